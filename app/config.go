@@ -3,6 +3,9 @@ package app
 type (
 	// StoreConfig is a config for store db.
 	StoreConfig struct {
+		// EVM state garbage collection mode ("full" or "archive"). Default is "archive".
+		GCMode string
+
 		// Cache size for Receipts.
 		ReceiptsCacheSize int
 		// Cache size for Stakers.
@@ -15,6 +18,7 @@ type (
 // DefaultStoreConfig for product.
 func DefaultStoreConfig() StoreConfig {
 	return StoreConfig{
+		GCMode:              GCModeArchive,
 		ReceiptsCacheSize:   100,
 		DelegatorsCacheSize: 4000,
 		StakersCacheSize:    4000,
@@ -24,6 +28,7 @@ func DefaultStoreConfig() StoreConfig {
 // LiteStoreConfig is for tests or inmemory.
 func LiteStoreConfig() StoreConfig {
 	return StoreConfig{
+		GCMode:              GCModeArchive,
 		ReceiptsCacheSize:   100,
 		DelegatorsCacheSize: 400,
 		StakersCacheSize:    400,
@@ -23,6 +23,8 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/topicsdb"
 )
 
+var evmLogsPrefix = []byte("L")
+
 // Store is a node persistent storage working over physical key-value database.
 type Store struct {
 	cfg StoreConfig
@@ -62,6 +64,8 @@ type Store struct {
 		StakerOldRewards           kvdb.KeyValueStore `table:"7"`
 		StakerDelegatorsOldRewards kvdb.KeyValueStore `table:"8"`
 
+		Version kvdb.KeyValueStore `table:"~"`
+
 		Evm      ethdb.Database
 		EvmState state.Database
 		EvmLogs  *topicsdb.Index
@@ -75,6 +79,11 @@ type Store struct {
 		BlockDowntime *lru.Cache `cache:"-"` // store by pointer
 	}
 
+	state struct {
+		recent []common.Hash // roots of the recent blocks, referenced in trie DB
+		last   common.Hash
+	}
+
 	mutex struct {
 		Inc sync.Mutex
 	}
@@ -99,12 +108,18 @@ func NewStore(dbs *flushable.SyncedPool, cfg StoreConfig) *Store {
 		Instance: logger.MakeInstance(),
 	}
 
+	switch cfg.GCMode {
+	case "", GCModeArchive, GCModeFull:
+	default:
+		s.Log.Crit("Unknown GC mode", "mode", cfg.GCMode)
+	}
+
 	table.MigrateTables(&s.table, s.mainDb)
 
 	evmTable := nokeyiserr.Wrap(table.New(s.mainDb, []byte("M"))) // ETH expects that "not found" is an error
 	s.table.Evm = rawdb.NewDatabase(evmTable)
 	s.table.EvmState = state.NewDatabaseWithCache(s.table.Evm, 16)
-	s.table.EvmLogs = topicsdb.New(table.New(s.mainDb, []byte("L")))
+	s.table.EvmLogs = topicsdb.New(table.New(s.mainDb, evmLogsPrefix))
 
 	s.initCache()
 
@@ -131,6 +146,35 @@ func (s *Store) Close() {
 	s.mainDb.Close()
 }
 
+// AddCheckpointedTables adds the tables, which are modified by the blocks applying, into the scope
+// of the poset checkpoints. EVM state isn't added, because the states of the recent blocks are kept in archive GC mode.
+func (s *Store) AddCheckpointedTables(scope flushable.UndoScope) {
+	scope.Add("gossip-main", table.Prefixes(
+		s.table.ActiveValidationScore,
+		s.table.DirtyValidationScore,
+		s.table.ActiveOriginationScore,
+		s.table.DirtyOriginationScore,
+		s.table.BlockDowntime,
+		s.table.StakerPOIScore,
+		s.table.AddressPOIScore,
+		s.table.AddressFee,
+		s.table.StakerDelegatorsFee,
+		s.table.AddressLastTxTime,
+		s.table.TotalPoiFee,
+		s.table.GasPowerRefund,
+		s.table.Validators,
+		s.table.Stakers,
+		s.table.Delegators,
+		s.table.SfcConstants,
+		s.table.TotalSupply,
+		s.table.Receipts,
+		s.table.DelegatorOldRewards,
+		s.table.StakerOldRewards,
+		s.table.StakerDelegatorsOldRewards,
+	)...)
+	scope.Add("gossip-main", evmLogsPrefix)
+}
+
 // Commit changes.
 func (s *Store) Commit(flushID []byte, immediately bool) error {
 	// TODO: enable s.dbs (uncomment all the code) when database versioning is ready
@@ -152,7 +196,7 @@ func (s *Store) Commit(flushID []byte, immediately bool) error {
 	*/
 
 	// Flush trie on the DB
-	err := s.table.EvmState.TrieDB().Cap(0)
+	err := s.commitTrie(immediately)
 	if err != nil {
 		s.Log.Error("Failed to flush trie DB into main DB", "err", err)
 	}
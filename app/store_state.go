@@ -0,0 +1,81 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// GCModeArchive keeps EVM states of all the blocks.
+	GCModeArchive = "archive"
+	// GCModeFull keeps EVM states of recent blocks only.
+	GCModeFull = "full"
+)
+
+const (
+	// triesInMemory is a number of recent blocks which states are kept in memory by full node.
+	triesInMemory = 128
+	// trieDirtyLimit is a memory limit of not committed trie nodes, before they are flushed into DB.
+	trieDirtyLimit = 256 * 1024 * 1024
+)
+
+// ErrStatePruned is returned if requested state is discarded by GC.
+var ErrStatePruned = errors.New("state pruned, it's available on archive node only (gcmode=archive)")
+
+func (s *Store) isArchive() bool {
+	return s.cfg.GCMode != GCModeFull
+}
+
+// CommitState writes the block state into trie DB, and returns the state root.
+// Full node keeps in memory states of the recent blocks only, older ones are garbage collected.
+func (s *Store) CommitState(statedb *state.StateDB) (common.Hash, error) {
+	root, err := statedb.Commit(true)
+	if err != nil || s.isArchive() {
+		return root, err
+	}
+
+	triedb := s.table.EvmState.TrieDB()
+	triedb.Reference(root, common.Hash{})
+	s.state.recent = append(s.state.recent, root)
+	s.state.last = root
+
+	if len(s.state.recent) > triesInMemory {
+		triedb.Dereference(s.state.recent[0])
+		s.state.recent = s.state.recent[1:]
+	}
+
+	if nodes, _ := triedb.Size(); nodes > trieDirtyLimit {
+		err = triedb.Cap(trieDirtyLimit - ethdb.IdealBatchSize)
+	}
+	return root, err
+}
+
+// commitTrie flushes trie DB into main DB.
+// Archive node flushes all the states, full node flushes only the last one if immediately.
+func (s *Store) commitTrie(immediately bool) error {
+	triedb := s.table.EvmState.TrieDB()
+	if s.isArchive() {
+		return triedb.Cap(0)
+	}
+	if !immediately || s.state.last == (common.Hash{}) {
+		return nil
+	}
+	return triedb.Commit(s.state.last, false)
+}
+
+// OpenStateDB returns state database, or ErrStatePruned if full node doesn't have the state root.
+// Other errors, including a missing root on archive node, are returned as is.
+func (s *Store) OpenStateDB(root common.Hash) (*state.StateDB, error) {
+	db, err := state.New(root, s.table.EvmState)
+	if err != nil {
+		if _, missing := err.(*trie.MissingNodeError); missing && !s.isArchive() {
+			return nil, ErrStatePruned
+		}
+		return nil, err
+	}
+	return db, nil
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestStoreStateGC(t *testing.T) {
+	logger.SetTestMode(t)
+
+	for _, mode := range []string{GCModeArchive, GCModeFull} {
+		t.Run(mode, func(t *testing.T) {
+			testStoreStateGC(t, mode)
+		})
+	}
+}
+
+func testStoreStateGC(t *testing.T, mode string) {
+	assertar := assert.New(t)
+
+	namespace := fmt.Sprintf("app.TestStoreStateGC-%s-%d", mode, rand.Int())
+	cfg := LiteStoreConfig()
+	cfg.GCMode = mode
+	dbs := flushable.NewSyncedPool(memorydb.NewProducer(namespace))
+	store := NewStore(dbs, cfg)
+
+	const blocks = triesInMemory * 2
+	roots := make([]common.Hash, blocks)
+	prev := common.Hash{}
+	for i := range roots {
+		statedb, err := store.OpenStateDB(prev)
+		if !assertar.NoError(err) {
+			return
+		}
+		statedb.SetBalance(common.BigToAddress(big.NewInt(int64(i))), big.NewInt(int64(i+1)))
+		roots[i], err = store.CommitState(statedb)
+		assertar.NoError(err)
+		assertar.NoError(store.Commit(nil, false))
+		prev = roots[i]
+	}
+
+	opened := func(root common.Hash) bool {
+		_, err := store.OpenStateDB(root)
+		if err != nil {
+			assertar.Equal(ErrStatePruned, err)
+		}
+		return err == nil
+	}
+	for i, root := range roots {
+		expect := mode == GCModeArchive || i >= blocks-triesInMemory
+		assertar.Equal(expect, opened(root), i)
+	}
+
+	// restart
+	assertar.NoError(store.Commit(nil, true))
+	assertar.NoError(dbs.Flush([]byte("1")))
+	dbs = flushable.NewSyncedPool(memorydb.NewProducer(namespace))
+	store = NewStore(dbs, cfg)
+
+	for i, root := range roots {
+		expect := mode == GCModeArchive || i == blocks-1
+		assertar.Equal(expect, opened(root), i)
+	}
+	statedb, err := store.OpenStateDB(roots[blocks-1])
+	if assertar.NoError(err) {
+		for i := range roots {
+			assertar.Equal(big.NewInt(int64(i+1)), statedb.GetBalance(common.BigToAddress(big.NewInt(int64(i)))))
+		}
+	}
+
+	// unknown root isn't reported as pruned by archive node
+	_, err = store.OpenStateDB(common.Hash{1})
+	if mode == GCModeArchive {
+		assertar.IsType(&trie.MissingNodeError{}, err)
+	} else {
+		assertar.Equal(ErrStatePruned, err)
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/kvdb/leveldb"
 )
 
+// Usage: db-check [events|stats] [datadir].
+// "events" (default) checks that all the event parents are stored,
+// "stats" reports key-space usage of each db table.
 func main() {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -14,8 +17,19 @@ func main() {
 	}
 	dir := filepath.Join(home, ".lachesis")
 
-	if len(os.Args) >= 2 {
-		dir = os.Args[1]
+	cmd := "events"
+	args := os.Args[1:]
+	if len(args) >= 1 && (args[0] == "events" || args[0] == "stats") {
+		cmd = args[0]
+		args = args[1:]
+	}
+	if len(args) >= 1 {
+		dir = args[0]
+	}
+
+	if cmd == "stats" {
+		checkStats(dir)
+		return
 	}
 
 	p := leveldb.NewProducer(dir)
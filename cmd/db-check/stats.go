@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/dbengine"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/keyspace"
+)
+
+const largestPairs = 3
+
+func checkStats(dir string) {
+	engines := dbengine.Detect(dir)
+	if len(engines) == 0 {
+		panic("no dbs found in " + dir)
+	}
+
+	schema := gossip.DbSchema()
+	for _, engine := range engines {
+		producer, err := dbengine.NewProducer(engine, dir)
+		if err != nil {
+			panic(err)
+		}
+
+		names := producer.Names()
+		sort.Strings(names)
+		for _, name := range names {
+			db := producer.OpenDb(name)
+			stats, err := keyspace.Collect(name, db, schema.Find(name), largestPairs)
+			_ = db.Close()
+			if err != nil {
+				panic(err)
+			}
+			printStats(engine, stats)
+		}
+	}
+}
+
+func printStats(engine string, stats *keyspace.DbStats) {
+	fmt.Printf("%s (%s)\n", stats.Db, engine)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  TABLE\tPREFIX\tKEYS\tKEYS SIZE\tVALUES SIZE\tTOTAL")
+	for _, t := range stats.Tables {
+		if t.Keys == 0 {
+			continue
+		}
+		name := t.Table
+		if name == "" {
+			name = "(unknown)"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%d\t%s\t%s\t%s\n", name, t.Prefix, t.Keys,
+			common.StorageSize(t.KeyBytes), common.StorageSize(t.ValueBytes), common.StorageSize(t.Size()))
+		for _, pair := range t.Largest {
+			fmt.Fprintf(w, "  \t\t\t\t\t%s\t%s\n", common.StorageSize(pair.Size), pair.Key)
+		}
+	}
+	_ = w.Flush()
+	fmt.Println()
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-lachesis/integration/simulator"
+	_ "github.com/Fantom-foundation/go-lachesis/version"
+)
+
+var (
+	// Git SHA1 commit hash of the release (set via linker flags).
+	gitCommit = ""
+	gitDate   = ""
+	// The app that holds all commands and flags.
+	app = utils.NewApp(gitCommit, gitDate, "the in-process network simulator")
+)
+
+var VerbosityFlag = cli.IntFlag{
+	Name:  "verbosity",
+	Usage: "sets the verbosity level of the nodes",
+	Value: 1,
+}
+
+var JSONFlag = cli.BoolFlag{
+	Name:  "json",
+	Usage: "print the report as JSON",
+}
+
+// init the CLI app.
+func init() {
+	app.Action = simulatorMain
+	app.Version = params.VersionWithCommit(gitCommit, gitDate)
+	app.ArgsUsage = "<scenario.json>"
+	app.Flags = append(app.Flags, VerbosityFlag, JSONFlag)
+}
+
+// Usage: lachesis-sim [--json] [--verbosity N] <scenario.json>.
+// See integration/simulator/testdata/partition.json for the scenario example.
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// simulatorMain runs the scenario and prints the report.
+func simulatorMain(ctx *cli.Context) error {
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(ctx.GlobalInt(VerbosityFlag.Name)))
+	log.Root().SetHandler(glogger)
+
+	args := ctx.Args()
+	if len(args) != 1 {
+		return fmt.Errorf("scenario file expected")
+	}
+	sc, err := simulator.LoadScenario(args[0])
+	if err != nil {
+		return err
+	}
+
+	report, err := simulator.Run(sc)
+	if err != nil {
+		return err
+	}
+
+	if ctx.GlobalBool(JSONFlag.Name) {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	fmt.Print(report.String())
+	return nil
+}
@@ -19,9 +19,11 @@ import (
 	"github.com/naoina/toml"
 	"gopkg.in/urfave/cli.v1"
 
+	lachesisapp "github.com/Fantom-foundation/go-lachesis/app"
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
 	"github.com/Fantom-foundation/go-lachesis/gossip"
 	"github.com/Fantom-foundation/go-lachesis/gossip/gasprice"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/dbengine"
 	"github.com/Fantom-foundation/go-lachesis/lachesis"
 )
 
@@ -48,6 +50,26 @@ var (
 		Value: big.NewInt(params.GWei),
 	}
 
+	// DBEngineFlag defines on-disk database engine
+	DBEngineFlag = cli.StringFlag{
+		Name:  "db.engine",
+		Usage: "Database engine of the datadir (leveldb, badger)",
+		Value: dbengine.LevelDb,
+	}
+
+	// DBReadOnlyFlag opens databases in read-only mode
+	DBReadOnlyFlag = cli.BoolFlag{
+		Name:  "db.readonly",
+		Usage: "Open databases in read-only mode to serve API only (disables emitter, tx pool and p2p)",
+	}
+
+	// GCModeFlag defines EVM state garbage collection mode
+	GCModeFlag = cli.StringFlag{
+		Name:  "gcmode",
+		Usage: `EVM state garbage collection mode ("full", "archive")`,
+		Value: lachesisapp.GCModeArchive,
+	}
+
 	// DataDirFlag defines directory to store Lachesis state and user's wallets
 	DataDirFlag = utils.DirectoryFlag{
 		Name:  "datadir",
@@ -196,6 +218,21 @@ func gossipConfigWithFlags(ctx *cli.Context, src gossip.Config) gossip.Config {
 	setGPO(ctx, &cfg.GPO)
 	setTxPool(ctx, &cfg.TxPool)
 
+	if ctx.GlobalIsSet(DBEngineFlag.Name) {
+		cfg.DBEngine = ctx.GlobalString(DBEngineFlag.Name)
+	}
+
+	if ctx.GlobalIsSet(DBReadOnlyFlag.Name) {
+		cfg.ReadOnly = ctx.GlobalBool(DBReadOnlyFlag.Name)
+	}
+
+	if ctx.GlobalIsSet(GCModeFlag.Name) {
+		cfg.GCMode = ctx.GlobalString(GCModeFlag.Name)
+		if cfg.GCMode != lachesisapp.GCModeFull && cfg.GCMode != lachesisapp.GCModeArchive {
+			utils.Fatalf("--%s must be either '%s' or '%s'", GCModeFlag.Name, lachesisapp.GCModeFull, lachesisapp.GCModeArchive)
+		}
+	}
+
 	if ctx.GlobalIsSet(utils.NetworkIdFlag.Name) {
 		cfg.Net.NetworkID = ctx.GlobalUint64(utils.NetworkIdFlag.Name)
 	}
@@ -250,6 +287,14 @@ func makeAllConfigs(ctx *cli.Context) config {
 	cfg.Lachesis = gossipConfigWithFlags(ctx, cfg.Lachesis)
 	cfg.Node = nodeConfigWithFlags(ctx, cfg.Node)
 
+	if cfg.Lachesis.ReadOnly {
+		// no peers, so no events and txs are received
+		cfg.Node.P2P.MaxPeers = 0
+		cfg.Node.P2P.NoDiscovery = true
+		cfg.Node.P2P.DiscoveryV5 = false
+		cfg.Node.P2P.ListenAddr = ""
+	}
+
 	return cfg
 }
 
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip/snapshot"
+	"github.com/Fantom-foundation/go-lachesis/integration"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+var (
+	dryRunFlag = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Report pending migrations without applying them",
+	}
+	toBlockFlag = cli.Uint64Flag{
+		Name:  "to-block",
+		Usage: "Block to roll back to",
+	}
+
+	dbCommand = cli.Command{
+		Name:     "db",
+		Usage:    "Manage node databases",
+		Category: "DATABASE COMMANDS",
+		Description: `
+
+Make a consistent backup of the running node databases, restore it, migrate the databases,
+rebuild the consensus state, and roll back the recent blocks.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "snapshot",
+				Usage:     "Copy databases of the running node into the empty dir",
+				ArgsUsage: "<dir>",
+				Action:    utils.MigrateFlags(dbSnapshot),
+				Flags:     append(nodeFlags, testFlags...),
+				Description: `
+    lachesis db snapshot <dir>
+
+Connects to the running node (IPC endpoint of the datadir) and makes a consistent
+copy of all the node databases into the dir, together with a manifest file.
+Events processing is paused until the copy is done.`,
+			},
+			{
+				Name:      "restore",
+				Usage:     "Restore databases from the snapshot dir",
+				ArgsUsage: "<dir>",
+				Action:    utils.MigrateFlags(dbRestore),
+				Flags:     append(nodeFlags, testFlags...),
+				Description: `
+    lachesis db restore <dir>
+
+Validates the snapshot against its manifest and copies the databases into
+the datadir. The node must be stopped, and the datadir must have no databases.`,
+			},
+			{
+				Name:   "migrate",
+				Usage:  "Apply pending migrations of the databases",
+				Action: utils.MigrateFlags(dbMigrate),
+				Flags:  append(append(nodeFlags, testFlags...), dryRunFlag),
+				Description: `
+    lachesis db migrate [--dry-run]
+
+Applies not applied yet migrations of the node databases, the same way as it's done
+at the node start. With --dry-run, only reports the pending migrations without changing
+the data. The node must be stopped.`,
+			},
+			{
+				Name:   "reindex-consensus",
+				Usage:  "Rebuild the consensus state from the stored events",
+				Action: utils.MigrateFlags(dbReindexConsensus),
+				Flags:  append(nodeFlags, testFlags...),
+				Description: `
+    lachesis db reindex-consensus
+
+Drops the poset and vector clock databases, and replays the stored events epoch by epoch
+to rebuild them. Atropos, events, time and previous hash of each decided block are checked
+against the stored block, the first divergence is reported. Transactions aren't re-executed,
+so state roots of the blocks aren't verified, only the state of the last block is checked
+to exist (use "lachesis db rollback" to re-execute the blocks). It's an alternative to the
+resync from the network, when the consensus databases are corrupted. The node must be stopped.`,
+			},
+			{
+				Name:   "rollback",
+				Usage:  "Roll back the recent blocks and re-execute them",
+				Action: utils.MigrateFlags(dbRollback),
+				Flags:  append(append(nodeFlags, testFlags...), toBlockFlag),
+				Description: `
+    lachesis db rollback --to-block N
+
+Rewinds the poset, gossip and app databases to the checkpoint of block N, and re-executes
+the subsequent blocks from the stored events. Only the last blocks of the current epoch
+may be rolled back, as many as are kept in the checkpoint history (see CheckpointHistory
+in the config file, the history is disabled by default). EVM states of the blocks are required, so the node must run in archive
+GC mode. The node must be stopped.`,
+			},
+		},
+	}
+)
+
+func dbSnapshot(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	dir, err := filepath.Abs(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Invalid dir: %v", err)
+	}
+
+	cfg := makeAllConfigs(ctx)
+	client, err := dialRPC(cfg.Node.IPCEndpoint())
+	if err != nil {
+		utils.Fatalf("Unable to attach to running lachesis: %v", err)
+	}
+	defer client.Close()
+
+	var m snapshot.Manifest
+	err = client.Call(&m, "debug_snapshotDb", dir)
+	if err != nil {
+		utils.Fatalf("Failed to make snapshot: %v", err)
+	}
+
+	fmt.Printf("Snapshot of epoch %d, block %d is written into %s\n", m.Epoch, m.Block, dir)
+	return nil
+}
+
+func dbRestore(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	dir := ctx.Args().First()
+
+	cfg := makeAllConfigs(ctx)
+	m, err := snapshot.Restore(dir, cfg.Node.DataDir)
+	if err != nil {
+		utils.Fatalf("Failed to restore snapshot: %v", err)
+	}
+
+	fmt.Printf("Snapshot of epoch %d, block %d is restored into %s\n", m.Epoch, m.Block, cfg.Node.DataDir)
+	return nil
+}
+
+func dbMigrate(ctx *cli.Context) error {
+	cfg := makeAllConfigs(ctx)
+
+	if !ctx.Bool(dryRunFlag.Name) {
+		err := integration.Migrate(cfg.Node.DataDir, &cfg.Lachesis)
+		if err != nil {
+			utils.Fatalf("Failed to migrate DB: %v", err)
+		}
+		fmt.Println("All the migrations are applied")
+		return nil
+	}
+
+	pending, err := integration.PendingMigrations(cfg.Node.DataDir, &cfg.Lachesis)
+	if err != nil {
+		utils.Fatalf("Failed to read DB versions: %v", err)
+	}
+	if len(pending) == 0 {
+		fmt.Println("No pending migrations")
+		return nil
+	}
+
+	stores := make([]string, 0, len(pending))
+	for store := range pending {
+		stores = append(stores, store)
+	}
+	sort.Strings(stores)
+	for _, store := range stores {
+		fmt.Printf("%s:\n", store)
+		for _, name := range pending[store] {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+	return nil
+}
+
+func dbReindexConsensus(ctx *cli.Context) error {
+	cfg := makeAllConfigs(ctx)
+
+	block, err := integration.ReindexConsensus(cfg.Node.DataDir, &cfg.Lachesis)
+	if err != nil {
+		utils.Fatalf("Failed to reindex consensus (last replayed block is %d): %v", block, err)
+	}
+
+	fmt.Printf("Consensus state is rebuilt up to block %d\n", block)
+	return nil
+}
+
+func dbRollback(ctx *cli.Context) error {
+	if !ctx.IsSet(toBlockFlag.Name) {
+		utils.Fatalf("--%s is required", toBlockFlag.Name)
+	}
+	to := idx.Block(ctx.Uint64(toBlockFlag.Name))
+
+	cfg := makeAllConfigs(ctx)
+
+	block, err := integration.Rollback(cfg.Node.DataDir, &cfg.Lachesis, to)
+	if err != nil {
+		utils.Fatalf("Failed to roll back to block %d: %v", to, err)
+	}
+
+	fmt.Printf("Rolled back to block %d, re-executed up to block %d\n", to, block)
+	return nil
+}
@@ -64,6 +64,9 @@ func init() {
 		utils.BootnodesV4Flag,
 		utils.BootnodesV5Flag,
 		DataDirFlag,
+		DBEngineFlag,
+		DBReadOnlyFlag,
+		GCModeFlag,
 		utils.KeyStoreDirFlag,
 		utils.ExternalSignerFlag,
 		utils.NoUSBFlag,
@@ -159,6 +162,8 @@ func init() {
 		javascriptCommand,
 		// See config.go:
 		dumpConfigCommand,
+		// See dbcmd.go:
+		dbCommand,
 		// See misccmd.go:
 		versionCommand,
 		licenseCommand,
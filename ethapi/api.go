@@ -1023,6 +1023,7 @@ func RPCMarshalEventHeader(header *inter.EventHeaderData) map[string]interface{}
 		"claimedTime":      header.ClaimedTime,
 		"medianTime":       header.MedianTime,
 		"extraData":        hexutil.Bytes(header.Extra),
+		"extra":            rpcMarshalExtra(header.Extra),
 		"transactionsRoot": hexutil.Bytes(header.TxHash.Bytes()),
 		"gasPowerLeft": map[string]interface{}{
 			"shortTerm": header.GasPowerLeft.Gas[idx.ShortTermGas],
@@ -1064,6 +1065,40 @@ func RPCMarshalEvent(event *inter.Event, inclTx bool, fullTx bool) (map[string]i
 	return fields, nil
 }
 
+// rpcMarshalExtra converts typed Extra to the RPC output. It returns nil if Extra isn't typed or malformed.
+func rpcMarshalExtra(raw []byte) map[string]interface{} {
+	x, err := inter.DecodeExtra(raw)
+	if err != nil {
+		return nil
+	}
+	fields := map[string]interface{}{
+		"schemaVersion": x.SchemaVersion,
+	}
+	if len(x.NodeVersion) != 0 {
+		fields["nodeVersion"] = x.NodeVersion
+	}
+	if x.GasPriceHint != nil {
+		fields["gasPriceHint"] = (*hexutil.Big)(x.GasPriceHint)
+	}
+	if len(x.ValidatorAnnounce) != 0 {
+		fields["validatorAnnounce"] = hexutil.Bytes(x.ValidatorAnnounce)
+	}
+	encodeRecords := func(records map[inter.ExtraType][]byte) map[string]hexutil.Bytes {
+		res := make(map[string]hexutil.Bytes, len(records))
+		for t, value := range records {
+			res[hexutil.EncodeUint64(uint64(t))] = value
+		}
+		return res
+	}
+	if len(x.App) != 0 {
+		fields["app"] = encodeRecords(x.App)
+	}
+	if len(x.Unknown) != 0 {
+		fields["unknown"] = encodeRecords(x.Unknown)
+	}
+	return fields
+}
+
 func eventIDsToHex(ids hash.Events) []hexutil.Bytes {
 	res := make([]hexutil.Bytes, len(ids))
 	for i, id := range ids {
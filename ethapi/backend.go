@@ -33,11 +33,13 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
+	"github.com/Fantom-foundation/go-lachesis/finality"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/inter/pos"
 	"github.com/Fantom-foundation/go-lachesis/inter/sfctype"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 )
 
 // PeerProgress is synchronization status of a peer
@@ -96,6 +98,10 @@ type Backend interface {
 	TtfReport(ctx context.Context, untilBlock rpc.BlockNumber, maxBlocks idx.Block, mode string) (map[hash.Event]time.Duration, error)
 	ForEachEvent(ctx context.Context, epoch rpc.BlockNumber, onEvent func(event *inter.Event) bool) error
 	ValidatorTimeDrifts(ctx context.Context, epoch rpc.BlockNumber, maxEvents idx.Event) (map[idx.StakerID]map[hash.Event]time.Duration, error)
+	GetBlockProof(ctx context.Context, number rpc.BlockNumber) (*finality.Proof, error)
+	GetEpochTransition(ctx context.Context, epoch rpc.BlockNumber) (*finality.EpochTransition, error)
+	GetElectionTrace(ctx context.Context, epoch rpc.BlockNumber, frame idx.Frame) (*election.Trace, error)
+	GetForkEvidence(ctx context.Context, stakerID idx.StakerID, epoch rpc.BlockNumber) (*inter.ForkEvidence, error)
 
 	// Lachesis SFC API
 	GetValidators(ctx context.Context) *pos.Validators
@@ -132,6 +138,11 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicDAGChainAPI(apiBackend),
 			Public:    true,
+		}, {
+			Namespace: "dag",
+			Version:   "1.0",
+			Service:   NewPublicDAGProofAPI(apiBackend),
+			Public:    true,
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
@@ -1,7 +1,6 @@
 package ethapi
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -9,11 +8,14 @@ import (
 
 	"github.com/beorn7/perks/histogram"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 
+	"github.com/Fantom-foundation/go-lachesis/finality"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 )
 
 // PublicDAGChainAPI provides an API to access the directed acyclic graph chain.
@@ -92,9 +94,189 @@ func (s *PublicDAGChainAPI) GetEpochStats(ctx context.Context, requestedEpoch rp
 		"totalFee":              (*hexutil.Big)(stats.TotalFee),
 		"totalBaseRewardWeight": (*hexutil.Big)(stats.TotalBaseRewardWeight),
 		"totalTxRewardWeight":   (*hexutil.Big)(stats.TotalTxRewardWeight),
+		"forkEvidences":         stats.ForkEvidences,
 	}, nil
 }
 
+// PublicDAGProofAPI provides an API to get the proofs of the DAG consensus decisions,
+// which are verifiable by a third party without the DAG.
+type PublicDAGProofAPI struct {
+	b Backend
+}
+
+// NewPublicDAGProofAPI creates a new DAG proofs API.
+func NewPublicDAGProofAPI(b Backend) *PublicDAGProofAPI {
+	return &PublicDAGProofAPI{b}
+}
+
+// GetBlockProof returns finality proof of the block, which is verifiable by finality.Verify
+// with the validators and the first block of the epoch.
+// The "rlp" field is the RLP-encoded proof.
+func (s *PublicDAGProofAPI) GetBlockProof(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error) {
+	proof, err := s.b.GetBlockProof(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+	return RPCMarshalBlockProof(proof)
+}
+
+// RPCMarshalBlockProof converts the given finality proof to the RPC output.
+func RPCMarshalBlockProof(proof *finality.Proof) (map[string]interface{}, error) {
+	raw, err := rlp.EncodeToBytes(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make([]map[string]interface{}, len(proof.Headers))
+	for i, h := range proof.Headers {
+		headers[i] = RPCMarshalEventHeader(&h.EventHeaderData)
+		headers[i]["sig"] = hexutil.Bytes(h.Sig)
+	}
+
+	return map[string]interface{}{
+		"block":      hexutil.Uint64(proof.Block),
+		"blockHash":  proof.BlockHash(),
+		"atropos":    hexutil.Bytes(proof.Atropos.Bytes()),
+		"validators": rpcMarshalValidators(proof.Validators),
+		"headers":    headers,
+		"rlp":        hexutil.Bytes(raw),
+	}, nil
+}
+
+// GetEpochTransition returns validators group transition to the epoch, which is verifiable by
+// finality.VerifyTransition with the previous epoch validators and its first block.
+// * When epoch is -2 or -1 the transition to the current epoch is returned.
+// The "rlp" field is the RLP-encoded transition.
+func (s *PublicDAGProofAPI) GetEpochTransition(ctx context.Context, epoch rpc.BlockNumber) (map[string]interface{}, error) {
+	t, err := s.b.GetEpochTransition(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, fmt.Errorf("epoch %d transition not found", epoch)
+	}
+	return RPCMarshalEpochTransition(t)
+}
+
+// GetCheaterEvidence returns the pair of conflicting signed event headers of the cheater in the epoch,
+// which is verifiable by inter.ForkEvidence.Verify with the cheater's address.
+// * When epoch is -2 or -1 the current epoch is used.
+// The "hash" field is the hash of the evidence, as in the epoch stats.
+func (s *PublicDAGProofAPI) GetCheaterEvidence(ctx context.Context, stakerID hexutil.Uint64, epoch rpc.BlockNumber) (map[string]interface{}, error) {
+	ev, err := s.b.GetForkEvidence(ctx, idx.StakerID(stakerID), epoch)
+	if err != nil {
+		return nil, err
+	}
+	if ev == nil {
+		return nil, fmt.Errorf("staker %d has no fork evidence in epoch %d", stakerID, epoch)
+	}
+	return RPCMarshalForkEvidence(ev)
+}
+
+// RPCMarshalForkEvidence converts the given fork evidence to the RPC output.
+func RPCMarshalForkEvidence(ev *inter.ForkEvidence) (map[string]interface{}, error) {
+	raw, err := rlp.EncodeToBytes(ev)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]map[string]interface{}, 2)
+	for i, h := range []*inter.EventHeader{&ev.A, &ev.B} {
+		events[i] = RPCMarshalEventHeader(&h.EventHeaderData)
+		events[i]["sig"] = hexutil.Bytes(h.Sig)
+	}
+
+	return map[string]interface{}{
+		"hash":   ev.Hash(),
+		"events": events,
+		"rlp":    hexutil.Bytes(raw),
+	}, nil
+}
+
+// RPCMarshalEpochTransition converts the given validators group transition to the RPC output.
+func RPCMarshalEpochTransition(t *finality.EpochTransition) (map[string]interface{}, error) {
+	raw, err := rlp.EncodeToBytes(t)
+	if err != nil {
+		return nil, err
+	}
+
+	var proof map[string]interface{}
+	if t.Proof != nil {
+		proof, err = RPCMarshalBlockProof(t.Proof)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]interface{}{
+		"epoch":              hexutil.Uint64(t.Epoch),
+		"prevValidatorsHash": t.PrevValidatorsHash,
+		"validators":         rpcMarshalValidators(t.Validators),
+		"sealingBlock":       hexutil.Uint64(t.SealingBlock),
+		"proof":              proof,
+		"rlp":                hexutil.Bytes(raw),
+	}, nil
+}
+
+func rpcMarshalValidators(vv []finality.Validator) []map[string]interface{} {
+	validators := make([]map[string]interface{}, len(vv))
+	for i, v := range vv {
+		validators[i] = map[string]interface{}{
+			"id":      hexutil.Uint64(v.ID),
+			"stake":   hexutil.Uint64(v.Stake),
+			"address": v.Address,
+		}
+	}
+	return validators
+}
+
+// GetElectionTrace returns election trace of the decided frame, to diagnose why it took many rounds to decide.
+// Only traces of the current epoch are kept, if ElectionTraces are enabled.
+// * When epoch is -2 or -1 the current epoch is used.
+// Format. String. One of {"json", "dot"}, "dot" returns the trace as a graph in DOT format.
+func (s *PublicDebugAPI) GetElectionTrace(ctx context.Context, epoch rpc.BlockNumber, frame hexutil.Uint64, format string) (interface{}, error) {
+	if format != "json" && format != "dot" {
+		return nil, errors.New("format must be one of {json, dot}")
+	}
+
+	t, err := s.b.GetElectionTrace(ctx, epoch, idx.Frame(frame))
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, fmt.Errorf("frame %d election trace not found", frame)
+	}
+	if format == "dot" {
+		return t.DOT(), nil
+	}
+	return RPCMarshalElectionTrace(t), nil
+}
+
+// RPCMarshalElectionTrace converts the given election trace to the RPC output.
+func RPCMarshalElectionTrace(t *election.Trace) map[string]interface{} {
+	votes := make([]map[string]interface{}, len(t.Votes))
+	for i, v := range t.Votes {
+		votes[i] = map[string]interface{}{
+			"fromRoot":     eventIDToHex(v.FromRoot),
+			"fromFrame":    hexutil.Uint64(v.FromFrame),
+			"forValidator": hexutil.Uint64(v.ForValidator),
+			"yes":          v.Yes,
+			"decided":      v.Decided,
+			"observedRoot": eventIDToHex(v.ObservedRoot),
+		}
+	}
+
+	return map[string]interface{}{
+		"frame":   hexutil.Uint64(t.Frame),
+		"atropos": eventIDToHex(t.Atropos),
+		"rounds":  hexutil.Uint64(t.Rounds()),
+		"votes":   votes,
+	}
+}
+
 func durationToRPC(t time.Duration) string {
 	/*if t < 0 {
 		t = -t
@@ -183,13 +365,11 @@ func (s *PublicDebugAPI) TtfReport(ctx context.Context, untilBlock rpc.BlockNumb
 // If validator didn't have an event in beginning of epoch, then it will not be listed.
 func (s *PublicDebugAPI) ValidatorVersions(ctx context.Context, epoch rpc.BlockNumber, maxEvents hexutil.Uint64) (map[hexutil.Uint64]string, error) {
 	processed := 0
-	prefix := []byte("v-")
 	versions := map[hexutil.Uint64]string{}
 
 	err := s.b.ForEachEvent(ctx, epoch, func(event *inter.Event) bool {
 		creator := hexutil.Uint64(event.Creator)
-		if bytes.HasPrefix(event.Extra, prefix) {
-			version := string(event.Extra[len(prefix):])
+		if version, ok := inter.ExtraNodeVersionOf(event.Extra); ok {
 			versions[creator] = version
 		} else if _, ok := versions[creator]; !ok {
 			versions[creator] = "not found"
@@ -16,26 +16,59 @@ type Checkers struct {
 	Parentscheck  *parentscheck.Checker
 	Gaspowercheck *gaspowercheck.Checker
 	Heavycheck    *heavycheck.Checker
+
+	// custom rules
+	Rules Rules
 }
 
-// Validate runs all the checks except Poset-related. intended only for tests
-func (v *Checkers) Validate(e *inter.Event, parents []*inter.EventHeaderData) error {
+// AddRules appends the custom rules to the checks. It must be called before Heavycheck is started.
+func (v *Checkers) AddRules(rules ...Rule) {
+	for _, r := range rules {
+		v.Rules.Add(r)
+		if r.Stage == HeavyStage {
+			rule := r
+			v.Heavycheck.AddRule(func(e *inter.Event) error {
+				return rule.validate(e, nil)
+			})
+		}
+	}
+}
+
+// ValidateLight runs the checks which require only the event, except the heavy ones.
+func (v *Checkers) ValidateLight(e *inter.Event) error {
 	if err := v.Basiccheck.Validate(e); err != nil {
 		return err
 	}
 	if err := v.Epochcheck.Validate(e); err != nil {
 		return err
 	}
-	if err := v.Parentscheck.Validate(e, parents); err != nil {
-		return err
-	}
+	return v.Rules.ValidateStage(LightStage, e, nil)
+}
+
+// ValidateParents runs the checks which require the event's parents.
+func (v *Checkers) ValidateParents(e *inter.Event, parents []*inter.EventHeaderData) error {
 	var selfParent *inter.EventHeaderData
 	if e.SelfParent() != nil {
 		selfParent = parents[0]
 	}
+	if err := v.Parentscheck.Validate(e, parents); err != nil {
+		return err
+	}
 	if err := v.Gaspowercheck.Validate(e, selfParent); err != nil {
 		return err
 	}
+	return v.Rules.ValidateStage(ParentsStage, e, parents)
+}
+
+// Validate runs all the checks except Poset-related. intended only for tests
+func (v *Checkers) Validate(e *inter.Event, parents []*inter.EventHeaderData) error {
+	if err := v.ValidateLight(e); err != nil {
+		return err
+	}
+	if err := v.ValidateParents(e, parents); err != nil {
+		return err
+	}
+	// heavy custom rules are run by Heavycheck
 	if err := v.Heavycheck.Validate(e); err != nil {
 		return err
 	}
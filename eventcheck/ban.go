@@ -10,7 +10,12 @@ var (
 	ErrAlreadyConnectedEvent = errors.New("event is connected already")
 )
 
+// IsBan returns true if the event, which has failed the checks with the error, is a reason to punish the peer.
+// Errors may decide it themselves by implementing IsBan() bool.
 func IsBan(err error) bool {
+	if banErr, ok := err.(interface{ IsBan() bool }); ok {
+		return banErr.IsBan()
+	}
 	if err == epochcheck.ErrNotRelevant ||
 		err == ErrAlreadyConnectedEvent {
 		return false
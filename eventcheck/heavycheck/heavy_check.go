@@ -7,11 +7,13 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/Fantom-foundation/go-lachesis/eventcheck/epochcheck"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/utils/sigcache"
 )
 
 var (
@@ -23,13 +25,17 @@ var (
 )
 
 const (
-	maxQueuedTasks = 128 // the maximum number of events to queue up
-	maxBatch       = 4   // Maximum number of events in an task batch (batch is divided if exceeded)
+	maxQueuedTasks = 128 // the maximum number of tasks to queue up
+	minBatch       = 4   // Minimum number of events in a task batch, unless there are less events
+	maxBatch       = 64  // Maximum number of events in a task batch (batch is divided if exceeded)
 )
 
 // OnValidatedFn is a callback type for notifying about validation result.
 type OnValidatedFn func(*TaskData)
 
+// Rule is an additional heavy check, which is run after the built-in ones.
+type Rule func(*inter.Event) error
+
 // DagReader is accessed by the validator to get the current state.
 type DagReader interface {
 	GetEpochPubKeys() (map[idx.StakerID]common.Address, idx.Epoch)
@@ -40,6 +46,8 @@ type Checker struct {
 	config   *lachesis.DagConfig
 	txSigner types.Signer
 	reader   DagReader
+	sigs     *sigcache.Cache
+	rules    []Rule
 
 	numOfThreads int
 
@@ -56,7 +64,7 @@ type TaskData struct {
 }
 
 // NewDefault uses N-1 threads
-func NewDefault(config *lachesis.DagConfig, reader DagReader, txSigner types.Signer) *Checker {
+func NewDefault(config *lachesis.DagConfig, reader DagReader, txSigner types.Signer, sigs *sigcache.Cache) *Checker {
 	threads := runtime.NumCPU()
 	if threads > 1 {
 		threads--
@@ -64,21 +72,28 @@ func NewDefault(config *lachesis.DagConfig, reader DagReader, txSigner types.Sig
 	if threads < 1 {
 		threads = 1
 	}
-	return New(config, reader, txSigner, threads)
+	return New(config, reader, txSigner, sigs, threads)
 }
 
-// New validator which performs heavy checks, related to signatures validation and Merkle tree validation
-func New(config *lachesis.DagConfig, reader DagReader, txSigner types.Signer, numOfThreads int) *Checker {
+// New validator which performs heavy checks, related to signatures validation and Merkle tree validation.
+// Verified event signatures are cached in sigs, txSigner should share the same cache to cache tx signatures.
+func New(config *lachesis.DagConfig, reader DagReader, txSigner types.Signer, sigs *sigcache.Cache, numOfThreads int) *Checker {
 	return &Checker{
 		config:       config,
 		txSigner:     txSigner,
 		reader:       reader,
+		sigs:         sigs,
 		numOfThreads: numOfThreads,
 		tasksQ:       make(chan *TaskData, maxQueuedTasks),
 		quit:         make(chan struct{}),
 	}
 }
 
+// AddRule appends the additional check. It must be called before Start.
+func (v *Checker) AddRule(rule Rule) {
+	v.rules = append(v.rules, rule)
+}
+
 func (v *Checker) Start() {
 	for i := 0; i < v.numOfThreads; i++ {
 		v.wg.Add(1)
@@ -95,12 +110,25 @@ func (v *Checker) Overloaded() bool {
 	return len(v.tasksQ) > maxQueuedTasks/2
 }
 
+// batchSize divides the events evenly between the threads, within the batch limits.
+func (v *Checker) batchSize(num int) int {
+	size := (num + v.numOfThreads - 1) / v.numOfThreads
+	if size < minBatch {
+		size = minBatch
+	}
+	if size > maxBatch {
+		size = maxBatch
+	}
+	return size
+}
+
 func (v *Checker) Enqueue(events inter.Events, onValidated OnValidatedFn) error {
 	// divide big batch into smaller ones
-	for start := 0; start < len(events); start += maxBatch {
+	batch := v.batchSize(len(events))
+	for start := 0; start < len(events); start += batch {
 		end := len(events)
-		if end > start+maxBatch {
-			end = start + maxBatch
+		if end > start+batch {
+			end = start + batch
 		}
 		op := &TaskData{
 			Events:      events[start:end],
@@ -128,7 +156,7 @@ func (v *Checker) Validate(e *inter.Event) error {
 		return epochcheck.ErrAuth
 	}
 	// event sig
-	if !e.VerifySignature(addr) {
+	if !v.verifySignature(e, addr) {
 		return ErrWrongEventSig
 	}
 	// pre-cache tx sig
@@ -142,10 +170,25 @@ func (v *Checker) Validate(e *inter.Event) error {
 	if e.TxHash != types.DeriveSha(e.Transactions) {
 		return ErrWrongTxHash
 	}
+	// additional rules
+	for _, rule := range v.rules {
+		if err := rule(e); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// verifySignature checks the event signature against the address, same as inter.EventHeader.VerifySignature,
+// but the verified signatures are cached.
+func (v *Checker) verifySignature(e *inter.Event, addr common.Address) bool {
+	// NOTE: Keccak256 because of AccountManager
+	signedHash := crypto.Keccak256Hash(e.DataToSign())
+	signer, err := v.sigs.Recover(signedHash, e.Sig)
+	return err == nil && signer == addr
+}
+
 func (v *Checker) loop() {
 	defer v.wg.Done()
 	for {
@@ -0,0 +1,285 @@
+package heavycheck
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/eventcheck/epochcheck"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+	"github.com/Fantom-foundation/go-lachesis/utils/sigcache"
+)
+
+var testChainID = big.NewInt(1)
+
+type testReader struct {
+	addrs map[idx.StakerID]common.Address
+	epoch idx.Epoch
+}
+
+func (r *testReader) GetEpochPubKeys() (map[idx.StakerID]common.Address, idx.Epoch) {
+	return r.addrs, r.epoch
+}
+
+// testNet is a set of validators with their keys, which emit events full of txs.
+type testNet struct {
+	reader  *testReader
+	keys    map[idx.StakerID]*ecdsa.PrivateKey
+	txKey   *ecdsa.PrivateKey
+	txNonce uint64
+}
+
+func newTestNet(validators int) *testNet {
+	net := &testNet{
+		reader: &testReader{
+			addrs: make(map[idx.StakerID]common.Address),
+			epoch: 1,
+		},
+		keys: make(map[idx.StakerID]*ecdsa.PrivateKey),
+	}
+	for i := 1; i <= validators; i++ {
+		key, _ := crypto.GenerateKey()
+		net.keys[idx.StakerID(i)] = key
+		net.reader.addrs[idx.StakerID(i)] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	net.txKey, _ = crypto.GenerateKey()
+	return net
+}
+
+func (net *testNet) makeTxs(num int) types.Transactions {
+	signer := types.NewEIP155Signer(testChainID)
+	txs := make(types.Transactions, num)
+	for i := range txs {
+		tx := types.NewTransaction(net.txNonce, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		net.txNonce++
+		var err error
+		txs[i], err = types.SignTx(tx, signer, net.txKey)
+		if err != nil {
+			panic(err)
+		}
+	}
+	return txs
+}
+
+func (net *testNet) makeEvent(creator idx.StakerID, seq idx.Event, txs types.Transactions) *inter.Event {
+	e := inter.NewEvent()
+	e.Epoch = net.reader.epoch
+	e.Seq = seq
+	e.Creator = creator
+	e.Lamport = idx.Lamport(seq)
+	e.Transactions = txs
+	e.TxHash = types.DeriveSha(txs)
+	if err := e.SignBy(net.keys[creator]); err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// relay returns a fresh copy of the events, as if they are received from another peer.
+func relay(events inter.Events) inter.Events {
+	res := make(inter.Events, len(events))
+	for i, e := range events {
+		raw, err := rlp.EncodeToBytes(e)
+		if err != nil {
+			panic(err)
+		}
+		res[i] = new(inter.Event)
+		if err := rlp.DecodeBytes(raw, res[i]); err != nil {
+			panic(err)
+		}
+	}
+	return res
+}
+
+func newTestChecker(net *testNet, sigs *sigcache.Cache, threads int) *Checker {
+	txSigner := sigcache.NewSigner(types.NewEIP155Signer(testChainID), sigs)
+	return New(&lachesis.DagConfig{}, net.reader, txSigner, sigs, threads)
+}
+
+func TestHeavyCheck(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	net := newTestNet(2)
+	sigs := sigcache.New(1024)
+	checker := newTestChecker(net, sigs, 2)
+
+	e := net.makeEvent(1, 1, net.makeTxs(3))
+	assertar.NoError(checker.Validate(relay(inter.Events{e})[0]))
+	assertar.Equal(1+3, sigs.Len())
+	// cached signatures are valid only for their signers
+	assertar.NoError(checker.Validate(relay(inter.Events{e})[0]))
+	wrong := relay(inter.Events{e})[0]
+	wrong.Creator = 2
+	assertar.Equal(ErrWrongEventSig, checker.Validate(wrong))
+	wrong.Creator = 3
+	assertar.Equal(epochcheck.ErrAuth, checker.Validate(wrong))
+	wrong = relay(inter.Events{e})[0]
+	wrong.Epoch = 2
+	assertar.Equal(epochcheck.ErrNotRelevant, checker.Validate(wrong))
+
+	// malformed tx sig
+	badTx := types.NewTransaction(0, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	e = net.makeEvent(2, 1, types.Transactions{badTx})
+	assertar.Equal(ErrMalformedTxSig, checker.Validate(relay(inter.Events{e})[0]))
+
+	// wrong tx hash
+	e = net.makeEvent(2, 2, net.makeTxs(1))
+	e.TxHash = common.Hash{1}
+	assertar.NoError(e.SignBy(net.keys[2]))
+	assertar.Equal(ErrWrongTxHash, checker.Validate(relay(inter.Events{e})[0]))
+
+	// batches
+	checker.Start()
+	defer checker.Stop()
+	events := make(inter.Events, 0, 100)
+	for i := 1; i <= cap(events); i++ {
+		events = append(events, net.makeEvent(1, idx.Event(i), nil))
+	}
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		checked = make(map[*inter.Event]error)
+	)
+	wg.Add((len(events) + checker.batchSize(len(events)) - 1) / checker.batchSize(len(events)))
+	assertar.NoError(checker.Enqueue(events, func(task *TaskData) {
+		mu.Lock()
+		defer mu.Unlock()
+		for i, e := range task.Events {
+			checked[e] = task.Result[i]
+		}
+		wg.Done()
+	}))
+	wg.Wait()
+	assertar.Equal(len(events), len(checked))
+	for _, err := range checked {
+		assertar.NoError(err)
+	}
+}
+
+func TestHeavyCheckRules(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	net := newTestNet(2)
+	checker := newTestChecker(net, nil, 1)
+	errRule := errors.New("rule")
+	checker.AddRule(func(e *inter.Event) error {
+		if e.Creator == 2 {
+			return errRule
+		}
+		return nil
+	})
+
+	assertar.NoError(checker.Validate(net.makeEvent(1, 1, nil)))
+	assertar.Equal(errRule, checker.Validate(net.makeEvent(2, 1, nil)))
+	// built-in checks are first
+	e := net.makeEvent(2, 1, nil)
+	e.Sig[0]++
+	assertar.Equal(ErrWrongEventSig, checker.Validate(e))
+}
+
+func TestBatchSize(t *testing.T) {
+	assertar := assert.New(t)
+
+	checker := New(&lachesis.DagConfig{}, nil, nil, nil, 4)
+	assertar.Equal(minBatch, checker.batchSize(1))
+	assertar.Equal(minBatch, checker.batchSize(4*minBatch))
+	assertar.Equal(10, checker.batchSize(40))
+	assertar.Equal(maxBatch, checker.batchSize(1000))
+}
+
+// BenchmarkTxStorm measures throughput of checking events full of txs, which are relayed by a few peers,
+// while the txs were already received by the tx pool.
+func BenchmarkTxStorm(b *testing.B) {
+	const (
+		validators  = 10
+		eventsNum   = 100
+		txsPerEvent = 50
+		peers       = 3
+	)
+
+	net := newTestNet(validators)
+	events := make(inter.Events, 0, eventsNum)
+	for i := 0; i < eventsNum; i++ {
+		creator := idx.StakerID(i%validators + 1)
+		events = append(events, net.makeEvent(creator, idx.Event(i/validators+1), net.makeTxs(txsPerEvent)))
+	}
+
+	for _, cacheSize := range []int{0, 1024, eventsNum * (txsPerEvent + 1)} {
+		for _, txpool := range []bool{false, true} {
+			b.Run(fmt.Sprintf("cache=%d/txpool=%v", cacheSize, txpool), func(b *testing.B) {
+				benchmarkTxStorm(b, net, events, cacheSize, txpool, peers)
+			})
+		}
+	}
+}
+
+func benchmarkTxStorm(b *testing.B, net *testNet, events inter.Events, cacheSize int, txpool bool, peers int) {
+	checker := newTestChecker(net, nil, 4)
+	checker.Start()
+	defer checker.Stop()
+
+	txs := 0
+	for _, e := range events {
+		txs += e.Transactions.Len()
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		sigs := sigcache.New(cacheSize)
+		checker.sigs = sigs
+		checker.txSigner = sigcache.NewSigner(types.NewEIP155Signer(testChainID), sigs)
+		if txpool {
+			// the txs are verified by the tx pool before the events arrive
+			for _, e := range relay(events) {
+				for _, tx := range e.Transactions {
+					_, _ = types.Sender(checker.txSigner, tx)
+				}
+			}
+		}
+		relayed := make([]inter.Events, peers)
+		for p := range relayed {
+			relayed[p] = relay(events)
+		}
+		b.StartTimer()
+
+		var (
+			wg     sync.WaitGroup
+			failed uint32
+		)
+		for _, evs := range relayed {
+			wg.Add(len(evs))
+			err := checker.Enqueue(evs, func(task *TaskData) {
+				for _, err := range task.Result {
+					if err != nil {
+						atomic.StoreUint32(&failed, 1)
+					}
+				}
+				wg.Add(-len(task.Events))
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+		wg.Wait()
+		if atomic.LoadUint32(&failed) != 0 {
+			b.Fatal("event is rejected")
+		}
+	}
+	b.ReportMetric(float64(b.N*txs*peers)/b.Elapsed().Seconds(), "txs/s")
+}
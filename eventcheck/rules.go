@@ -0,0 +1,175 @@
+package eventcheck
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+)
+
+// Stage of the checks, which a custom rule is run at.
+type Stage int
+
+const (
+	// LightStage rules require only the event. They're run after the basic and epoch checks,
+	// before the event is buffered.
+	LightStage Stage = iota
+	// ParentsStage rules require the event's parents. They're run after the parents and gas power checks,
+	// when all the parents are connected.
+	ParentsStage
+	// HeavyStage rules require only the event, but are expensive. They're run in the threads
+	// of heavycheck, after the signatures checks.
+	HeavyStage
+)
+
+// String returns the stage name.
+func (s Stage) String() string {
+	switch s {
+	case LightStage:
+		return "light"
+	case ParentsStage:
+		return "parents"
+	case HeavyStage:
+		return "heavy"
+	}
+	return fmt.Sprintf("stage-%d", int(s))
+}
+
+type (
+	// Rule is a custom validation rule, e.g. of a private network.
+	// It's run after the built-in checks of its stage.
+	Rule struct {
+		Name  string
+		Stage Stage
+		// Validate returns nil if the event passes the rule.
+		// Parents are provided only at ParentsStage, in the order of e.Parents.
+		Validate func(e *inter.Event, parents []*inter.EventHeaderData) error
+	}
+
+	// Rules is a set of custom rules, ordered by stages and then by addition.
+	Rules struct {
+		stages [HeavyStage + 1][]Rule
+	}
+
+	// RuleError is a failure of a custom rule.
+	// The event is a ban reason, unless the rule's error is wrapped by NotBan.
+	RuleError struct {
+		Rule string
+		Err  error
+	}
+
+	// notBanError is a failure, which isn't a ban reason.
+	notBanError struct {
+		error
+	}
+)
+
+// Error returns the rule name and the error.
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("rule %s: %v", e.Rule, e.Err)
+}
+
+// Unwrap returns the rule's error.
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// IsBan returns true if the rule's error is a ban reason.
+func (e *RuleError) IsBan() bool {
+	return IsBan(e.Err)
+}
+
+// NotBan wraps the error, so the event is rejected without punishing the peer which has sent it.
+func NotBan(err error) error {
+	return &notBanError{err}
+}
+
+func (e *notBanError) Unwrap() error {
+	return e.error
+}
+
+func (e *notBanError) IsBan() bool {
+	return false
+}
+
+func (r *Rule) validate(e *inter.Event, parents []*inter.EventHeaderData) error {
+	if err := r.Validate(e, parents); err != nil {
+		return &RuleError{
+			Rule: r.Name,
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// Add appends the rule to its stage.
+func (rr *Rules) Add(r Rule) {
+	if r.Stage < LightStage || r.Stage > HeavyStage {
+		panic(fmt.Sprintf("eventcheck: unknown stage %s of rule %s", r.Stage, r.Name))
+	}
+	if r.Validate == nil {
+		panic(fmt.Sprintf("eventcheck: rule %s has no Validate", r.Name))
+	}
+	rr.stages[r.Stage] = append(rr.stages[r.Stage], r)
+}
+
+// Len returns the number of the rules.
+func (rr *Rules) Len() int {
+	n := 0
+	for _, rules := range rr.stages {
+		n += len(rules)
+	}
+	return n
+}
+
+// ValidateStage runs the rules of the stage.
+func (rr *Rules) ValidateStage(stage Stage, e *inter.Event, parents []*inter.EventHeaderData) error {
+	for _, r := range rr.stages[stage] {
+		if err := r.validate(e, parents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate runs the rules of all the stages.
+func (rr *Rules) Validate(e *inter.Event, parents []*inter.EventHeaderData) error {
+	if err := rr.ValidateStage(LightStage, e, nil); err != nil {
+		return err
+	}
+	if err := rr.ValidateStage(ParentsStage, e, parents); err != nil {
+		return err
+	}
+	return rr.ValidateStage(HeavyStage, e, nil)
+}
+
+var registry struct {
+	rules []Rule
+	names map[string]bool
+	mu    sync.Mutex
+}
+
+// Register makes the rule available for all the Checkers built by the node.
+// It's intended to be called from init() of the package which implements the rule.
+// It panics if the rule name is registered already.
+func Register(r Rule) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.names == nil {
+		registry.names = make(map[string]bool)
+	}
+	if registry.names[r.Name] {
+		panic("eventcheck: rule " + r.Name + " is registered twice")
+	}
+	registry.names[r.Name] = true
+	registry.rules = append(registry.rules, r)
+}
+
+// Registered returns the registered rules, in the order of registration.
+func Registered() []Rule {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	return append(make([]Rule, 0, len(registry.rules)), registry.rules...)
+}
@@ -0,0 +1,69 @@
+// Package ruletest is a harness to unit test custom event validation rules against ASCII-scheme DAGs.
+package ruletest
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/eventcheck"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// BuildFn may modify the event of the scheme before its hash is calculated (e.g. add txs or set Extra).
+// The event is skipped if nil is returned.
+type BuildFn func(e *inter.Event, name string) *inter.Event
+
+// Result of the rules check.
+type Result struct {
+	// Events of the scheme by names.
+	Events map[string]*inter.Event
+	// Errors of the events, which haven't passed the rules, by names.
+	Errors map[string]error
+}
+
+// Check builds the events of the ASCII-scheme, and validates each of them with the custom rules,
+// stage by stage, in the same order as eventcheck.Checkers does. The built-in checks aren't run.
+// The events of the scheme are built in the epoch 1, and their creators are numbered from 1
+// in the order of the creators' first events, unless build changes it.
+func Check(scheme string, build BuildFn, rules ...eventcheck.Rule) *Result {
+	var rr eventcheck.Rules
+	for _, r := range rules {
+		rr.Add(r)
+	}
+
+	res := &Result{
+		Errors: make(map[string]error),
+	}
+	headers := make(map[hash.Event]*inter.EventHeaderData)
+	creators := make(map[idx.StakerID]idx.StakerID)
+	_, _, res.Events = inter.ASCIIschemeForEach(scheme, inter.ForEachEvent{
+		Build: func(e *inter.Event, name string) *inter.Event {
+			if _, ok := creators[e.Creator]; !ok {
+				creators[e.Creator] = idx.StakerID(len(creators) + 1)
+			}
+			e.Creator = creators[e.Creator]
+			e.Epoch = 1
+			if build != nil {
+				return build(e, name)
+			}
+			return e
+		},
+		Process: func(e *inter.Event, name string) {
+			headers[e.Hash()] = &e.EventHeaderData
+
+			parents := make([]*inter.EventHeaderData, len(e.Parents))
+			for i, p := range e.Parents {
+				parents[i] = headers[p]
+			}
+			if err := rr.Validate(e, parents); err != nil {
+				res.Errors[name] = err
+			}
+		},
+	})
+	return res
+}
+
+// Passed returns true if the event has passed the rules.
+func (r *Result) Passed(name string) bool {
+	_, failed := r.Errors[name]
+	return !failed
+}
@@ -0,0 +1,184 @@
+package ruletest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/eventcheck"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+var (
+	errNotWhitelisted = errors.New("creator isn't whitelisted")
+	errExtraFormat    = errors.New("extra has wrong format")
+	errNoOtherParents = errors.New("event has no parents of other creators")
+	errTooManyTxs     = errors.New("too many txs of the same sender")
+)
+
+const testScheme = `
+a1.1   b1.1   c1.1
+║      ║      ║
+a1.2 ─ ╬ ─ ─  ╣
+║      ║      ║
+║      b1.2 ─ ╣
+║      ║      ║
+a1.3   ║      ║
+║      ║      ║
+╠ ─ ─  b1.3   ║
+║      ║      ║
+`
+
+// whitelist is a rule of a permissioned network.
+func whitelist(creators ...idx.StakerID) eventcheck.Rule {
+	allowed := make(map[idx.StakerID]bool)
+	for _, c := range creators {
+		allowed[c] = true
+	}
+	return eventcheck.Rule{
+		Name:  "whitelist",
+		Stage: eventcheck.LightStage,
+		Validate: func(e *inter.Event, _ []*inter.EventHeaderData) error {
+			if !allowed[e.Creator] {
+				return errNotWhitelisted
+			}
+			return nil
+		},
+	}
+}
+
+// extraFormat requires Extra to be prefixed, but doesn't punish peers for it.
+func extraFormat(prefix string) eventcheck.Rule {
+	return eventcheck.Rule{
+		Name:  "extra",
+		Stage: eventcheck.LightStage,
+		Validate: func(e *inter.Event, _ []*inter.EventHeaderData) error {
+			if !bytes.HasPrefix(e.Extra, []byte(prefix)) {
+				return eventcheck.NotBan(errExtraFormat)
+			}
+			return nil
+		},
+	}
+}
+
+// otherParents requires non-first events to have parents of other creators.
+func otherParents() eventcheck.Rule {
+	return eventcheck.Rule{
+		Name:  "other-parents",
+		Stage: eventcheck.ParentsStage,
+		Validate: func(e *inter.Event, parents []*inter.EventHeaderData) error {
+			if e.Seq <= 1 {
+				return nil
+			}
+			for _, p := range parents {
+				if p.Creator != e.Creator {
+					return nil
+				}
+			}
+			return errNoOtherParents
+		},
+	}
+}
+
+// maxTxsPerSender limits the number of txs of the same sender in an event.
+func maxTxsPerSender(limit int, signer types.Signer) eventcheck.Rule {
+	return eventcheck.Rule{
+		Name:  "max-txs-per-sender",
+		Stage: eventcheck.HeavyStage,
+		Validate: func(e *inter.Event, _ []*inter.EventHeaderData) error {
+			counts := make(map[common.Address]int)
+			for _, tx := range e.Transactions {
+				sender, err := types.Sender(signer, tx)
+				if err != nil {
+					return err
+				}
+				counts[sender]++
+				if counts[sender] > limit {
+					return errTooManyTxs
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func signedTxs(signer types.Signer, keys ...*ecdsa.PrivateKey) types.Transactions {
+	txs := make(types.Transactions, len(keys))
+	for i, key := range keys {
+		tx := types.NewTransaction(uint64(i), common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		txs[i], _ = types.SignTx(tx, signer, key)
+	}
+	return txs
+}
+
+func TestCheck(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	signer := types.NewEIP155Signer(big.NewInt(1))
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+
+	res := Check(testScheme, func(e *inter.Event, name string) *inter.Event {
+		if name != "b1.3" {
+			e.Extra = append([]byte("net:"), e.Extra...)
+		}
+		switch name {
+		case "a1.2":
+			e.Transactions = signedTxs(signer, key1, key2, key1)
+		case "a1.3":
+			e.Transactions = signedTxs(signer, key1, key1, key1)
+		}
+		return e
+	},
+		whitelist(1, 2),
+		extraFormat("net:"),
+		otherParents(),
+		maxTxsPerSender(2, signer),
+	)
+
+	assertar.Len(res.Events, 7)
+	expected := map[string]error{
+		"c1.1": errNotWhitelisted,
+		"b1.3": errExtraFormat,
+		"a1.3": errNoOtherParents, // and too many txs, but heavy rules are run last
+	}
+	for name := range res.Events {
+		err, failed := res.Errors[name]
+		want, fail := expected[name]
+		if !assertar.Equal(fail, failed, name) || !fail {
+			continue
+		}
+		var ruleErr *eventcheck.RuleError
+		assertar.True(errors.As(err, &ruleErr), name)
+		assertar.True(errors.Is(err, want), name)
+	}
+
+	assertar.Equal("other-parents", res.Errors["a1.3"].(*eventcheck.RuleError).Rule)
+
+	// heavy rule
+	res = Check(testScheme, func(e *inter.Event, name string) *inter.Event {
+		if name == "a1.3" {
+			e.Transactions = signedTxs(signer, key1, key1, key1)
+		}
+		return e
+	}, maxTxsPerSender(2, signer))
+	assertar.True(res.Passed("a1.2"))
+	assertar.False(res.Passed("a1.3"))
+	assertar.True(errors.Is(res.Errors["a1.3"], errTooManyTxs))
+
+	// ban reasons
+	assertar.True(eventcheck.IsBan(res.Errors["a1.3"]))
+	res = Check(testScheme, nil, extraFormat("net:"))
+	assertar.False(eventcheck.IsBan(res.Errors["a1.1"]))
+	assertar.Len(res.Errors, 7)
+}
@@ -36,6 +36,7 @@ import (
 
 	lachesisparams "github.com/Fantom-foundation/go-lachesis/lachesis/params"
 	"github.com/Fantom-foundation/go-lachesis/tracing"
+	"github.com/Fantom-foundation/go-lachesis/utils/sigcache"
 )
 
 const (
@@ -262,6 +263,12 @@ type txpoolResetRequest struct {
 // NewTxPool creates a new transaction pool to gather, sort and filter inbound
 // transactions from the network.
 func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain stateReader) *TxPool {
+	return NewTxPoolWithSigCache(config, chainconfig, chain, nil)
+}
+
+// NewTxPoolWithSigCache creates a new transaction pool, which shares the cache of verified signatures
+// with other checkers, so tx senders aren't recovered again.
+func NewTxPoolWithSigCache(config TxPoolConfig, chainconfig *params.ChainConfig, chain stateReader, sigs *sigcache.Cache) *TxPool {
 	// Sanitize the input to ensure no vulnerable gas prices are set
 	config = (&config).sanitize()
 
@@ -270,7 +277,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain state
 		config:          config,
 		chainconfig:     chainconfig,
 		chain:           chain,
-		signer:          types.NewEIP155Signer(chainconfig.ChainID),
+		signer:          sigcache.NewSigner(types.NewEIP155Signer(chainconfig.ChainID), sigs),
 		pending:         make(map[common.Address]*txList),
 		queue:           make(map[common.Address]*txList),
 		beats:           make(map[common.Address]time.Time),
@@ -0,0 +1,168 @@
+package finality
+
+import (
+	"sort"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+)
+
+// dag is a subgraph of the epoch events. Event A observes event B if B is reachable from A
+// by the parents of the subgraph headers.
+type dag struct {
+	headers map[hash.Event]*inter.EventHeader
+	ordered []*inter.EventHeader // ancestors are before descendants
+
+	observers map[hash.Event]hash.EventsSet // cache
+	ancestors map[hash.Event]hash.EventsSet // cache
+}
+
+func newDag(headers []*inter.EventHeader) *dag {
+	d := &dag{
+		headers:   make(map[hash.Event]*inter.EventHeader, len(headers)),
+		ordered:   make([]*inter.EventHeader, 0, len(headers)),
+		observers: make(map[hash.Event]hash.EventsSet),
+		ancestors: make(map[hash.Event]hash.EventsSet),
+	}
+	for _, h := range headers {
+		if _, ok := d.headers[h.Hash()]; ok {
+			continue
+		}
+		d.headers[h.Hash()] = h
+		d.ordered = append(d.ordered, h)
+	}
+	// parent's Lamport is always lower
+	sort.SliceStable(d.ordered, func(i, j int) bool {
+		return d.ordered[i].Lamport < d.ordered[j].Lamport
+	})
+	return d
+}
+
+// observersOf returns the subgraph events which observe the event, including itself.
+func (d *dag) observersOf(id hash.Event) hash.EventsSet {
+	if res, ok := d.observers[id]; ok {
+		return res
+	}
+
+	res := hash.EventsSet{}
+	res.Add(id)
+	for _, h := range d.ordered {
+		for _, p := range h.Parents {
+			if res.Contains(p) {
+				res.Add(h.Hash())
+				break
+			}
+		}
+	}
+
+	d.observers[id] = res
+	return res
+}
+
+// ancestorsOf returns the subgraph events which are observed by the event, including itself.
+func (d *dag) ancestorsOf(id hash.Event) hash.EventsSet {
+	if res, ok := d.ancestors[id]; ok {
+		return res
+	}
+
+	res := hash.EventsSet{}
+	stack := hash.Events{id}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if res.Contains(cur) {
+			continue
+		}
+		res.Add(cur)
+		for _, p := range d.headers[cur].Parents {
+			if _, ok := d.headers[p]; ok {
+				stack = append(stack, p)
+			}
+		}
+	}
+
+	d.ancestors[id] = res
+	return res
+}
+
+// isComplete returns true if all the ancestors of the event since the frame are in the subgraph,
+// i.e. every subgraph ancestor of the frame or above has all its parents in the subgraph.
+// Frame of event isn't lower than frames of its parents, so the other ancestors are of the lower frames.
+func (d *dag) isComplete(id hash.Event, frame idx.Frame) bool {
+	for a := range d.ancestorsOf(id) {
+		h := d.headers[a]
+		if h.Frame < frame {
+			continue
+		}
+		for _, p := range h.Parents {
+			if _, ok := d.headers[p]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// forklessCause returns true if event A forkless causes event B: events of 2/3W validators,
+// which are observed by A, observe B. Validators, which forks are observed by A, aren't counted.
+// It returns the minimal set of such witness events.
+func (d *dag) forklessCause(a, b hash.Event, validators *pos.Validators) (witnesses hash.Events, ok bool) {
+	ancestors := d.ancestorsOf(a)
+	if !ancestors.Contains(b) {
+		return nil, false
+	}
+	observers := d.observersOf(b)
+
+	// fork is a pair of events with the same creator and seq
+	seqs := make(map[idx.StakerID]map[idx.Event]hash.Event)
+	cheaters := make(map[idx.StakerID]bool)
+	for id := range ancestors {
+		h := d.headers[id]
+		bySeq := seqs[h.Creator]
+		if bySeq == nil {
+			bySeq = make(map[idx.Event]hash.Event)
+			seqs[h.Creator] = bySeq
+		}
+		if other, ok := bySeq[h.Seq]; ok && other != id {
+			cheaters[h.Creator] = true
+		}
+		bySeq[h.Seq] = id
+	}
+
+	counter := validators.NewCounter()
+	for _, h := range d.ordered {
+		id := h.Hash()
+		if !ancestors.Contains(id) || !observers.Contains(id) || cheaters[h.Creator] || !validators.Exists(h.Creator) {
+			continue
+		}
+		if counter.Count(h.Creator) {
+			witnesses.Add(id)
+		}
+		if counter.HasQuorum() {
+			return witnesses, true
+		}
+	}
+	return nil, false
+}
+
+// path returns the events from A to B by parents, if A observes B.
+func (d *dag) path(a, b hash.Event) hash.Events {
+	observers := d.observersOf(b)
+	if !observers.Contains(a) {
+		return nil
+	}
+
+	res := hash.Events{a}
+	for cur := a; cur != b; {
+		for _, p := range d.headers[cur].Parents {
+			if observers.Contains(p) {
+				cur = p
+				break
+			}
+		}
+		res.Add(cur)
+	}
+	return res
+}
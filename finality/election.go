@@ -0,0 +1,152 @@
+package finality
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
+)
+
+// decision of the candidate in the 2nd election round: the decider root of frame+2
+// forkless causes the voter roots of frame+1, which have 2/3W+1 of the same votes.
+type decision struct {
+	candidate idx.StakerID
+	yes       bool
+	decider   hash.Event
+	voters    hash.Events
+}
+
+// elect checks that the Atropos is elected in its frame by the default election strategy:
+// the validators before the Atropos creator in the candidates order are decided "no",
+// and the Atropos is decided "yes". It returns the decisions in the candidates order.
+// Only decisions of the 2nd election round are recognized, and "no" vote is recognized
+// only if all the voter ancestors since the Atropos frame are in the subgraph.
+func (d *dag) elect(atropos *inter.EventHeader, validators *pos.Validators) (decisions []decision, ok bool) {
+	strategy := election.DefaultStrategy()
+	frame := atropos.Frame
+
+	var voters, deciders []*inter.EventHeader
+	for _, h := range d.ordered {
+		if !h.IsRoot {
+			continue
+		}
+		switch h.Frame {
+		case frame + 1:
+			voters = append(voters, h)
+		case frame + 2:
+			deciders = append(deciders, h)
+		}
+	}
+
+	for _, candidate := range strategy.AtroposCandidates(validators, frame) {
+		yes := candidate == atropos.Creator
+		vote := func(voter *inter.EventHeader) bool {
+			if yes {
+				_, ok := d.forklessCause(voter.Hash(), atropos.Hash(), validators)
+				return ok
+			}
+			return d.votesNo(voter, candidate, frame, validators)
+		}
+
+		res, ok := d.decide(deciders, voters, vote, strategy.Quorum(validators), validators)
+		if !ok {
+			return nil, false
+		}
+		res.candidate = candidate
+		res.yes = yes
+		decisions = append(decisions, res)
+		if yes {
+			return decisions, true
+		}
+	}
+
+	return nil, false
+}
+
+// decide finds the decider, which forkless causes the voters with the quorum of the votes.
+func (d *dag) decide(deciders, voters []*inter.EventHeader, vote func(*inter.EventHeader) bool, quorum pos.Stake, validators *pos.Validators) (res decision, ok bool) {
+	votes := make(map[hash.Event]bool, len(voters))
+	for _, decider := range deciders {
+		counter := validators.NewCounter()
+		res.voters = res.voters[:0]
+		for _, voter := range voters {
+			if _, ok := d.forklessCause(decider.Hash(), voter.Hash(), validators); !ok {
+				continue
+			}
+			v, voted := votes[voter.Hash()]
+			if !voted {
+				v = vote(voter)
+				votes[voter.Hash()] = v
+			}
+			if !v {
+				continue
+			}
+			if counter.Count(voter.Creator) {
+				res.voters.Add(voter.Hash())
+			}
+			if counter.Sum() >= quorum {
+				res.decider = decider.Hash()
+				return res, true
+			}
+		}
+	}
+	return res, false
+}
+
+// votesNo returns true if the voter doesn't forkless cause any root of the candidate in the frame,
+// and it's provable by the subgraph.
+func (d *dag) votesNo(voter *inter.EventHeader, candidate idx.StakerID, frame idx.Frame, validators *pos.Validators) bool {
+	if !d.isComplete(voter.Hash(), frame) {
+		return false
+	}
+	for _, h := range d.ordered {
+		if h.IsRoot && h.Frame == frame && h.Creator == candidate {
+			if _, ok := d.forklessCause(voter.Hash(), h.Hash(), validators); ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// justification returns the subgraph events, which are sufficient to verify the decisions.
+func (d *dag) justification(atropos *inter.EventHeader, decisions []decision, validators *pos.Validators) hash.EventsSet {
+	included := hash.EventsSet{}
+	included.Add(atropos.Hash())
+
+	forklessCause := func(a, b hash.Event) {
+		witnesses, _ := d.forklessCause(a, b, validators)
+		for _, w := range witnesses {
+			for _, id := range d.path(a, w) {
+				included.Add(id)
+			}
+			for _, id := range d.path(w, b) {
+				included.Add(id)
+			}
+		}
+	}
+
+	for _, res := range decisions {
+		for _, voter := range res.voters {
+			forklessCause(res.decider, voter)
+			if res.yes {
+				forklessCause(voter, atropos.Hash())
+				continue
+			}
+			// "no" vote is verifiable only with all the voter ancestors since the frame, and their parents
+			for a := range d.ancestorsOf(voter) {
+				h := d.headers[a]
+				if h.Frame < atropos.Frame {
+					continue
+				}
+				included.Add(a)
+				for _, p := range h.Parents {
+					included.Add(p)
+				}
+			}
+		}
+	}
+
+	return included
+}
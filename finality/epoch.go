@@ -0,0 +1,106 @@
+package finality
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+)
+
+var (
+	// ErrPrevValidatorsMismatch indicates that the transition doesn't follow the known validators group.
+	ErrPrevValidatorsMismatch = errors.New("previous validators of the transition mismatch the known ones")
+	// ErrNoValidators indicates that the transition has no new validators.
+	ErrNoValidators = errors.New("transition has no validators")
+	// ErrNoProof indicates that the transition has no finality proof of the sealing block.
+	ErrNoProof = errors.New("transition has no sealing proof")
+	// ErrWrongSealing indicates that the proof isn't of the sealing block of the previous epoch.
+	ErrWrongSealing = errors.New("proof isn't of the sealing block")
+	// ErrEpochGap indicates that the transitions aren't consecutive.
+	ErrEpochGap = errors.New("transitions aren't consecutive")
+)
+
+// EpochTransition is a record of the validators group change by the epoch sealing.
+// It's signed by the previous validators group with the finality proof of the sealing block.
+// Note that the new validators group is derived from the state of the sealing block,
+// which isn't the part of the proof.
+type EpochTransition struct {
+	Epoch              idx.Epoch   // the new epoch
+	PrevValidatorsHash common.Hash // see ValidatorsHash
+	Validators         []Validator // validators group of the new epoch
+	SealingBlock       idx.Block   // the last block of the previous epoch
+	Proof              *Proof      `rlp:"nil"` // finality proof of the sealing block, nil if it wasn't built
+}
+
+// ValidatorsHash returns hash of the validators group, regardless of the validators order.
+func ValidatorsHash(validators []Validator) common.Hash {
+	sorted := make([]Validator, len(validators))
+	copy(sorted, validators)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	buf := bytes.Buffer{}
+	if err := rlp.Encode(&buf, sorted); err != nil {
+		panic(err)
+	}
+	return hash.Of(buf.Bytes())
+}
+
+// ValidatorsGroup returns validators group with their addresses.
+func ValidatorsGroup(validators []Validator) (*pos.Validators, map[idx.StakerID]common.Address) {
+	builder := pos.NewBuilder()
+	addrs := make(map[idx.StakerID]common.Address, len(validators))
+	for _, v := range validators {
+		builder.Set(v.ID, v.Stake)
+		addrs[v.ID] = v.Address
+	}
+	return builder.Build(), addrs
+}
+
+// FirstBlock is the first block of the first epoch, the genesis block isn't decided.
+const FirstBlock = idx.Block(1)
+
+// VerifyTransition checks that the transition is signed by the previous validators group,
+// i.e. its sealing block is decided by them. The prevFirstBlock is the first block of the previous epoch.
+func VerifyTransition(t *EpochTransition, prev []Validator, prevFirstBlock idx.Block) error {
+	if t.PrevValidatorsHash != ValidatorsHash(prev) {
+		return ErrPrevValidatorsMismatch
+	}
+	if len(t.Validators) == 0 {
+		return ErrNoValidators
+	}
+	if t.Proof == nil {
+		return ErrNoProof
+	}
+	if t.Proof.Block != t.SealingBlock || t.Proof.Atropos.Epoch()+1 != t.Epoch {
+		return ErrWrongSealing
+	}
+
+	validators, addrs := ValidatorsGroup(prev)
+	return Verify(t.Proof, prevFirstBlock, validators, addrs)
+}
+
+// WalkEpochs verifies the consecutive transitions, starting from the known validators group and the first block
+// of the first epoch (FirstBlock for the genesis validators), and returns validators group and the first block
+// of the last epoch.
+func WalkEpochs(first []Validator, firstBlock idx.Block, transitions []*EpochTransition) ([]Validator, idx.Block, error) {
+	validators := first
+	for i, t := range transitions {
+		if i > 0 && t.Epoch != transitions[i-1].Epoch+1 {
+			return nil, 0, ErrEpochGap
+		}
+		if err := VerifyTransition(t, validators, firstBlock); err != nil {
+			return nil, 0, err
+		}
+		validators = t.Validators
+		firstBlock = t.SealingBlock + 1
+	}
+	return validators, firstBlock, nil
+}
@@ -0,0 +1,42 @@
+// Package finality implements compact proofs of blocks finality,
+// which are verifiable by a third party without the DAG.
+package finality
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+)
+
+type (
+	// Validator of the epoch with the address, which authenticates its events.
+	Validator struct {
+		ID      idx.StakerID
+		Stake   pos.Stake
+		Address common.Address
+	}
+
+	// Proof of the block finality.
+	// It consists of signed event headers, which justify the election of the block's Atropos:
+	// the Atropos itself, the roots which vote for or against the candidates, the roots which decide them,
+	// and the events on the paths between them.
+	Proof struct {
+		Block      idx.Block
+		Atropos    hash.Event
+		Validators []Validator // validators group of the Atropos epoch
+		Headers    []*inter.EventHeader
+	}
+)
+
+// EpochValidators returns validators group of the proof with their addresses.
+func (p *Proof) EpochValidators() (*pos.Validators, map[idx.StakerID]common.Address) {
+	return ValidatorsGroup(p.Validators)
+}
+
+// BlockHash returns hash of the proven block, which is the Atropos ID.
+func (p *Proof) BlockHash() common.Hash {
+	return common.Hash(p.Atropos)
+}
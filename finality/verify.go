@@ -0,0 +1,110 @@
+package finality
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+)
+
+var (
+	// ErrValidatorsMismatch indicates that proof's validators group differs from the known one.
+	ErrValidatorsMismatch = errors.New("validators group of the proof mismatches the known one")
+	// ErrAuth indicates that event's creator isn't a validator.
+	ErrAuth = errors.New("event creator isn't validator")
+	// ErrWrongSig indicates that event has wrong signature.
+	ErrWrongSig = errors.New("event has wrong signature")
+	// ErrWrongEpoch indicates that event's epoch isn't the Atropos epoch.
+	ErrWrongEpoch = errors.New("event isn't of the Atropos epoch")
+	// ErrNoAtropos indicates that the Atropos header isn't found or isn't a root.
+	ErrNoAtropos = errors.New("Atropos root isn't found")
+	// ErrWrongBlock indicates that the block isn't decided by the Atropos frame.
+	ErrWrongBlock = errors.New("block number mismatches the Atropos frame")
+	// ErrNotDecided indicates that the headers don't justify the Atropos election.
+	ErrNotDecided = errors.New("Atropos election isn't justified by the headers")
+)
+
+// BlockOf returns number of the block, which is decided by the Atropos of the frame.
+// Every decided frame of the epoch makes a block, so frames are numbered as blocks since the first block of the epoch.
+func BlockOf(epochFirstBlock idx.Block, frame idx.Frame) idx.Block {
+	return epochFirstBlock + idx.Block(frame) - 1
+}
+
+// Verify checks the proof against the known validators group and the first block of the Atropos epoch.
+// The proof is valid if all the headers are signed by the validators, the block is decided by the Atropos frame,
+// and the Atropos is elected by the default election strategy: the roots of the frame+2 decide "no"
+// for every validator before the Atropos creator in the candidates order, and "yes" for the Atropos,
+// by votes of the roots of the frame+1 they forkless cause.
+// Block hash is the Atropos ID, so the proof is bound to the block by p.Block and p.BlockHash().
+// Note that forks, which aren't in the headers, aren't detected.
+func Verify(p *Proof, epochFirstBlock idx.Block, validators *pos.Validators, addrs map[idx.StakerID]common.Address) error {
+	if len(p.Validators) != validators.Len() {
+		return ErrValidatorsMismatch
+	}
+	for _, v := range p.Validators {
+		if !validators.Exists(v.ID) || validators.Get(v.ID) != v.Stake || addrs[v.ID] != v.Address {
+			return ErrValidatorsMismatch
+		}
+	}
+
+	for _, h := range p.Headers {
+		h.RecacheHash() // don't trust the cached one
+		if h.Epoch != p.Atropos.Epoch() {
+			return ErrWrongEpoch
+		}
+		addr, ok := addrs[h.Creator]
+		if !ok {
+			return ErrAuth
+		}
+		if !h.VerifySignature(addr) {
+			return ErrWrongSig
+		}
+	}
+
+	d := newDag(p.Headers)
+	atropos := d.headers[p.Atropos]
+	if atropos == nil || !atropos.IsRoot {
+		return ErrNoAtropos
+	}
+	if p.Block != BlockOf(epochFirstBlock, atropos.Frame) {
+		return ErrWrongBlock
+	}
+	if _, ok := d.elect(atropos, validators); !ok {
+		return ErrNotDecided
+	}
+
+	return nil
+}
+
+// NewProof selects the headers which justify the Atropos election, out of the signed headers of the Atropos epoch.
+// The headers must include all the events of the Atropos frame and the next 2 frames, which precede the election decision,
+// and their parents.
+func NewProof(block idx.Block, atropos *inter.EventHeader, validators []Validator, headers []*inter.EventHeader) (*Proof, error) {
+	p := &Proof{
+		Block:      block,
+		Atropos:    atropos.Hash(),
+		Validators: validators,
+	}
+
+	d := newDag(append([]*inter.EventHeader{atropos}, headers...))
+	vv, _ := p.EpochValidators()
+	decisions, ok := d.elect(atropos, vv)
+	if !ok {
+		return nil, ErrNotDecided
+	}
+
+	for id := range d.justification(atropos, decisions, vv) {
+		p.Headers = append(p.Headers, d.headers[id])
+	}
+	// event ID starts with epoch and Lamport time
+	sort.Slice(p.Headers, func(i, j int) bool {
+		return bytes.Compare(p.Headers[i].Hash().Bytes(), p.Headers[j].Hash().Bytes()) < 0
+	})
+
+	return p, nil
+}
@@ -1,8 +1,15 @@
 package gossip
 
 import (
+	"path/filepath"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip/reputation"
+	"github.com/Fantom-foundation/go-lachesis/gossip/snapshot"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/keyspace"
 )
 
 // PublicEthereumAPI provides an API to access Ethereum-like information.
@@ -41,3 +48,61 @@ func (api *PublicEthereumAPI) Hashrate() hexutil.Uint64 {
 func (api *PublicEthereumAPI) ChainId() hexutil.Uint64 {
 	return hexutil.Uint64(api.s.config.Net.EvmChainConfig().ChainID.Uint64())
 }
+
+// PrivateDebugAPI provides private debug methods of the node.
+type PrivateDebugAPI struct {
+	s *Service
+}
+
+// NewPrivateDebugAPI creates a new private debug API for gossip.
+func NewPrivateDebugAPI(s *Service) *PrivateDebugAPI {
+	return &PrivateDebugAPI{s}
+}
+
+// SnapshotDb makes a consistent copy of the node databases into the empty dir.
+// Events processing is paused until the copy is done.
+func (api *PrivateDebugAPI) SnapshotDb(dir string) (*snapshot.Manifest, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	return api.s.SnapshotDb(dir)
+}
+
+// DbStats walks the node databases and reports key-space usage of each table,
+// with top largest key-value pairs of each table. The walk is done over the dbs snapshots,
+// so neither flushing nor events processing is paused.
+func (api *PrivateDebugAPI) DbStats(top *int) ([]*keyspace.DbStats, error) {
+	n := 0
+	if top != nil {
+		n = *top
+	}
+	return api.s.DbStats(n)
+}
+
+// PrivateAdminAPI provides private admin methods of the node.
+type PrivateAdminAPI struct {
+	s *Service
+}
+
+// NewPrivateAdminAPI creates a new private admin API for gossip.
+func NewPrivateAdminAPI(s *Service) *PrivateAdminAPI {
+	return &PrivateAdminAPI{s}
+}
+
+// PeerBans lists the banned peers.
+func (api *PrivateAdminAPI) PeerBans() []*reputation.Ban {
+	return api.s.pm.reputation.Bans()
+}
+
+// ClearPeerBans clears the ban of the peer, or all the bans if peer isn't specified.
+// It returns the number of cleared bans.
+func (api *PrivateAdminAPI) ClearPeerBans(id *enode.ID) int {
+	if id == nil {
+		return api.s.pm.reputation.UnbanAll()
+	}
+	if api.s.pm.reputation.Unban(*id) {
+		return 1
+	}
+	return 0
+}
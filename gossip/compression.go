@@ -0,0 +1,52 @@
+package gossip
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
+)
+
+// compressedMsgs are the messages, which payloads are snappy-compressed since lachesis63.
+var compressedMsgs = map[uint64]bool{
+	EventsMsg:        true,
+	PackInfosMsg:     true,
+	CompactEventsMsg: true,
+}
+
+// isCompressed returns true if the message payload is compressed in the protocol version.
+func isCompressed(version int, code uint64) bool {
+	return version >= lachesis63 && compressedMsgs[code]
+}
+
+// compressMsg encodes the data into a compressed payload.
+// The payload is sent as RLP string, which is the snappy-compressed RLP of the data.
+func compressMsg(data interface{}) ([]byte, error) {
+	raw, err := rlp.EncodeToBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Encode(nil, raw), nil
+}
+
+// decompressMsg decodes the compressed payload into val.
+// The decompressed size is limited by protocolMaxMsgSize.
+func decompressMsg(msg p2p.Msg, val interface{}) error {
+	var compressed []byte
+	if err := msg.Decode(&compressed); err != nil {
+		return err
+	}
+	size, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return err
+	}
+	if size > protocolMaxMsgSize {
+		return fmt.Errorf("decompressed size %v > %v", size, protocolMaxMsgSize)
+	}
+	raw, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return err
+	}
+	return rlp.DecodeBytes(raw, val)
+}
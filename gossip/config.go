@@ -3,8 +3,11 @@ package gossip
 import (
 	"math/big"
 
+	"github.com/Fantom-foundation/go-lachesis/app"
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
 	"github.com/Fantom-foundation/go-lachesis/gossip/gasprice"
+	"github.com/Fantom-foundation/go-lachesis/gossip/reputation"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/lachesis"
 	"github.com/Fantom-foundation/go-lachesis/lachesis/params"
 )
@@ -16,6 +19,9 @@ type (
 
 		LatencyImportance    int
 		ThroughputImportance int
+
+		// Peers misbehaviour scoring
+		Reputation reputation.Config
 	}
 	// Config for the gossip service.
 	Config struct {
@@ -27,6 +33,10 @@ type (
 		TxIndex             bool // Whether to enable indexing transactions and receipts or not
 		DecisiveEventsIndex bool // Whether to enable indexing events which decide blocks or not
 		EventLocalTimeIndex bool // Whether to enable indexing arrival time of events or not
+		ElectionTraces      bool // Whether to enable recording election traces of decided frames or not
+
+		// Number of the verified event and tx signatures to cache. 0 disables the cache.
+		SigCacheSize int
 
 		// Protocol options
 		Protocol ProtocolConfig
@@ -51,6 +61,25 @@ type (
 
 	// StoreConfig is a config for store db.
 	StoreConfig struct {
+		// On-disk database engine ("leveldb" or "badger"). Default is "leveldb".
+		DBEngine string
+		// Open databases in read-only mode: no events are emitted and received, only API is served.
+		ReadOnly bool
+
+		// Number of the last epochs to keep events and packs of. 0 means to keep the whole history.
+		RetainEpochs idx.Epoch
+		// Keep only headers of the blocks of pruned epochs, drop lists of their events.
+		RetainBlockHeadersOnly bool
+
+		// EVM state garbage collection mode ("full" or "archive"). Default is "archive".
+		GCMode string
+
+		// Number of the last blocks of the current epoch to keep checkpoints of, for rollback. 0 disables the history.
+		CheckpointHistory int
+
+		// Expected number of events per epoch, to size bloom filter of events. 0 disables the filter.
+		EventsBloomSize int
+
 		// Cache size for Events.
 		EventsCacheSize int
 		// Cache size for EventHeaderData (Epoch db).
@@ -63,6 +92,8 @@ type (
 		TxPositionsCacheSize int
 		// Cache size for EpochStats.
 		EpochStatsCacheSize int
+		// Cache size for block finality proofs.
+		BlockProofsCacheSize int
 
 		// NOTE: fields for config-file back compatibility
 		// Cache size for Receipts.
@@ -84,10 +115,12 @@ func DefaultConfig(network lachesis.Config) Config {
 
 		TxIndex:             true,
 		DecisiveEventsIndex: false,
+		SigCacheSize:        32 * 1024,
 
 		Protocol: ProtocolConfig{
 			LatencyImportance:    60,
 			ThroughputImportance: 40,
+			Reputation:           reputation.DefaultConfig(),
 		},
 
 		GPO: gasprice.Config{
@@ -115,23 +148,29 @@ func DefaultConfig(network lachesis.Config) Config {
 // DefaultStoreConfig for product.
 func DefaultStoreConfig() StoreConfig {
 	return StoreConfig{
+		GCMode:                 app.GCModeArchive,
+		EventsBloomSize:        200000,
 		EventsCacheSize:        500,
 		EventsHeadersCacheSize: 10000,
 		BlockCacheSize:         100,
 		PackInfosCacheSize:     100,
 		TxPositionsCacheSize:   1000,
 		EpochStatsCacheSize:    100,
+		BlockProofsCacheSize:   100,
 	}
 }
 
 // LiteStoreConfig is for tests or inmemory.
 func LiteStoreConfig() StoreConfig {
 	return StoreConfig{
+		GCMode:                 app.GCModeArchive,
+		EventsBloomSize:        10000,
 		EventsCacheSize:        100,
 		EventsHeadersCacheSize: 1000,
 		BlockCacheSize:         100,
 		PackInfosCacheSize:     100,
 		TxPositionsCacheSize:   100,
 		EpochStatsCacheSize:    100,
+		BlockProofsCacheSize:   10,
 	}
 }
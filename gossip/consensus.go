@@ -7,6 +7,7 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 	"github.com/Fantom-foundation/go-lachesis/vector"
 )
 
@@ -30,6 +31,8 @@ type Consensus interface {
 	GetEpochValidators() (*pos.Validators, idx.Epoch)
 	// GetConsensusTime calc consensus timestamp for given event.
 	GetConsensusTime(id hash.Event) (inter.Timestamp, error)
+	// GetElectionTrace returns election trace of the decided frame of current epoch, if recorded.
+	GetElectionTrace(f idx.Frame) *election.Trace
 
 	// Bootstrap must be called (once) before calling other methods
 	Bootstrap(callbacks inter.ConsensusCallbacks)
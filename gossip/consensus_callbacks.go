@@ -11,14 +11,26 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/Fantom-foundation/go-lachesis/app"
 	"github.com/Fantom-foundation/go-lachesis/eventcheck"
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
 	"github.com/Fantom-foundation/go-lachesis/tracing"
 )
 
+// consensusCallbacks returns the callbacks of the engine, which apply decided blocks to the service state.
+func (s *Service) consensusCallbacks() inter.ConsensusCallbacks {
+	return inter.ConsensusCallbacks{
+		ApplyBlock:              s.applyBlock,
+		SelectValidatorsGroup:   s.selectValidatorsGroup,
+		OnEventConfirmed:        s.onEventConfirmed,
+		IsEventAllowedIntoBlock: s.isEventAllowedIntoBlock,
+	}
+}
+
 // processEvent extends the engine.ProcessEvent with gossip-specific actions on each event processing
 func (s *Service) processEvent(realEngine Consensus, e *inter.Event) error {
 	// s.engineMu is locked here
@@ -74,19 +86,25 @@ func (s *Service) processEvent(realEngine Consensus, e *inter.Event) error {
 		s.store.delEpochStore(oldEpoch)
 		s.store.getEpochStore(newEpoch)
 		s.occurredTxs.Clear()
+		s.scheduleProving()
+		s.schedulePruning()
 
 		// notify about new epoch after event connection
 		s.emitter.OnNewEpoch(s.engine.GetValidators(), newEpoch)
 		s.feed.newEpoch.Send(newEpoch)
 	}
 
-	immediately := (newEpoch != oldEpoch)
+	// decide once, because EVM state and DAG must be flushed together
+	immediately := (newEpoch != oldEpoch) || s.store.IsCommitNeeded()
 
 	err := s.app.Commit(e.Hash().Bytes(), immediately)
 	if err != nil {
 		return err
 	}
-	return s.store.Commit(e.Hash().Bytes(), immediately)
+	if !immediately {
+		return nil
+	}
+	return s.store.Commit(e.Hash().Bytes(), true)
 }
 
 // applyNewState moves the state according to new block (txs execution, SFC logic, epoch sealing)
@@ -145,6 +163,9 @@ func (s *Service) applyNewState(
 	s.updateUsersPOI(block, evmBlock, receipts, totalFee, sealEpoch)
 	s.updateStakersPOI(block, sealEpoch)
 
+	// Keep evidences of the forks, to audit the slashing off-chain
+	s.recordForkEvidences(block.Atropos.Epoch(), cheaters)
+
 	// Process SFC contract transactions
 	s.processSfc(block, receipts, totalFee, sealEpoch, cheaters, statedb)
 
@@ -154,7 +175,7 @@ func (s *Service) applyNewState(
 	}
 
 	// Get state root
-	newStateHash, err := statedb.Commit(true)
+	newStateHash, err := s.app.CommitState(statedb)
 	if err != nil {
 		s.Log.Crit("Failed to commit state", "err", err)
 	}
@@ -174,7 +195,7 @@ func (s *Service) applyNewState(
 }
 
 // spillBlockEvents excludes first events which exceed BlockGasHardLimit
-func (s *Service) spillBlockEvents(block *inter.Block) (*inter.Block, inter.Events) {
+func spillBlockEvents(store *Store, block *inter.Block, gasHardLimit uint64) (*inter.Block, inter.Events) {
 	fullEvents := make(inter.Events, len(block.Events))
 	if len(block.Events) == 0 {
 		return block, fullEvents
@@ -183,14 +204,14 @@ func (s *Service) spillBlockEvents(block *inter.Block) (*inter.Block, inter.Even
 	// iterate in reversed order
 	for i := len(block.Events) - 1; ; i-- {
 		id := block.Events[i]
-		e := s.store.GetEvent(id)
+		e := store.GetEvent(id)
 		if e == nil {
-			s.Log.Crit("Event not found", "event", id.String())
+			store.Log.Crit("Event not found", "event", id.String())
 		}
 		fullEvents[i] = e
 		gasPowerUsedSum += e.GasPowerUsed
 		// stop if limit is exceeded, erase [:i] events
-		if gasPowerUsedSum > s.config.Net.Blocks.BlockGasHardLimit {
+		if gasPowerUsedSum > gasHardLimit {
 			// spill
 			block.Events = block.Events[i+1:]
 			fullEvents = fullEvents[i+1:]
@@ -211,7 +232,7 @@ func (s *Service) assembleEvmBlock(
 	if len(block.SkippedTxs) != 0 {
 		log.Crit("Building with SkippedTxs isn't supported")
 	}
-	block, blockEvents := s.spillBlockEvents(block)
+	block, blockEvents := spillBlockEvents(s.store, block, s.config.Net.Blocks.BlockGasHardLimit)
 
 	// Assemble block data
 	evmBlock := &evmcore.EvmBlock{
@@ -299,12 +320,9 @@ func (s *Service) applyBlock(block *inter.Block, decidedFrame idx.Frame, cheater
 	// s.engineMu is locked here
 
 	confirmBlocksMeter.Inc(1)
-	// if cheater is confirmed, seal epoch right away to prune them from of BFT validators list
 
 	epochStart := s.store.GetEpochStats(pendingEpoch).Start
-	sealEpoch = decidedFrame >= s.config.Net.Dag.MaxEpochBlocks
-	sealEpoch = sealEpoch || block.Time-epochStart >= inter.Timestamp(s.config.Net.Dag.MaxEpochDuration)
-	sealEpoch = sealEpoch || cheaters.Len() > 0
+	sealEpoch = isEpochSealedBy(&s.config.Net.Dag, block, decidedFrame, cheaters, epochStart)
 
 	block, evmBlock, receipts, txPositions, newAppHash := s.applyNewState(block, sealEpoch, cheaters)
 
@@ -359,14 +377,31 @@ func (s *Service) applyBlock(block *inter.Block, decidedFrame idx.Frame, cheater
 func (s *Service) selectValidatorsGroup(oldEpoch, newEpoch idx.Epoch) (newValidators *pos.Validators) {
 	// s.engineMu is locked here
 
+	// the sealing block is already applied
+	sealing, _ := s.engine.LastBlock()
+	s.store.SetEpochTransition(makeEpochTransition(s.app, oldEpoch, sealing))
+
+	return readEpochValidators(s.app, newEpoch)
+}
+
+// readEpochValidators returns validators group of the epoch, which is written by SFC logic
+func readEpochValidators(a *app.Store, epoch idx.Epoch) *pos.Validators {
 	builder := pos.NewBuilder()
-	for _, it := range s.app.GetEpochValidators(newEpoch) {
+	for _, it := range a.GetEpochValidators(epoch) {
 		builder.Set(it.StakerID, pos.BalanceToStake(it.Staker.CalcTotalStake()))
 	}
 
 	return builder.Build()
 }
 
+// isEpochSealedBy returns true if the block is the last one of epoch
+func isEpochSealedBy(dag *lachesis.DagConfig, block *inter.Block, decidedFrame idx.Frame, cheaters inter.Cheaters, epochStart inter.Timestamp) bool {
+	// if cheater is confirmed, seal epoch right away to prune them from of BFT validators list
+	return decidedFrame >= dag.MaxEpochBlocks ||
+		block.Time-epochStart >= inter.Timestamp(dag.MaxEpochDuration) ||
+		cheaters.Len() > 0
+}
+
 // onEventConfirmed is callback type to notify about event confirmation
 func (s *Service) onEventConfirmed(header *inter.EventHeaderData, seqDepth idx.Event) {
 	// s.engineMu is locked here
@@ -391,10 +426,14 @@ func (s *Service) onEventConfirmed(header *inter.EventHeaderData, seqDepth idx.E
 func (s *Service) isEventAllowedIntoBlock(header *inter.EventHeaderData, seqDepth idx.Event) bool {
 	// s.engineMu is locked here
 
+	return isEventAllowedIntoBlock(&s.config.Net.Dag, header, seqDepth)
+}
+
+func isEventAllowedIntoBlock(dag *lachesis.DagConfig, header *inter.EventHeaderData, seqDepth idx.Event) bool {
 	if header.NoTransactions() {
 		return false // block contains only non-empty events to speed up block retrieving and processing
 	}
-	if seqDepth > s.config.Net.Dag.MaxValidatorEventsInBlock {
+	if seqDepth > dag.MaxValidatorEventsInBlock {
 		return false // block contains only MaxValidatorEventsInBlock highest events from a creator to prevent huge blocks
 	}
 	return true
@@ -0,0 +1,202 @@
+package gossip
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+)
+
+// BlockMismatchError is raised when the replayed consensus decides a block
+// which differs from the stored one.
+type BlockMismatchError struct {
+	Block           idx.Block
+	Field           string
+	Stored, Decided string
+}
+
+// Error implements error interface.
+func (e *BlockMismatchError) Error() string {
+	return fmt.Sprintf("block %d mismatches the stored one: %s is %s, but stored %s", e.Block, e.Field, e.Decided, e.Stored)
+}
+
+// ConsensusReplay feeds the stored events into the empty consensus engine, to rebuild its state.
+// Decided blocks aren't applied, but are checked against the stored ones. Txs aren't executed,
+// so the state roots aren't compared, only the last block state is checked to exist.
+// It's an EventSource for the engine, which works also for the events of sealed epochs.
+type ConsensusReplay struct {
+	store *Store
+	app   *app.Store
+	net   *lachesis.Config
+
+	lastBlock idx.Block
+	err       error // the first divergence
+}
+
+// NewConsensusReplay constructor.
+func NewConsensusReplay(store *Store, app *app.Store, net *lachesis.Config) *ConsensusReplay {
+	return &ConsensusReplay{
+		store: store,
+		app:   app,
+		net:   net,
+	}
+}
+
+// HasEvent returns true if event exists.
+func (r *ConsensusReplay) HasEvent(id hash.Event) bool {
+	return r.store.HasEvent(id)
+}
+
+// GetEvent returns stored event.
+func (r *ConsensusReplay) GetEvent(id hash.Event) *inter.Event {
+	return r.store.GetEvent(id)
+}
+
+// GetEventHeader returns header of stored event. Headers of sealed epochs are already
+// erased, so it's always read from the full event.
+func (r *ConsensusReplay) GetEventHeader(epoch idx.Epoch, id hash.Event) *inter.EventHeaderData {
+	e := r.store.GetEvent(id)
+	if e == nil {
+		return nil
+	}
+	return &e.EventHeaderData
+}
+
+// CheckPruned returns an error if events since the genesis aren't stored, so consensus
+// can't be replayed into the empty engine.
+func (r *ConsensusReplay) CheckPruned() error {
+	return r.checkPruned(firstEpochTransition - 1)
+}
+
+func (r *ConsensusReplay) checkPruned(from idx.Epoch) error {
+	if lowest := r.store.GetLowestEpoch(); lowest > from {
+		return fmt.Errorf("events before epoch %d are pruned, consensus can't be replayed", lowest)
+	}
+	return nil
+}
+
+// Run bootstraps the engine and processes the stored events epoch by epoch, in Lamport order.
+// It returns the first divergence of the decided blocks from the stored ones, by Atropos,
+// PrevHash, Time and Events.
+// Engine must be created over the empty store, with ConsensusReplay as events source.
+func (r *ConsensusReplay) Run(engine Consensus) error {
+	engine.Bootstrap(inter.ConsensusCallbacks{
+		ApplyBlock:              r.applyBlock,
+		SelectValidatorsGroup:   r.selectValidatorsGroup,
+		IsEventAllowedIntoBlock: r.isEventAllowedIntoBlock,
+	})
+	r.lastBlock, _ = engine.LastBlock()
+
+	if err := r.checkPruned(engine.GetEpoch()); err != nil {
+		return err
+	}
+
+	for epoch := engine.GetEpoch(); ; epoch++ {
+		var found bool
+		var processed int
+		r.store.ForEachEvent(epoch, func(e *inter.Event) bool {
+			found = true
+			current := engine.GetEpoch()
+			if current > epoch {
+				// the rest events of sealed epoch aren't needed for consensus
+				return false
+			}
+			if current < epoch {
+				r.fail(fmt.Errorf("epoch %d isn't sealed, but events of epoch %d are stored", current, epoch))
+				return false
+			}
+
+			if err := engine.ProcessEvent(e); err != nil {
+				r.fail(fmt.Errorf("failed to process event %s: %v", e.Hash().String(), err))
+				return false
+			}
+			processed++
+			return r.err == nil
+		})
+		if r.err != nil {
+			return r.err
+		}
+		if !found {
+			break
+		}
+		r.store.Log.Info("Replayed epoch", "epoch", epoch, "events", processed, "block", r.lastBlock)
+
+		// don't flush during the events iteration
+		if err := r.store.Commit(nil, false); err != nil {
+			return err
+		}
+	}
+
+	if r.store.GetBlock(r.lastBlock+1) != nil {
+		return fmt.Errorf("block %d is stored, but isn't decided by the stored events", r.lastBlock+1)
+	}
+	// state roots can't be compared without txs execution, so only check that the last one is available
+	last := r.store.GetBlock(r.lastBlock)
+	if _, err := r.app.OpenStateDB(last.Root); err != nil {
+		return fmt.Errorf("state root %s of block %d: %v", last.Root.String(), last.Index, err)
+	}
+
+	return r.store.Commit(nil, true)
+}
+
+// LastBlock returns index of the last decided block.
+func (r *ConsensusReplay) LastBlock() idx.Block {
+	return r.lastBlock
+}
+
+func (r *ConsensusReplay) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// applyBlock checks the decided block against the stored one, the same way as it's built by Service.
+func (r *ConsensusReplay) applyBlock(block *inter.Block, decidedFrame idx.Frame, cheaters inter.Cheaters) (newAppHash common.Hash, sealEpoch bool) {
+	r.lastBlock = block.Index
+
+	stored := r.store.GetBlock(block.Index)
+	if stored == nil {
+		r.fail(fmt.Errorf("block %d is decided, but isn't stored", block.Index))
+		return common.Hash{}, false
+	}
+
+	var epochStart inter.Timestamp
+	if stats := r.store.GetEpochStats(block.Atropos.Epoch() - 1); stats != nil {
+		epochStart = stats.End
+	}
+	sealEpoch = isEpochSealedBy(&r.net.Dag, block, decidedFrame, cheaters, epochStart)
+
+	block, _ = spillBlockEvents(r.store, block, r.net.Blocks.BlockGasHardLimit)
+	r.compare(block.Index, "atropos", stored.Atropos.String(), block.Atropos.String())
+	r.compare(block.Index, "prev hash", stored.PrevHash.String(), block.PrevHash.String())
+	r.compare(block.Index, "time", fmt.Sprint(uint64(stored.Time)), fmt.Sprint(uint64(block.Time)))
+	r.compare(block.Index, "events", stored.Events.String(), block.Events.String())
+
+	// app hash is a hash of executed txs, see Service.applyNewState
+	return stored.TxHash, sealEpoch
+}
+
+func (r *ConsensusReplay) compare(n idx.Block, field, stored, decided string) {
+	if stored != decided {
+		r.fail(&BlockMismatchError{
+			Block:   n,
+			Field:   field,
+			Stored:  stored,
+			Decided: decided,
+		})
+	}
+}
+
+func (r *ConsensusReplay) selectValidatorsGroup(oldEpoch, newEpoch idx.Epoch) *pos.Validators {
+	return readEpochValidators(r.app, newEpoch)
+}
+
+func (r *ConsensusReplay) isEventAllowedIntoBlock(header *inter.EventHeaderData, seqDepth idx.Event) bool {
+	return isEventAllowedIntoBlock(&r.net.Dag, header, seqDepth)
+}
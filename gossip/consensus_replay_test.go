@@ -0,0 +1,101 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+)
+
+func TestConsensusReplay(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	net := lachesis.FakeNetConfig(genesis.FakeValidators(5, big.NewInt(0), pos.StakeToBalance(1)))
+
+	adb := app.NewMemStore()
+	state, _, err := adb.ApplyGenesis(&net, nil)
+	if !assertar.NoError(err) {
+		return
+	}
+	store := NewMemStore()
+	genesisAtropos, genesisState, _, err := store.ApplyGenesis(&net, state)
+	if !assertar.NoError(err) {
+		return
+	}
+	newEngine := func(input poset.EventSource) *poset.Poset {
+		cdb := poset.NewMemStore()
+		err := cdb.ApplyGenesis(&net.Genesis, genesisAtropos, genesisState)
+		assertar.NoError(err)
+		return poset.New(net.Dag, cdb, input)
+	}
+
+	// make DAG and blocks, the same way as Service does
+	var lastBlock idx.Block
+	engine := newEngine(store)
+	engine.Bootstrap(inter.ConsensusCallbacks{
+		ApplyBlock: func(block *inter.Block, decidedFrame idx.Frame, cheaters inter.Cheaters) (common.Hash, bool) {
+			block, _ = spillBlockEvents(store, block, net.Blocks.BlockGasHardLimit)
+			block.Root = state.Root
+			store.SetBlock(block)
+			lastBlock = block.Index
+			return block.TxHash, isEpochSealedBy(&net.Dag, block, decidedFrame, cheaters, net.Genesis.Time)
+		},
+		IsEventAllowedIntoBlock: func(header *inter.EventHeaderData, seqDepth idx.Event) bool {
+			return isEventAllowedIntoBlock(&net.Dag, header, seqDepth)
+		},
+	})
+	inter.ForEachRandEvent(net.Genesis.Alloc.Validators.Validators().IDs(), 50, 3, nil, inter.ForEachEvent{
+		Process: func(e *inter.Event, name string) {
+			store.SetEvent(e)
+			err := engine.ProcessEvent(e)
+			if err != nil {
+				panic(err)
+			}
+		},
+		Build: func(e *inter.Event, name string) *inter.Event {
+			e.Epoch = 1
+			e.ClaimedTime = net.Genesis.Time + inter.Timestamp(e.Lamport)*inter.Timestamp(time.Second)
+			e.TxHash = hash.Of(e.Lamport.Bytes())
+			return engine.Prepare(e)
+		},
+	})
+	if !assertar.NotZero(lastBlock) {
+		return
+	}
+
+	replay := NewConsensusReplay(store, adb, &net)
+	assertar.NoError(replay.Run(newEngine(replay)))
+	assertar.Equal(lastBlock, replay.LastBlock())
+
+	// stored block is changed
+	block := *store.GetBlock(2)
+	block.Atropos = hash.FakeEvent()
+	store.SetBlock(&block)
+
+	replay = NewConsensusReplay(store, adb, &net)
+	err = replay.Run(newEngine(replay))
+	if assertar.IsType(&BlockMismatchError{}, err) {
+		assertar.Equal(idx.Block(2), err.(*BlockMismatchError).Block)
+		assertar.Equal("atropos", err.(*BlockMismatchError).Field)
+	}
+
+	// events are pruned
+	replay = NewConsensusReplay(store, adb, &net)
+	assertar.NoError(replay.CheckPruned())
+	store.PruneEpoch(1)
+	assertar.Error(replay.CheckPruned())
+	assertar.Error(replay.Run(newEngine(replay)))
+}
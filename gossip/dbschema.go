@@ -0,0 +1,40 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/keyspace"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+	"github.com/Fantom-foundation/go-lachesis/topicsdb"
+	"github.com/Fantom-foundation/go-lachesis/vector"
+)
+
+// DbSchema returns tables layout of all the node dbs.
+func DbSchema() keyspace.Schema {
+	mainTables := keyspace.FieldTables((*Store)(nil), "table")
+	mainTables = append(mainTables, keyspace.FieldTables((*app.Store)(nil), "table")...)
+	// see app.NewStore
+	mainTables = append(mainTables,
+		&keyspace.Table{Name: "Evm", Prefix: "M"},
+		&keyspace.Table{Name: "EvmLogs", Prefix: "L", Tables: keyspace.FieldTables((*topicsdb.Index)(nil), "table")},
+	)
+
+	return keyspace.Schema{
+		{
+			Pattern: "gossip-main",
+			Tables:  mainTables,
+		},
+		{
+			Pattern: "gossip-epoch-*",
+			Tables:  keyspace.Tables(epochStore{}),
+		},
+		{
+			Pattern: "poset-main",
+			Tables:  keyspace.FieldTables((*poset.Store)(nil), "table"),
+		},
+		{
+			Pattern: "poset-epoch-*",
+			Tables: keyspace.Nest(keyspace.FieldTables((*poset.Store)(nil), "epochTable"),
+				"v", keyspace.FieldTables((*vector.Index)(nil), "table")),
+		},
+	}
+}
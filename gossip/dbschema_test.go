@@ -0,0 +1,18 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDbSchema(t *testing.T) {
+	assertar := assert.New(t)
+
+	schema := DbSchema()
+	assertar.NoError(schema.Validate())
+
+	for _, name := range []string{"gossip-main", "gossip-epoch-1", "poset-main", "poset-epoch-1"} {
+		assertar.NotEmpty(schema.Find(name), name)
+	}
+}
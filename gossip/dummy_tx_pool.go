@@ -52,3 +52,16 @@ func (p *dummyTxPool) Pending() (map[common.Address]types.Transactions, error) {
 func (p *dummyTxPool) SubscribeNewTxsNotify(ch chan<- evmcore.NewTxsNotify) notify.Subscription {
 	return p.txFeed.Subscribe(ch)
 }
+
+// Get returns the transaction from the pool, or nil if it isn't known
+func (p *dummyTxPool) Get(hash common.Hash) *types.Transaction {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	for _, tx := range p.pool {
+		if tx.Hash() == hash {
+			return tx
+		}
+	}
+	return nil
+}
@@ -25,14 +25,17 @@ import (
 
 	"github.com/Fantom-foundation/go-lachesis/ethapi"
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
+	"github.com/Fantom-foundation/go-lachesis/finality"
 	"github.com/Fantom-foundation/go-lachesis/gossip/gasprice"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/inter/pos"
 	"github.com/Fantom-foundation/go-lachesis/inter/sfctype"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/readonly"
 	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis/sfc"
 	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis/sfc/sfcpos"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 	"github.com/Fantom-foundation/go-lachesis/topicsdb"
 	"github.com/Fantom-foundation/go-lachesis/tracing"
 )
@@ -100,7 +103,10 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	if header == nil {
 		return nil, nil, errors.New("header not found")
 	}
-	stateDb := b.svc.app.StateDB(header.Root)
+	stateDb, err := b.svc.app.OpenStateDB(header.Root)
+	if err != nil {
+		return nil, nil, err
+	}
 	return stateDb, header, nil
 }
 
@@ -209,6 +215,9 @@ func (b *EthAPIBackend) GetHeads(ctx context.Context, epoch rpc.BlockNumber) (he
 
 	if requested == current {
 		heads = b.svc.store.GetHeads(requested)
+	} else if b.svc.store.IsEpochPruned(requested) {
+		err = errors.New("epoch is pruned")
+		return
 	} else {
 		num, ok := b.svc.store.GetPacksNum(requested)
 		if !ok {
@@ -321,6 +330,9 @@ func (b *EthAPIBackend) GetEVM(ctx context.Context, msg evmcore.Message, state *
 }
 
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	if b.svc.config.ReadOnly {
+		return readonly.ErrReadOnly
+	}
 	err := b.svc.txpool.AddLocal(signedTx)
 	if err == nil {
 		// NOTE: only sent txs tracing, see TxPool.addTxs() for all
@@ -372,6 +384,9 @@ func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash)
 	}
 
 	event := b.svc.store.GetEvent(position.Event)
+	if event == nil && b.svc.store.IsEpochPruned(position.Event.Epoch()) {
+		return nil, 0, 0, nil
+	}
 	if position.EventOffset > uint32(event.Transactions.Len()) {
 		return nil, 0, 0, fmt.Errorf("transactions index is corrupted (offset is larger than number of txs in event), event=%s, txid=%s, block=%d, offset=%d, txs_num=%d",
 			position.Event.String(),
@@ -420,7 +435,7 @@ func (b *EthAPIBackend) Progress() ethapi.PeerProgress {
 }
 
 func (b *EthAPIBackend) ProtocolVersion() int {
-	return int(ProtocolVersions[len(ProtocolVersions)-1])
+	return int(ProtocolVersions[0])
 }
 
 func (b *EthAPIBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
@@ -474,6 +489,13 @@ func (b *EthAPIBackend) GetEpochStats(ctx context.Context, requestedEpoch rpc.Bl
 	}
 	stats.Epoch = epoch
 
+	// hashes of the cheaters evidences, to audit the slashing
+	if epoch == pendingEpoch {
+		stats.ForkEvidences = b.svc.store.GetForkEvidenceHashes(b.CurrentEpoch(ctx))
+	} else {
+		stats.ForkEvidences = b.svc.store.GetForkEvidenceHashes(epoch)
+	}
+
 	// read total reward weights from SFC contract
 	header := b.state.CurrentHeader()
 	statedb := b.svc.app.StateDB(header.Root)
@@ -618,6 +640,60 @@ func (b *EthAPIBackend) GetEventTime(ctx context.Context, id hash.Event, arrival
 	return t
 }
 
+// GetBlockProof returns finality proof of the block.
+func (b *EthAPIBackend) GetBlockProof(ctx context.Context, number rpc.BlockNumber) (*finality.Proof, error) {
+	if number == rpc.PendingBlockNumber {
+		return nil, errors.New("pending block request isn't allowed")
+	}
+	if number == rpc.LatestBlockNumber {
+		number = rpc.BlockNumber(b.state.CurrentHeader().Number.Uint64())
+	}
+	return getBlockProof(b.svc.store, b.svc.app, idx.Block(number))
+}
+
+// GetEpochTransition returns validators group transition to the epoch.
+// * When epoch is -2 or -1 the transition to the current epoch is returned.
+func (b *EthAPIBackend) GetEpochTransition(ctx context.Context, epoch rpc.BlockNumber) (*finality.EpochTransition, error) {
+	requested := b.svc.engine.GetEpoch()
+	if epoch >= 0 {
+		requested = idx.Epoch(epoch)
+	}
+	if requested < firstEpochTransition {
+		return nil, errors.New("genesis validators have no transition")
+	}
+	return b.svc.store.GetEpochTransition(requested), nil
+}
+
+// GetForkEvidence returns evidence of the cheater's fork in the epoch.
+// * When epoch is -2 or -1 the current epoch is used.
+func (b *EthAPIBackend) GetForkEvidence(ctx context.Context, stakerID idx.StakerID, epoch rpc.BlockNumber) (*inter.ForkEvidence, error) {
+	b.svc.engineMu.RLock()
+	requested := b.svc.engine.GetEpoch()
+	b.svc.engineMu.RUnlock()
+	if epoch >= 0 {
+		requested = idx.Epoch(epoch)
+	}
+	return b.svc.store.GetForkEvidence(requested, stakerID), nil
+}
+
+// GetElectionTrace returns election trace of the decided frame.
+// Only traces of the current epoch are kept.
+// * When epoch is -2 or -1 the current epoch is used.
+func (b *EthAPIBackend) GetElectionTrace(ctx context.Context, epoch rpc.BlockNumber, frame idx.Frame) (*election.Trace, error) {
+	if !b.svc.config.ElectionTraces {
+		return nil, errors.New("election traces are disabled (enable ElectionTraces)")
+	}
+
+	b.svc.engineMu.RLock() // lock because epoch DB may be recreated
+	defer b.svc.engineMu.RUnlock()
+
+	current := b.svc.engine.GetEpoch()
+	if epoch >= 0 && idx.Epoch(epoch) != current {
+		return nil, errors.New("election traces are kept only for the current epoch")
+	}
+	return b.svc.engine.GetElectionTrace(frame), nil
+}
+
 // TtfReport for a range of blocks
 func (b *EthAPIBackend) TtfReport(ctx context.Context, untilBlock rpc.BlockNumber, maxBlocks idx.Block, mode string) (map[hash.Event]time.Duration, error) {
 	if !b.svc.config.DecisiveEventsIndex {
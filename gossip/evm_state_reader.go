@@ -74,6 +74,9 @@ func (r *EvmStateReader) getBlock(h hash.Event, n idx.Block, readTxs bool) *evmc
 		return nil
 	}
 
+	// txs of pruned epochs aren't available
+	readTxs = readTxs && !r.store.IsEpochPruned(block.Atropos.Epoch())
+
 	transactions := make(types.Transactions, 0, len(block.Events)*10)
 	if readTxs {
 		txCount := uint(0)
@@ -112,5 +115,5 @@ func (r *EvmStateReader) getBlock(h hash.Event, n idx.Block, readTxs bool) *evmc
 }
 
 func (r *EvmStateReader) StateAt(root common.Hash) (*state.StateDB, error) {
-	return r.app.StateDB(root), nil
+	return r.app.OpenStateDB(root)
 }
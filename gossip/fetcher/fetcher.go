@@ -7,6 +7,7 @@ import (
 
 	"github.com/Fantom-foundation/go-lachesis/eventcheck"
 	"github.com/Fantom-foundation/go-lachesis/eventcheck/heavycheck"
+	"github.com/Fantom-foundation/go-lachesis/gossip/reputation"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/logger"
@@ -42,8 +43,8 @@ var (
 	errTerminated = errors.New("terminated")
 )
 
-// DropPeerFn is a callback type for dropping a peer detected as malicious.
-type DropPeerFn func(peer string)
+// PunishPeerFn is a callback type for scoring a peer's misbehaviour.
+type PunishPeerFn func(peer string, o reputation.Offence)
 
 // FilterInterestedFn returns only event which may be requested.
 type FilterInterestedFn func(ids hash.Events) hash.Events
@@ -104,7 +105,7 @@ type Fetcher struct {
 type Callback struct {
 	PushEvent      PushEventFn
 	OnlyInterested FilterInterestedFn
-	DropPeer       DropPeerFn
+	PunishPeer     PunishPeerFn
 
 	HeavyCheck *heavycheck.Checker
 	FirstCheck func(*inter.Event) error
@@ -216,7 +217,7 @@ func (f *Fetcher) Enqueue(peer string, inEvents inter.Events, t time.Time, fetch
 		err := f.callback.FirstCheck(e)
 		if eventcheck.IsBan(err) {
 			f.Periodic.Warn(time.Second, "Incoming event rejected", "event", e.Hash().String(), "creator", e.Creator, "err", err)
-			f.callback.DropPeer(peer)
+			f.callback.PunishPeer(peer, reputation.InvalidEvent)
 			return err
 		}
 		if err == nil {
@@ -232,7 +233,7 @@ func (f *Fetcher) Enqueue(peer string, inEvents inter.Events, t time.Time, fetch
 			if eventcheck.IsBan(err) {
 				e := res.Events[i]
 				f.Periodic.Warn(time.Second, "Incoming event rejected", "event", e.Hash().String(), "creator", e.Creator, "err", err)
-				f.callback.DropPeer(peer)
+				f.callback.PunishPeer(peer, reputation.InvalidEvent)
 				return
 			}
 			if err == nil {
@@ -273,12 +274,17 @@ func (f *Fetcher) loop() {
 	fetchTimer := time.NewTimer(0)
 
 	for {
-		// Clean up any expired event fetches
+		// Clean up any expired event fetches, punish the peer once per batch
+		expired := make(map[*announcesBatch]bool)
 		for id, announce := range f.fetching {
 			if time.Since(announce.batch.time) > fetchTimeout {
+				expired[announce.batch] = true
 				f.forgetHash(id)
 			}
 		}
+		for batch := range expired {
+			f.callback.PunishPeer(batch.peer, reputation.Timeout)
+		}
 		// Wait for an outside event to occur
 		select {
 		case <-f.quit:
@@ -293,6 +299,7 @@ func (f *Fetcher) loop() {
 			if count+len(notification.hashes) > hashLimit {
 				f.Periodic.Debug(time.Second, "Peer exceeded outstanding announces", "peer", notification.peer, "limit", hashLimit)
 				propAnnounceDOSMeter.Update(1)
+				f.callback.PunishPeer(notification.peer, reputation.UselessAnnounce)
 				break
 			}
 
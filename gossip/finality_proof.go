@@ -0,0 +1,199 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/finality"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// makeBlockProof builds finality proof of the block from the stored events of its epoch.
+// It returns nil if block isn't found.
+func makeBlockProof(s *Store, a *app.Store, n idx.Block) (*finality.Proof, error) {
+	if n == 0 {
+		return nil, errors.New("genesis block has no finality proof")
+	}
+	block := s.GetBlock(n)
+	if block == nil {
+		return nil, nil
+	}
+
+	epoch := block.Atropos.Epoch()
+	if s.IsEpochPruned(epoch) {
+		return nil, fmt.Errorf("events of epoch %d are pruned", epoch)
+	}
+	atropos := s.GetEvent(block.Atropos)
+	if atropos == nil {
+		return nil, fmt.Errorf("Atropos %s isn't found", block.Atropos.String())
+	}
+
+	validators := readFinalityValidators(a, epoch)
+
+	// the events of the Atropos frame and the next 2 frames are iterated in Lamport order,
+	// so every root of frame+2 may decide the election after its ancestors are collected
+	frame := atropos.Frame
+	var (
+		headers   []*inter.EventHeader
+		collected = hash.EventsSet{}
+		parentsOf int
+		proof     *finality.Proof
+		err       = finality.ErrNotDecided
+	)
+	collect := func(h *inter.EventHeader) {
+		if !collected.Contains(h.Hash()) {
+			collected.Add(h.Hash())
+			headers = append(headers, h)
+		}
+	}
+	s.ForEachEvent(epoch, func(e *inter.Event) bool {
+		if e.Frame < frame || e.Frame > frame+2 {
+			return true
+		}
+		collect(&e.EventHeader)
+		if !e.IsRoot || e.Frame != frame+2 {
+			return true
+		}
+
+		// parents of the lower frames
+		for ; parentsOf < len(headers); parentsOf++ {
+			for _, p := range headers[parentsOf].Parents {
+				if collected.Contains(p) {
+					continue
+				}
+				if parent := s.GetEvent(p); parent != nil {
+					collect(&parent.EventHeader)
+				}
+			}
+		}
+
+		proof, err = finality.NewProof(n, &atropos.EventHeader, validators, headers)
+		return err != nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+// getBlockProof returns finality proof of the block, see makeBlockProof.
+// Proofs are built one at a time and cached, because building reads the events of the Atropos frames.
+func getBlockProof(s *Store, a *app.Store, n idx.Block) (*finality.Proof, error) {
+	s.mutex.BlockProofs.Lock()
+	defer s.mutex.BlockProofs.Unlock()
+
+	if c := s.cache.BlockProofs; c != nil {
+		if res, ok := c.Get(n); ok {
+			res := res.(*blockProofRes)
+			return res.proof, res.err
+		}
+	}
+
+	proof, err := makeBlockProof(s, a, n)
+	if proof == nil && err == nil {
+		// block isn't found yet
+		return nil, nil
+	}
+	if c := s.cache.BlockProofs; c != nil {
+		c.Add(n, &blockProofRes{proof, err})
+	}
+	return proof, err
+}
+
+type blockProofRes struct {
+	proof *finality.Proof
+	err   error
+}
+
+// makeEpochTransition builds the validators group transition from the epoch, which is sealed by the block.
+// The transition is without the sealing block proof, which is built later by proveEpochTransition.
+func makeEpochTransition(a *app.Store, sealed idx.Epoch, sealing idx.Block) *finality.EpochTransition {
+	return &finality.EpochTransition{
+		Epoch:              sealed + 1,
+		PrevValidatorsHash: finality.ValidatorsHash(readFinalityValidators(a, sealed)),
+		Validators:         readFinalityValidators(a, sealed+1),
+		SealingBlock:       sealing,
+	}
+}
+
+// proveEpochTransition returns the stored transition to the epoch with the sealing block proof,
+// if the proof is missing. It returns nil if there is nothing to store.
+// The proof is built from the stored events of the sealed epoch, so it must be called before the epoch pruning.
+func proveEpochTransition(s *Store, a *app.Store, epoch idx.Epoch) *finality.EpochTransition {
+	if epoch < firstEpochTransition {
+		return nil
+	}
+	t := s.GetEpochTransition(epoch)
+	if t == nil || t.Proof != nil {
+		return nil
+	}
+
+	proof, err := getBlockProof(s, a, t.SealingBlock)
+	if err != nil || proof == nil {
+		s.Log.Warn("Failed to build sealing block proof", "epoch", epoch-1, "block", t.SealingBlock, "err", err)
+		return nil
+	}
+	t.Proof = proof
+
+	return t
+}
+
+// scheduleProving wakes up the sealing proofs builder without blocking.
+func (s *Service) scheduleProving() {
+	select {
+	case s.proving <- struct{}{}:
+	default:
+	}
+}
+
+// provingLoop builds the sealing proof of the transition to the current epoch on every new epoch.
+// Proofs are built without engineMu, because building reads the events of the sealed epoch.
+func (s *Service) provingLoop() {
+	defer s.wg.Done()
+
+	for {
+		s.engineMu.RLock()
+		epoch := s.engine.GetEpoch()
+		s.engineMu.RUnlock()
+
+		s.storeEpochTransitionProof(epoch)
+
+		select {
+		case <-s.proving:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// storeEpochTransitionProof builds and stores the missing sealing proof of the transition to the epoch.
+func (s *Service) storeEpochTransitionProof(epoch idx.Epoch) {
+	t := proveEpochTransition(s.store, s.app, epoch)
+	if t == nil {
+		return
+	}
+
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+
+	s.store.SetEpochTransition(t)
+}
+
+// readFinalityValidators returns validators group of the epoch with their addresses.
+func readFinalityValidators(a *app.Store, epoch idx.Epoch) []finality.Validator {
+	stakes := readEpochValidators(a, epoch)
+	addrs := ReadEpochPubKeys(a, epoch).Addresses
+	validators := make([]finality.Validator, 0, stakes.Len())
+	for _, id := range stakes.SortedIDs() {
+		validators = append(validators, finality.Validator{
+			ID:      id,
+			Stake:   stakes.Get(id),
+			Address: addrs[id],
+		})
+	}
+	return validators
+}
@@ -0,0 +1,246 @@
+package gossip
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/finality"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+)
+
+func TestBlockProof(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store, adb, lastBlock := makeSignedBlocks(t)
+	if store == nil {
+		return
+	}
+
+	validators := readEpochValidators(adb, 1)
+	addrs := ReadEpochPubKeys(adb, 1).Addresses
+
+	proof, err := makeBlockProof(store, adb, 0)
+	assertar.Error(err, "genesis")
+	assertar.Nil(proof)
+	proof, err = makeBlockProof(store, adb, lastBlock+1)
+	assertar.NoError(err)
+	assertar.Nil(proof)
+
+	var notFirst int // Atropos isn't the first candidate, so "no" decisions are proven
+	for n := idx.Block(1); n < lastBlock; n++ {
+		if store.GetEvent(store.GetBlock(n).Atropos).Creator != validators.SortedIDs()[0] {
+			notFirst++
+		}
+		proof, err := makeBlockProof(store, adb, n)
+		if !assertar.NoError(err, "block %d", n) {
+			return
+		}
+		assertar.Equal(store.GetBlock(n).Atropos, proof.Atropos)
+		assertar.Equal(common.Hash(store.GetBlock(n).Atropos), proof.BlockHash())
+		assertar.NoError(finality.Verify(proof, finality.FirstBlock, validators, addrs), "block %d", n)
+
+		// proof is transferable
+		raw, err := rlp.EncodeToBytes(proof)
+		if !assertar.NoError(err) {
+			return
+		}
+		got := &finality.Proof{}
+		if !assertar.NoError(rlp.DecodeBytes(raw, got)) {
+			return
+		}
+		assertar.NoError(finality.Verify(got, finality.FirstBlock, validators, addrs), "decoded block %d", n)
+	}
+	assertar.NotZero(notFirst)
+
+	// tampered proofs
+	proof, err = makeBlockProof(store, adb, 1)
+	if !assertar.NoError(err) {
+		return
+	}
+
+	other := pos.EqualStakeValidators(validators.IDs(), 2)
+	assertar.Equal(finality.ErrValidatorsMismatch, finality.Verify(proof, finality.FirstBlock, other, addrs))
+
+	forged := *proof.Headers[len(proof.Headers)-1]
+	forged.Frame++
+	proof.Headers[len(proof.Headers)-1] = &forged
+	assertar.Equal(finality.ErrWrongSig, finality.Verify(proof, finality.FirstBlock, validators, addrs))
+
+	proof, _ = makeBlockProof(store, adb, 1)
+	proof.Atropos = hash.FakeEvent()
+	assertar.Equal(finality.ErrWrongEpoch, finality.Verify(proof, finality.FirstBlock, validators, addrs))
+
+	proof, _ = makeBlockProof(store, adb, 1)
+	proof.Block++
+	assertar.Equal(finality.ErrWrongBlock, finality.Verify(proof, finality.FirstBlock, validators, addrs))
+	proof.Block--
+	assertar.Equal(finality.ErrWrongBlock, finality.Verify(proof, finality.FirstBlock+1, validators, addrs))
+
+	proof, _ = makeBlockProof(store, adb, 1)
+	proof.Headers = proof.Headers[:len(proof.Headers)-1]
+	assertar.Equal(finality.ErrNotDecided, finality.Verify(proof, finality.FirstBlock, validators, addrs))
+
+	// other roots of the Atropos frame aren't elected
+	proof, _ = makeBlockProof(store, adb, 1)
+	atropos := store.GetEvent(proof.Atropos)
+	var others int
+	for _, h := range proof.Headers {
+		if h.IsRoot && h.Frame == atropos.Frame && h.Hash() != proof.Atropos {
+			others++
+			forged := *proof
+			forged.Atropos = h.Hash()
+			assertar.Equal(finality.ErrNotDecided, finality.Verify(&forged, finality.FirstBlock, validators, addrs))
+
+			_, err := finality.NewProof(1, h, proof.Validators, proof.Headers)
+			assertar.Equal(finality.ErrNotDecided, err)
+		}
+	}
+	assertar.NotZero(others)
+
+	// proof is cached
+	proof, err = getBlockProof(store, adb, 1)
+	assertar.NoError(err)
+	again, _ := getBlockProof(store, adb, 1)
+	assertar.True(proof == again)
+}
+
+func TestEpochTransition(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store, adb, lastBlock := makeSignedBlocks(t)
+	if store == nil {
+		return
+	}
+
+	// the 2nd epoch is without one of the genesis validators
+	adb.SetEpochValidators(2, adb.GetEpochValidators(1)[1:])
+	sealing := lastBlock - 1
+
+	store.SetEpochTransition(makeEpochTransition(adb, 1, sealing))
+	assertar.Nil(store.GetEpochTransition(2).Proof)
+	assertar.Nil(proveEpochTransition(store, adb, 1), "genesis")
+	assertar.Nil(proveEpochTransition(store, adb, 3), "not sealed")
+	store.SetEpochTransition(proveEpochTransition(store, adb, 2))
+	assertar.Nil(proveEpochTransition(store, adb, 2), "already proven")
+	transition := store.GetEpochTransition(2)
+	if !assertar.NotNil(transition) || !assertar.NotNil(transition.Proof) {
+		return
+	}
+	assertar.Equal(sealing, transition.SealingBlock)
+	assertar.Equal(store.GetBlock(sealing).Atropos, transition.Proof.Atropos)
+	assertar.Nil(store.GetEpochTransition(3))
+
+	// light client walks from the genesis validators
+	genesisValidators := readFinalityValidators(adb, 1)
+	got, firstBlock, err := finality.WalkEpochs(genesisValidators, finality.FirstBlock, []*finality.EpochTransition{transition})
+	assertar.NoError(err)
+	assertar.Equal(sealing+1, firstBlock)
+	assertar.Equal(readFinalityValidators(adb, 2), got)
+	assertar.Equal(4, len(got))
+
+	// validators order doesn't matter
+	reversed := make([]finality.Validator, len(genesisValidators))
+	for i, v := range genesisValidators {
+		reversed[len(reversed)-1-i] = v
+	}
+	assertar.NoError(finality.VerifyTransition(transition, reversed, finality.FirstBlock))
+
+	// wrong transitions
+	assertar.Equal(finality.ErrPrevValidatorsMismatch, finality.VerifyTransition(transition, got, finality.FirstBlock))
+	_, _, err = finality.WalkEpochs(genesisValidators, finality.FirstBlock, []*finality.EpochTransition{transition, transition})
+	assertar.Equal(finality.ErrEpochGap, err)
+
+	wrong := *transition
+	wrong.SealingBlock--
+	assertar.Equal(finality.ErrWrongSealing, finality.VerifyTransition(&wrong, genesisValidators, finality.FirstBlock))
+
+	wrong = *transition
+	wrong.Epoch++
+	assertar.Equal(finality.ErrWrongSealing, finality.VerifyTransition(&wrong, genesisValidators, finality.FirstBlock))
+
+	wrong = *transition
+	wrong.Proof = nil
+	assertar.Equal(finality.ErrNoProof, finality.VerifyTransition(&wrong, genesisValidators, finality.FirstBlock))
+	store.SetEpochTransition(&wrong)
+	assertar.Nil(store.GetEpochTransition(2).Proof)
+
+	wrong = *transition
+	wrong.Validators = nil
+	assertar.Equal(finality.ErrNoValidators, finality.VerifyTransition(&wrong, genesisValidators, finality.FirstBlock))
+}
+
+// makeSignedBlocks makes blocks of the 1st epoch from the DAG, which is signed by the genesis validators.
+func makeSignedBlocks(t *testing.T) (*Store, *app.Store, idx.Block) {
+	assertar := assert.New(t)
+
+	net := lachesis.FakeNetConfig(genesis.FakeValidators(5, big.NewInt(0), pos.StakeToBalance(1)))
+
+	adb := app.NewMemStore()
+	state, _, err := adb.ApplyGenesis(&net, nil)
+	if !assertar.NoError(err) {
+		return nil, nil, 0
+	}
+	store := NewMemStore()
+	genesisAtropos, genesisState, _, err := store.ApplyGenesis(&net, state)
+	if !assertar.NoError(err) {
+		return nil, nil, 0
+	}
+	cdb := poset.NewMemStore()
+	if !assertar.NoError(cdb.ApplyGenesis(&net.Genesis, genesisAtropos, genesisState)) {
+		return nil, nil, 0
+	}
+
+	keys := make(map[idx.StakerID]*ecdsa.PrivateKey)
+	for _, v := range net.Genesis.Alloc.Validators {
+		keys[v.ID] = net.Genesis.Alloc.Accounts[v.Address].PrivateKey
+	}
+
+	// make signed DAG and blocks
+	var lastBlock idx.Block
+	engine := poset.New(net.Dag, cdb, store)
+	engine.Bootstrap(inter.ConsensusCallbacks{
+		ApplyBlock: func(block *inter.Block, decidedFrame idx.Frame, cheaters inter.Cheaters) (common.Hash, bool) {
+			store.SetBlock(block)
+			lastBlock = block.Index
+			return common.Hash{}, false
+		},
+	})
+	inter.ForEachRandEvent(net.Genesis.Alloc.Validators.Validators().IDs(), 30, 3, nil, inter.ForEachEvent{
+		Process: func(e *inter.Event, name string) {
+			store.SetEvent(e)
+			if err := engine.ProcessEvent(e); err != nil {
+				panic(err)
+			}
+		},
+		Build: func(e *inter.Event, name string) *inter.Event {
+			e.Epoch = 1
+			e.ClaimedTime = net.Genesis.Time + inter.Timestamp(e.Lamport)*inter.Timestamp(time.Second)
+			e = engine.Prepare(e)
+			if err := e.SignBy(keys[e.Creator]); err != nil {
+				panic(err)
+			}
+			return e
+		},
+	})
+	if !assertar.True(lastBlock > 2) {
+		return nil, nil, 0
+	}
+
+	return store, adb, lastBlock
+}
@@ -0,0 +1,51 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// findForkEvidence looks for a pair of the cheater's events with the same Seq.
+// Any fork has such a pair, because the branches are consecutive chains of self-parents.
+// It scans the whole epoch, but it's done once per cheater, see Service.recordForkEvidences.
+func findForkEvidence(s *Store, epoch idx.Epoch, cheater idx.StakerID) *inter.ForkEvidence {
+	var ev *inter.ForkEvidence
+	bySeq := make(map[idx.Event]*inter.Event)
+	s.ForEachEvent(epoch, func(e *inter.Event) bool {
+		if e.Creator != cheater {
+			return true
+		}
+		if another, ok := bySeq[e.Seq]; ok {
+			ev = inter.NewForkEvidence(&another.EventHeader, &e.EventHeader)
+			return false
+		}
+		bySeq[e.Seq] = e
+		return true
+	})
+	return ev
+}
+
+// recordForkEvidences stores the evidences of the new cheaters of the epoch.
+func (s *Service) recordForkEvidences(epoch idx.Epoch, cheaters inter.Cheaters) {
+	// s.engineMu is locked here
+
+	missed := &s.missedForkEvidences
+	if missed.cheaters == nil || missed.epoch != epoch {
+		missed.epoch = epoch
+		missed.cheaters = make(map[idx.StakerID]bool)
+	}
+
+	for _, cheater := range cheaters {
+		if missed.cheaters[cheater] || s.store.HasForkEvidence(epoch, cheater) {
+			continue
+		}
+		ev := findForkEvidence(s.store, epoch, cheater)
+		if ev == nil {
+			// the epoch events don't change, so don't search again
+			missed.cheaters[cheater] = true
+			s.Log.Warn("Fork evidence isn't found", "epoch", epoch, "cheater", cheater)
+			continue
+		}
+		s.store.SetForkEvidence(epoch, cheater, ev)
+	}
+}
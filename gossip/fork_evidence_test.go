@@ -0,0 +1,72 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestForkEvidence(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := NewMemStore()
+	key, _ := crypto.GenerateKey()
+	const epoch = idx.Epoch(2)
+
+	newEvent := func(creator idx.StakerID, seq idx.Event, lamport idx.Lamport, selfParent *inter.Event) *inter.Event {
+		e := inter.NewEvent()
+		e.Epoch = epoch
+		e.Creator = creator
+		e.Seq = seq
+		e.Lamport = lamport
+		if selfParent != nil {
+			e.Parents = hash.Events{selfParent.Hash()}
+		}
+		if !assertar.NoError(e.SignBy(key)) {
+			t.FailNow()
+		}
+		store.SetEvent(e)
+		return e
+	}
+
+	// honest validator
+	h1 := newEvent(1, 1, 1, nil)
+	newEvent(1, 2, 2, h1)
+	// cheater forks from its first event
+	c1 := newEvent(2, 1, 1, nil)
+	c2a := newEvent(2, 2, 2, c1)
+	newEvent(2, 3, 3, c2a)
+	c2b := newEvent(2, 2, 4, c1)
+
+	assertar.Nil(findForkEvidence(store, epoch, 1))
+	assertar.Nil(findForkEvidence(store, epoch+1, 2))
+
+	ev := findForkEvidence(store, epoch, 2)
+	if !assertar.NotNil(ev) {
+		return
+	}
+	assertar.NoError(ev.Verify(crypto.PubkeyToAddress(key.PublicKey)))
+	assertar.Equal(hash.NewEventsSet(c2a.Hash(), c2b.Hash()), hash.NewEventsSet(ev.A.Hash(), ev.B.Hash()))
+
+	assertar.False(store.HasForkEvidence(epoch, 2))
+	store.SetForkEvidence(epoch, 2, ev)
+	assertar.True(store.HasForkEvidence(epoch, 2))
+	assertar.False(store.HasForkEvidence(epoch+1, 2))
+
+	got := store.GetForkEvidence(epoch, 2)
+	if assertar.NotNil(got) {
+		assertar.Equal(ev.Hash(), got.Hash())
+		assertar.NoError(got.Verify(crypto.PubkeyToAddress(key.PublicKey)))
+	}
+	assertar.Nil(store.GetForkEvidence(epoch, 1))
+	assertar.Equal([]common.Hash{ev.Hash()}, store.GetForkEvidenceHashes(epoch))
+	assertar.Empty(store.GetForkEvidenceHashes(epoch + 1))
+}
@@ -20,9 +20,11 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/gossip/fetcher"
 	"github.com/Fantom-foundation/go-lachesis/gossip/ordering"
 	"github.com/Fantom-foundation/go-lachesis/gossip/packsdownloader"
+	"github.com/Fantom-foundation/go-lachesis/gossip/reputation"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/table"
 	"github.com/Fantom-foundation/go-lachesis/logger"
 )
 
@@ -39,8 +41,21 @@ const (
 	eventsBuffSize = 2048
 )
 
+// protocolError is a violation of the protocol by the peer.
+type protocolError struct {
+	code errCode
+	msg  string
+}
+
+func (e *protocolError) Error() string {
+	return fmt.Sprintf("%v - %v", e.code, e.msg)
+}
+
 func errResp(code errCode, format string, v ...interface{}) error {
-	return fmt.Errorf("%v - %v", code, fmt.Sprintf(format, v...))
+	return &protocolError{
+		code: code,
+		msg:  fmt.Sprintf(format, v...),
+	}
 }
 
 func checkLenLimits(size int, v interface{}) error {
@@ -71,6 +86,7 @@ type ProtocolManager struct {
 	peers *peerSet
 
 	serverPool *serverPool
+	reputation *reputation.Reputation
 
 	txsCh  chan evmcore.NewTxsNotify
 	txsSub notify.Subscription
@@ -139,36 +155,18 @@ func NewProtocolManager(
 
 	pm.SetName("PM")
 
+	pm.reputation = reputation.New(config.Protocol.Reputation, table.New(s.table.Peers, []byte("ban/")))
+
 	pm.fetcher, pm.buffer = pm.makeFetcher(checkers)
-	pm.downloader = packsdownloader.New(pm.fetcher, pm.onlyNotConnectedEvents, pm.removePeer)
+	pm.downloader = packsdownloader.New(pm.fetcher, pm.onlyNotConnectedEvents, pm.punish)
 
 	return pm, nil
 }
 
 func (pm *ProtocolManager) makeFetcher(checkers *eventcheck.Checkers) (*fetcher.Fetcher, *ordering.EventBuffer) {
 	// checkers
-	firstCheck := func(e *inter.Event) error {
-		if err := checkers.Basiccheck.Validate(e); err != nil {
-			return err
-		}
-		if err := checkers.Epochcheck.Validate(e); err != nil {
-			return err
-		}
-		return nil
-	}
-	bufferedCheck := func(e *inter.Event, parents []*inter.EventHeaderData) error {
-		var selfParent *inter.EventHeaderData
-		if e.SelfParent() != nil {
-			selfParent = parents[0]
-		}
-		if err := checkers.Parentscheck.Validate(e, parents); err != nil {
-			return err
-		}
-		if err := checkers.Gaspowercheck.Validate(e, selfParent); err != nil {
-			return err
-		}
-		return nil
-	}
+	firstCheck := checkers.ValidateLight
+	bufferedCheck := checkers.ValidateParents
 
 	// DAG callbacks
 	buffer := ordering.New(eventsBuffSize, ordering.Callback{
@@ -197,7 +195,7 @@ func (pm *ProtocolManager) makeFetcher(checkers *eventcheck.Checkers) (*fetcher.
 		Drop: func(e *inter.Event, peer string, err error) {
 			if eventcheck.IsBan(err) {
 				log.Warn("Incoming event rejected", "event", e.Hash().String(), "creator", e.Creator, "err", err)
-				pm.removePeer(peer)
+				pm.punish(peer, reputation.InvalidEvent)
 			}
 		},
 
@@ -215,7 +213,7 @@ func (pm *ProtocolManager) makeFetcher(checkers *eventcheck.Checkers) (*fetcher.
 	newFetcher := fetcher.New(fetcher.Callback{
 		PushEvent:      buffer.PushEvent,
 		OnlyInterested: pm.onlyInterestedEvents,
-		DropPeer:       pm.removePeer,
+		PunishPeer:     pm.punish,
 		FirstCheck:     firstCheck,
 		HeavyCheck:     checkers.Heavycheck,
 	})
@@ -320,6 +318,18 @@ func (pm *ProtocolManager) removePeer(id string) {
 	}
 }
 
+// punish scores the peer's offence, and removes the peer if it gets banned.
+func (pm *ProtocolManager) punish(id string, o reputation.Offence) {
+	peer := pm.peers.Peer(id)
+	if peer == nil {
+		return
+	}
+	if pm.reputation.Punish(peer.ID(), o) == reputation.Banned {
+		log.Warn("Removing banned peer", "peer", id, "offence", o)
+		pm.removePeer(id)
+	}
+}
+
 func (pm *ProtocolManager) Start(maxPeers int) {
 	pm.maxPeers = maxPeers
 
@@ -390,6 +400,7 @@ func (pm *ProtocolManager) myProgress() PeerProgress {
 		NumOfBlocks:  blockI,
 		LastBlock:    block,
 		LastPackInfo: pm.store.GetPackInfoOrDefault(epoch, pm.store.GetPacksNumOrDefault(epoch)-1),
+		LowestEpoch:  pm.store.GetLowestEpoch(),
 	}
 }
 
@@ -411,6 +422,9 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	if pm.peers.Len() >= pm.maxPeers && !p.Peer.Info().Network.Trusted {
 		return p2p.DiscTooManyPeers
 	}
+	if pm.reputation.Status(p.ID()) == reputation.Banned {
+		return p2p.DiscUselessPeer
+	}
 	p.Log().Debug("Peer connected", "name", p.Name())
 
 	// Execute the handshake
@@ -438,25 +452,39 @@ func (pm *ProtocolManager) handle(p *peer) error {
 
 	// Handle incoming messages until the connection is torn down
 	for {
-		if err := pm.handleMsg(p); err != nil {
-			p.Log().Debug("Message handling failed", "err", err)
+		err := pm.handleMsg(p)
+		if err == nil {
+			continue
+		}
+		p.Log().Debug("Message handling failed", "err", err)
+		perr, ok := err.(*protocolError)
+		if !ok {
+			return err
+		}
+		// protocol violations are scored, the peer is disconnected only if it gets banned
+		offence := reputation.InvalidMsg
+		if perr.code == ErrMsgTooLarge {
+			offence = reputation.OversizedResponse
+		}
+		if pm.reputation.Punish(p.ID(), offence) == reputation.Banned {
 			return err
 		}
 	}
 }
 
 // handleMsg is invoked whenever an inbound message is received from a remote
-// peer. The remote connection is torn down upon returning any error.
+// peer. Protocol violations are returned as *protocolError, the remote connection
+// is torn down upon returning any other error.
 func (pm *ProtocolManager) handleMsg(p *peer) error {
 	// Read the next message from the remote peer, and ensure it's fully consumed
 	msg, err := p.rw.ReadMsg()
 	if err != nil {
 		return err
 	}
+	defer msg.Discard()
 	if msg.Size > protocolMaxMsgSize {
 		return errResp(ErrMsgTooLarge, "%v > %v", msg.Size, protocolMaxMsgSize)
 	}
-	defer msg.Discard()
 
 	myEpoch := pm.engine.GetEpoch()
 	peerDwnlr := pm.downloader.Peer(p.id)
@@ -468,8 +496,8 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		return errResp(ErrExtraStatusMsg, "uncontrolled status message")
 
 	case msg.Code == ProgressMsg:
-		var progress PeerProgress
-		if err := msg.Decode(&progress); err != nil {
+		progress, err := p.decodeProgress(msg)
+		if err != nil {
 			return errResp(ErrDecode, "%v: %v", msg, err)
 		}
 		p.SetProgress(progress)
@@ -481,6 +509,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		_ = pm.downloader.RegisterPeer(packsdownloader.Peer{
 			ID:               p.id,
 			Epoch:            p.progress.Epoch,
+			LowestEpoch:      p.progress.LowestEpoch,
 			RequestPack:      p.RequestPack,
 			RequestPackInfos: p.RequestPackInfos,
 		}, myEpoch)
@@ -491,7 +520,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 
 	case msg.Code == NewEventHashesMsg:
-		if pm.fetcher.Overloaded() {
+		if pm.fetcher.Overloaded() || pm.reputation.Status(p.ID()) == reputation.Throttled {
 			break
 		}
 		// Fresh events arrived, make sure we have a valid and fresh graph to handle them
@@ -516,19 +545,62 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		if pm.fetcher.Overloaded() {
 			break
 		}
-		var events []*inter.Event
-		if err := msg.Decode(&events); err != nil {
+		var events inter.Events
+		if err := p.decode(msg, &events); err != nil {
 			return errResp(ErrDecode, "%v: %v", msg, err)
 		}
 		if err := checkLenLimits(len(events), events); err != nil {
 			return err
 		}
+		// replies to our requests aren't throttled, only the broadcasts are
+		if !p.ForgetRequested(events) && pm.reputation.Status(p.ID()) == reputation.Throttled {
+			break
+		}
 		// Mark the hashes as present at the remote node
 		for _, e := range events {
 			p.MarkEvent(e.Hash())
 		}
 		_ = pm.fetcher.Enqueue(p.id, events, time.Now(), p.RequestEvents)
 
+	case msg.Code == CompactEventsMsg:
+		if p.version < lachesis63 {
+			return errResp(ErrInvalidMsgCode, "%v", msg.Code)
+		}
+		if pm.fetcher.Overloaded() || pm.reputation.Status(p.ID()) == reputation.Throttled {
+			break
+		}
+		var compact []*compactEvent
+		if err := p.decode(msg, &compact); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		if err := checkLenLimits(len(compact), compact); err != nil {
+			return err
+		}
+		events := make([]*inter.Event, 0, len(compact))
+		missing := make(hash.Events, 0, len(compact))
+		for _, c := range compact {
+			if c.Header == nil {
+				return errResp(ErrDecode, "%v: event header is nil", msg)
+			}
+			// Mark the hashes as present at the remote node
+			p.MarkEvent(c.Header.Hash())
+			for _, h := range c.TxHashes {
+				p.MarkTransaction(h)
+			}
+			// restore the event from the local txpool, or request the full event if any tx is unknown
+			if e := pm.restoreCompactEvent(c); e != nil {
+				events = append(events, e)
+			} else {
+				missing = append(missing, c.Header.Hash())
+			}
+		}
+		if len(events) != 0 {
+			_ = pm.fetcher.Enqueue(p.id, events, time.Now(), p.RequestEvents)
+		}
+		if len(missing) != 0 {
+			_ = pm.fetcher.Notify(p.id, missing, time.Now(), p.RequestEvents)
+		}
+
 	case msg.Code == EvmTxMsg:
 		// Transactions arrived, make sure we have a valid and fresh graph to handle them
 		if atomic.LoadUint32(&pm.synced) == 0 {
@@ -647,7 +719,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 
 		var infos packInfosData
-		if err := msg.Decode(&infos); err != nil {
+		if err := p.decode(msg, &infos); err != nil {
 			return errResp(ErrDecode, "%v: %v", msg, err)
 		}
 		if err := checkLenLimits(len(infos.Infos), infos); err != nil {
@@ -691,12 +763,62 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		// Notify downloader about new pack
 		_ = peerDwnlr.NotifyPack(pack.Epoch, pack.Index, pack.IDs, time.Now(), p.RequestEvents)
 
+	case msg.Code == GetEpochTransitionsMsg:
+		if p.version < lachesis63 {
+			return errResp(ErrInvalidMsgCode, "%v", msg.Code)
+		}
+		var request getEpochTransitionsData
+		if err := msg.Decode(&request); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		if err := checkLenLimits(int(request.Amount), request); err != nil {
+			return err
+		}
+
+		from := request.From
+		if from < firstEpochTransition {
+			from = firstEpochTransition
+		}
+		rawTransitions := make([]rlp.RawValue, 0, softLimitItems)
+		size := 0
+		for i := uint32(0); i < request.Amount && len(rawTransitions) < softLimitItems; i++ {
+			raw := pm.store.GetEpochTransitionRLP(from + idx.Epoch(i))
+			if raw == nil {
+				// return only consecutive transitions
+				break
+			}
+			rawTransitions = append(rawTransitions, raw)
+			size += len(raw)
+			if size >= softResponseLimitSize {
+				break
+			}
+		}
+		if len(rawTransitions) != 0 {
+			_ = p.SendEpochTransitionsRLP(rawTransitions)
+		}
+
 	default:
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
 	}
 	return nil
 }
 
+// restoreCompactEvent fills the event's transactions from the txpool.
+// It returns nil if any transaction isn't in the pool.
+func (pm *ProtocolManager) restoreCompactEvent(c *compactEvent) *inter.Event {
+	txs := make(types.Transactions, len(c.TxHashes))
+	for i, h := range c.TxHashes {
+		txs[i] = pm.txpool.Get(h)
+		if txs[i] == nil {
+			return nil
+		}
+	}
+	return &inter.Event{
+		EventHeader:  *c.Header,
+		Transactions: txs,
+	}
+}
+
 func (pm *ProtocolManager) decideBroadcastAggressiveness(size int, passed time.Duration, peersNum int) int {
 	percents := 100
 	maxPercents := 1000000 * percents
@@ -826,12 +948,12 @@ func (pm *ProtocolManager) onNewEpochLoop() {
 	for {
 		select {
 		case myEpoch := <-pm.newEpochsCh:
-			peerEpoch := func(peer string) idx.Epoch {
+			peerEpochs := func(peer string) (lowest, epoch idx.Epoch) {
 				p := pm.peers.Peer(peer)
 				if p == nil {
-					return 0
+					return 0, 0
 				}
-				return p.progress.Epoch
+				return p.progress.LowestEpoch, p.progress.Epoch
 			}
 			if atomic.LoadUint32(&pm.synced) == 0 {
 				synced := false
@@ -846,7 +968,7 @@ func (pm *ProtocolManager) onNewEpochLoop() {
 				}
 			}
 			pm.buffer.Clear()
-			pm.downloader.OnNewEpoch(myEpoch, peerEpoch)
+			pm.downloader.OnNewEpoch(myEpoch, peerEpochs)
 		// Err() channel will be closed when unsubscribing.
 		case <-pm.txsSub.Err():
 			return
@@ -8,12 +8,15 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/Fantom-foundation/go-lachesis/app"
 	"github.com/Fantom-foundation/go-lachesis/eventcheck"
+	"github.com/Fantom-foundation/go-lachesis/finality"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
@@ -30,6 +33,11 @@ func TestGetEvents62(t *testing.T) {
 	testGetEvents(t, lachesis62)
 }
 
+func TestGetEvents63(t *testing.T) {
+	logger.SetTestMode(t)
+	testGetEvents(t, lachesis63)
+}
+
 func testGetEvents(t *testing.T, protocol int) {
 	assertar := assert.New(t)
 
@@ -90,7 +98,7 @@ func testGetEvents(t *testing.T, protocol int) {
 		if !assertar.NoError(p2p.Send(peer.app, GetEventsMsg, tt.query)) {
 			return
 		}
-		if err := p2p.ExpectMsg(peer.app, EventsMsg, tt.expect); err != nil {
+		if err := peer.expectMsg(EventsMsg, tt.expect); err != nil {
 			t.Errorf("test %d: events mismatch: %v", i, err)
 		}
 		if t.Failed() {
@@ -99,6 +107,57 @@ func testGetEvents(t *testing.T, protocol int) {
 	}
 }
 
+// Tests that epoch transitions are served consecutively.
+func TestGetEpochTransitions63(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	pm, store := newTestProtocolManagerMust(t, 5, 5, nil, nil)
+	defer pm.Stop()
+
+	transitions := make(map[idx.Epoch]*finality.EpochTransition)
+	for _, epoch := range []idx.Epoch{2, 3, 5} {
+		transitions[epoch] = &finality.EpochTransition{
+			Epoch:              epoch,
+			PrevValidatorsHash: common.Hash{byte(epoch)},
+			Validators: []finality.Validator{
+				{ID: idx.StakerID(epoch), Stake: 1, Address: common.Address{byte(epoch)}},
+			},
+			SealingBlock: idx.Block(epoch * 10),
+		}
+		store.SetEpochTransition(transitions[epoch])
+	}
+
+	peer, _ := newTestPeer("peer", lachesis63, pm, true)
+	defer peer.close()
+
+	tests := []struct {
+		query  getEpochTransitionsData
+		expect []*finality.EpochTransition
+	}{
+		{
+			getEpochTransitionsData{From: 0, Amount: 10},
+			[]*finality.EpochTransition{transitions[2], transitions[3]},
+		},
+		{
+			getEpochTransitionsData{From: 3, Amount: 1},
+			[]*finality.EpochTransition{transitions[3]},
+		},
+		{
+			getEpochTransitionsData{From: 5, Amount: 10},
+			[]*finality.EpochTransition{transitions[5]},
+		},
+	}
+	for i, tt := range tests {
+		if !assertar.NoError(p2p.Send(peer.app, GetEpochTransitionsMsg, tt.query)) {
+			return
+		}
+		if err := p2p.ExpectMsg(peer.app, EpochTransitionsMsg, tt.expect); err != nil {
+			t.Errorf("test %d: transitions mismatch: %v", i, err)
+		}
+	}
+}
+
 func TestBroadcastEvent(t *testing.T) {
 	logger.SetTestMode(t)
 
@@ -176,10 +235,10 @@ func testBroadcastEvent(t *testing.T, totalPeers int, forcedAggressiveBroadcast
 	pm.downloader.Terminate() // disable downloader so test would be deterministic
 	defer pm.Stop()
 
-	// create peers
+	// create peers of mixed protocol versions
 	var peers []*testPeer
 	for i := 0; i < totalPeers; i++ {
-		peer, _ := newTestPeer(fmt.Sprintf("peer %d", i), lachesis62, pm, true)
+		peer, _ := newTestPeer(fmt.Sprintf("peer %d", i), int(ProtocolVersions[i%len(ProtocolVersions)]), pm, true)
 		defer peer.close()
 		peers = append(peers, peer)
 	}
@@ -200,7 +259,7 @@ func testBroadcastEvent(t *testing.T, totalPeers int, forcedAggressiveBroadcast
 		for _, peer := range peers {
 			if forcedAggressiveBroadcast {
 				// aggressive
-				assertar.NoError(p2p.ExpectMsg(peer.app, EventsMsg, []*inter.Event{emitted}))
+				assertar.NoError(peer.expectMsg(EventsMsg, []*inter.Event{emitted}))
 			} else {
 				// announce
 				assertar.NoError(p2p.ExpectMsg(peer.app, NewEventHashesMsg, []hash.Event{emitted.Hash()}))
@@ -214,7 +273,7 @@ func testBroadcastEvent(t *testing.T, totalPeers int, forcedAggressiveBroadcast
 	}
 
 	// fresh new peer
-	newPeer, _ := newTestPeer(fmt.Sprintf("peer %d", totalPeers), lachesis62, pm, true)
+	newPeer, _ := newTestPeer(fmt.Sprintf("peer %d", totalPeers), lachesis63, pm, true)
 	defer newPeer.close()
 	for pm.peers.Len() < totalPeers+1 { // wait until the new peer is registered
 		time.Sleep(10 * time.Millisecond)
@@ -231,12 +290,12 @@ func testBroadcastEvent(t *testing.T, totalPeers int, forcedAggressiveBroadcast
 			return
 		}
 		// send it to PM
-		assertar.NoError(p2p.Send(newPeer.app, EventsMsg, []*inter.Event{emitted}))
+		assertar.NoError(newPeer.sendMsg(EventsMsg, []*inter.Event{emitted}))
 		// PM should broadcast it to all other peer except newPeer
 		for _, peer := range peers {
 			if forcedAggressiveBroadcast {
 				// aggressive
-				assertar.NoError(p2p.ExpectMsg(peer.app, EventsMsg, []*inter.Event{emitted}))
+				assertar.NoError(peer.expectMsg(EventsMsg, []*inter.Event{emitted}))
 			} else {
 				// announce
 				assertar.NoError(p2p.ExpectMsg(peer.app, NewEventHashesMsg, []hash.Event{emitted.Hash()}))
@@ -253,7 +312,7 @@ func testBroadcastEvent(t *testing.T, totalPeers int, forcedAggressiveBroadcast
 	for _, emitted := range emittedEvents {
 		for _, peer := range append(peers, newPeer) {
 			assertar.NoError(p2p.Send(peer.app, GetEventsMsg, []hash.Event{emitted.Hash()})) // request
-			assertar.NoError(p2p.ExpectMsg(peer.app, EventsMsg, []*inter.Event{emitted}))    // response
+			assertar.NoError(peer.expectMsg(EventsMsg, []*inter.Event{emitted}))             // response
 			if t.Failed() {
 				return
 			}
@@ -261,6 +320,101 @@ func testBroadcastEvent(t *testing.T, totalPeers int, forcedAggressiveBroadcast
 	}
 }
 
+// Tests that events are sent compact only to lachesis63 peers which know the txs,
+// and that compact events are restored from the txpool, or requested in full.
+func TestCompactEvents(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	pm, _ := newTestProtocolManagerMust(t, 5, 5, nil, nil)
+	defer pm.Stop()
+
+	p62, _ := newTestPeer("peer62", lachesis62, pm, true)
+	defer p62.close()
+	p63, _ := newTestPeer("peer63", lachesis63, pm, true)
+	defer p63.close()
+
+	newEvent := func(seq idx.Event, txs ...*types.Transaction) *inter.Event {
+		e := inter.NewEvent()
+		e.Epoch = 1
+		e.Seq = seq
+		e.Creator = 1
+		e.Transactions = txs
+		e.TxHash = types.DeriveSha(e.Transactions)
+		return e
+	}
+	tx1 := newTestTransaction(testAccount, 0, 0)
+	tx2 := newTestTransaction(testAccount, 1, 0)
+	withKnownTxs := newEvent(1, tx1, tx2)
+	withUnknownTx := newEvent(2, tx1, newTestTransaction(testAccount, 2, 0))
+	withoutTxs := newEvent(3)
+	events := inter.Events{withKnownTxs, withUnknownTx, withoutTxs}
+
+	compact := func(e *inter.Event) *compactEvent {
+		c := &compactEvent{
+			Header:   &e.EventHeader,
+			TxHashes: make([]common.Hash, len(e.Transactions)),
+		}
+		for i, tx := range e.Transactions {
+			c.TxHashes[i] = tx.Hash()
+		}
+		return c
+	}
+
+	// sending
+	for _, p := range []*testPeer{p62, p63} {
+		p.peer.MarkTransaction(tx1.Hash())
+		p.peer.MarkTransaction(tx2.Hash())
+		errc := make(chan error, 1)
+		go func(p *testPeer) {
+			errc <- p.peer.SendEvents(events)
+		}(p)
+		if p.version == lachesis62 {
+			assertar.NoError(p.expectMsg(EventsMsg, events))
+		} else {
+			assertar.NoError(p.expectMsg(EventsMsg, inter.Events{withUnknownTx, withoutTxs}))
+			assertar.NoError(p.expectMsg(CompactEventsMsg, []*compactEvent{compact(withKnownTxs)}))
+		}
+		assertar.NoError(<-errc)
+		if t.Failed() {
+			return
+		}
+	}
+
+	// restoring
+	assertar.Nil(pm.restoreCompactEvent(compact(withKnownTxs)))
+	pm.txpool.AddRemotes(types.Transactions{tx1, tx2})
+	restored := pm.restoreCompactEvent(compact(withKnownTxs))
+	if assertar.NotNil(restored) {
+		assertar.Equal(withKnownTxs.Hash(), restored.Hash())
+		assertar.Equal(withKnownTxs.Transactions, restored.Transactions)
+	}
+
+	// receiving, the event with unknown tx is requested in full
+	assertar.NoError(p63.sendMsg(CompactEventsMsg, []*compactEvent{compact(withUnknownTx)}))
+	assertar.NoError(p2p.ExpectMsg(p63.app, GetEventsMsg, hash.Events{withUnknownTx.Hash()}))
+	assertar.True(p63.peer.knownTxs.Contains(tx1.Hash()))
+}
+
+// Tests that compressed payloads can't be decompressed above the message size limit.
+func TestDecompressMsgLimit(t *testing.T) {
+	assertar := assert.New(t)
+
+	msgOf := func(data interface{}) p2p.Msg {
+		payload, err := compressMsg(data)
+		assertar.NoError(err)
+		size, r, err := rlp.EncodeToReader(payload)
+		assertar.NoError(err)
+		return p2p.Msg{Code: EventsMsg, Size: uint32(size), Payload: r}
+	}
+
+	var got []byte
+	assertar.NoError(decompressMsg(msgOf([]byte{1, 2, 3}), &got))
+	assertar.Equal([]byte{1, 2, 3}, got)
+
+	assertar.Error(decompressMsg(msgOf(make([]byte, protocolMaxMsgSize)), &got))
+}
+
 func mockAccountManager(accs genesis.Accounts, unlock ...common.Address) *accounts.Manager {
 	return accounts.NewManager(
 		&accounts.Config{InsecureUnlockAllowed: true},
@@ -273,5 +427,5 @@ func mockCheckers(epoch idx.Epoch, net *lachesis.Config, engine Consensus, s *St
 	heavyCheckReader.Addrs.Store(ReadEpochPubKeys(a, epoch))
 	gasPowerCheckReader := &GasPowerCheckReader{}
 	gasPowerCheckReader.Ctx.Store(ReadGasPowerContext(s, a, engine.GetValidators(), engine.GetEpoch(), &net.Economy))
-	return makeCheckers(net, heavyCheckReader, gasPowerCheckReader, engine, s)
+	return makeCheckers(net, heavyCheckReader, gasPowerCheckReader, engine, s, nil)
 }
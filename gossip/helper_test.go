@@ -1,8 +1,10 @@
 package gossip
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/rand"
+	"fmt"
 	"math/big"
 	"sync"
 	"testing"
@@ -11,6 +13,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
 
 	"github.com/Fantom-foundation/go-lachesis/app"
 	"github.com/Fantom-foundation/go-lachesis/hash"
@@ -168,14 +172,58 @@ func (p *testPeer) handshake(t *testing.T, progress *PeerProgress, genesis commo
 	if err := p2p.Send(p.app, EthStatusMsg, msg); err != nil {
 		t.Fatalf("status send: %v", err)
 	}
-	if err := p2p.ExpectMsg(p.app, ProgressMsg, progress); err != nil {
+	if err := p2p.ExpectMsg(p.app, ProgressMsg, progressMsgOf(p.version, *progress)); err != nil {
 		t.Fatalf("progress recv: %v", err)
 	}
-	if err := p2p.Send(p.app, ProgressMsg, progress); err != nil {
+	if err := p2p.Send(p.app, ProgressMsg, progressMsgOf(p.version, *progress)); err != nil {
 		t.Fatalf("progress send: %v", err)
 	}
 }
 
+// sendMsg sends the message from the remote side, compressing its payload if the protocol version requires.
+func (p *testPeer) sendMsg(code uint64, data interface{}) error {
+	if !isCompressed(p.version, code) {
+		return p2p.Send(p.app, code, data)
+	}
+	payload, err := compressMsg(data)
+	if err != nil {
+		return err
+	}
+	return p2p.Send(p.app, code, payload)
+}
+
+// expectMsg reads a message from the remote side and checks its code and content,
+// decompressing its payload if the protocol version requires.
+func (p *testPeer) expectMsg(code uint64, content interface{}) error {
+	if !isCompressed(p.version, code) {
+		return p2p.ExpectMsg(p.app, code, content)
+	}
+	msg, err := p.app.ReadMsg()
+	if err != nil {
+		return err
+	}
+	defer msg.Discard()
+	if msg.Code != code {
+		return fmt.Errorf("message code mismatch: got %d, expected %d", msg.Code, code)
+	}
+	var compressed []byte
+	if err := msg.Decode(&compressed); err != nil {
+		return err
+	}
+	got, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return err
+	}
+	want, err := rlp.EncodeToBytes(content)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(got, want) {
+		return fmt.Errorf("message payload mismatch:\ngot:  %x\nwant: %x", got, want)
+	}
+	return nil
+}
+
 // close terminates the local side of the peer, notifying the remote protocol
 // manager of termination.
 func (p *testPeer) close() {
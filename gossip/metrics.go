@@ -10,6 +10,10 @@ var (
 	confirmBlocksMeter = metrics.NewRegisteredCounter("confirm/blocks", nil)
 	confirmTxnsMeter   = metrics.NewRegisteredCounter("confirm/transactions", nil)
 	txTtfMeter         = metrics.NewRegisteredHistogram("tx_ttf", nil, metrics.NewUniformSample(500))
+
+	eventsBloomNegativeMeter      = metrics.NewRegisteredMeter("events/bloom/negative", nil)      // lookups which avoided disk reads
+	eventsBloomFalsePositiveMeter = metrics.NewRegisteredMeter("events/bloom/falsepositive", nil) // lookups of absent events which weren't filtered
+	eventsBloomFpRateGauge        = metrics.NewRegisteredGaugeFloat64("events/bloom/fprate", nil)
 )
 
 var txLatency = meta.NewTxs()
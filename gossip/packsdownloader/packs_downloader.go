@@ -22,7 +22,7 @@ const (
 // and scheduling them for retrieval.
 type PacksDownloader struct {
 	// Callbacks
-	dropPeer         dropPeerFn
+	punishPeer       punishPeerFn
 	fetcher          *fetcher.Fetcher
 	onlyNotConnected onlyNotConnectedFn
 
@@ -34,19 +34,20 @@ type PacksDownloader struct {
 }
 
 // New creates a packs fetcher to retrieve events based on pack announcements.
-func New(fetcher *fetcher.Fetcher, onlyNotConnected onlyNotConnectedFn, dropPeer dropPeerFn) *PacksDownloader {
+func New(fetcher *fetcher.Fetcher, onlyNotConnected onlyNotConnectedFn, punishPeer punishPeerFn) *PacksDownloader {
 	return &PacksDownloader{
 		fetcher:          fetcher,
 		onlyNotConnected: onlyNotConnected,
-		dropPeer:         dropPeer,
+		punishPeer:       punishPeer,
 		peers:            make(map[string]*PeerPacksDownloader),
 		peersMu:          new(sync.RWMutex),
 	}
 }
 
 type Peer struct {
-	ID    string
-	Epoch idx.Epoch
+	ID          string
+	Epoch       idx.Epoch
+	LowestEpoch idx.Epoch // lowest epoch which packs the peer still has
 
 	RequestPackInfos packInfoRequesterFn
 	RequestPack      packRequesterFn
@@ -55,7 +56,7 @@ type Peer struct {
 // RegisterPeer injects a new download peer into the set of block source to be
 // used for fetching hashes and blocks from.
 func (d *PacksDownloader) RegisterPeer(peer Peer, myEpoch idx.Epoch) error {
-	if peer.Epoch < myEpoch {
+	if peer.Epoch < myEpoch || peer.LowestEpoch > myEpoch {
 		// this peer is useless for syncing
 		return d.UnregisterPeer(peer.ID)
 	}
@@ -72,13 +73,13 @@ func (d *PacksDownloader) RegisterPeer(peer Peer, myEpoch idx.Epoch) error {
 	}
 
 	log.Trace("Registering sync peer", "peer", peer.ID, "epoch", myEpoch)
-	d.peers[peer.ID] = newPeer(peer, myEpoch, d.fetcher, d.onlyNotConnected, d.dropPeer)
+	d.peers[peer.ID] = newPeer(peer, myEpoch, d.fetcher, d.onlyNotConnected, d.punishPeer)
 	d.peers[peer.ID].Start()
 
 	return nil
 }
 
-func (d *PacksDownloader) OnNewEpoch(myEpoch idx.Epoch, peerEpoch func(string) idx.Epoch) {
+func (d *PacksDownloader) OnNewEpoch(myEpoch idx.Epoch, peerEpochs func(string) (lowest, epoch idx.Epoch)) {
 	d.peersMu.Lock()
 	defer d.peersMu.Unlock()
 
@@ -87,9 +88,9 @@ func (d *PacksDownloader) OnNewEpoch(myEpoch idx.Epoch, peerEpoch func(string) i
 	for peerID, peerDwnld := range d.peers {
 		peerDwnld.Stop()
 
-		if peerEpoch(peerID) >= myEpoch {
+		if lowest, epoch := peerEpochs(peerID); epoch >= myEpoch && lowest <= myEpoch {
 			// allocate new peer for the new epoch
-			newPeerDwnld := newPeer(peerDwnld.peer, myEpoch, d.fetcher, d.onlyNotConnected, d.dropPeer)
+			newPeerDwnld := newPeer(peerDwnld.peer, myEpoch, d.fetcher, d.onlyNotConnected, d.punishPeer)
 			newPeerDwnld.Start()
 			newPeers[peerID] = newPeerDwnld
 		} else {
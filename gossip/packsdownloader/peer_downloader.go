@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/Fantom-foundation/go-lachesis/gossip/fetcher"
+	"github.com/Fantom-foundation/go-lachesis/gossip/reputation"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 )
@@ -39,8 +40,8 @@ var (
 // onlyNotConnectedFn returns only not connected events.
 type onlyNotConnectedFn func(ids hash.Events) hash.Events
 
-// dropPeerFn is a callback type for dropping a peer detected as malicious.
-type dropPeerFn func(peer string)
+// punishPeerFn is a callback type for scoring a peer's misbehaviour.
+type punishPeerFn func(peer string, o reputation.Offence)
 
 // request pack info from the peer
 type packInfoRequesterFn func(epoch idx.Epoch, indexes []idx.Pack) error
@@ -80,7 +81,7 @@ type PeerPacksDownloader struct {
 	quit chan struct{}
 
 	// Callbacks
-	dropPeer         dropPeerFn
+	punishPeer       punishPeerFn
 	fetcher          *fetcher.Fetcher
 	onlyNotConnected onlyNotConnectedFn
 
@@ -96,7 +97,7 @@ type PeerPacksDownloader struct {
 }
 
 // New creates a packs fetcher to retrieve events based on pack announcements. Works only with 1 peer.
-func newPeer(peer Peer, myEpoch idx.Epoch, fetcher *fetcher.Fetcher, onlyNotConnected onlyNotConnectedFn, dropPeer dropPeerFn) *PeerPacksDownloader {
+func newPeer(peer Peer, myEpoch idx.Epoch, fetcher *fetcher.Fetcher, onlyNotConnected onlyNotConnectedFn, punishPeer punishPeerFn) *PeerPacksDownloader {
 	return &PeerPacksDownloader{
 		notifyInfo:       make(chan *packInfoData, maxQueuedInfos),
 		notifyPacksNum:   make(chan *packsNumData, maxQueuedInfos),
@@ -109,7 +110,7 @@ func newPeer(peer Peer, myEpoch idx.Epoch, fetcher *fetcher.Fetcher, onlyNotConn
 		myEpoch:          myEpoch,
 		fetcher:          fetcher,
 		onlyNotConnected: onlyNotConnected,
-		dropPeer:         dropPeer,
+		punishPeer:       punishPeer,
 	}
 }
 
@@ -216,7 +217,7 @@ func (d *PeerPacksDownloader) loop() {
 				// if we have too much packs -> d.sweepKnown() doesn't erase them -> we don't connect events from these packs.
 				// Also we do binary search, so we don't need much packs to store, so we shouldn't reach this if peer is ok.
 				log.Warn("All the peer packs are unknown. Faulty peer?", "peer", d.peer.ID)
-				d.dropPeer(d.peer.ID)
+				d.punishPeer(d.peer.ID, reputation.UselessAnnounce)
 			}
 			if packInfo.index <= 0 || packInfo.index >= math.MaxInt32 {
 				log.Error("Invalid pack index", "peer", d.peer.ID)
@@ -27,6 +27,7 @@ var (
 const (
 	maxKnownTxs    = 24576 // Maximum transactions hashes to keep in the known list (prevent DOS)
 	maxKnownEvents = 16384 // Maximum event hashes to keep in the known list (prevent DOS)
+	maxRequested   = 16384 // Maximum event hashes to keep in the requested list (prevent DOS)
 
 	// maxQueuedTxs is the maximum number of transaction lists to queue up before
 	// dropping broadcasts. This is a sensitive number as a transaction list might
@@ -62,6 +63,7 @@ type peer struct {
 
 	knownTxs    mapset.Set                // Set of transaction hashes known to be known by this peer
 	knownEvents mapset.Set                // Set of event hashes known to be known by this peer
+	requested   mapset.Set                // Set of event hashes requested from this peer, and not received yet
 	queuedTxs   chan []*types.Transaction // Queue of transactions to broadcast to the peer
 	queuedProps chan inter.Events         // Queue of events to broadcast to the peer
 	queuedAnns  chan hash.Events          // Queue of events to announce to the peer
@@ -111,6 +113,7 @@ func newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
 		id:          fmt.Sprintf("%x", p.ID().Bytes()[:8]),
 		knownTxs:    mapset.NewSet(),
 		knownEvents: mapset.NewSet(),
+		requested:   mapset.NewSet(),
 		queuedTxs:   make(chan []*types.Transaction, maxQueuedTxs),
 		queuedProps: make(chan inter.Events, maxQueuedProps),
 		queuedAnns:  make(chan hash.Events, maxQueuedAnns),
@@ -172,6 +175,20 @@ func (p *peer) MarkEvent(hash hash.Event) {
 	p.knownEvents.Add(hash)
 }
 
+// ForgetRequested forgets the received events as requested from the peer.
+// It returns true if all the events were requested, i.e. it's a reply to our requests.
+func (p *peer) ForgetRequested(events inter.Events) (solicited bool) {
+	solicited = true
+	for _, e := range events {
+		if !p.requested.Contains(e.Hash()) {
+			solicited = false
+			continue
+		}
+		p.requested.Remove(e.Hash())
+	}
+	return solicited
+}
+
 // MarkTransaction marks a transaction as known for the peer, ensuring that it
 // will never be propagated to this particular peer.
 func (p *peer) MarkTransaction(hash common.Hash) {
@@ -243,7 +260,39 @@ func (p *peer) AsyncSendNewEventHashes(ids hash.Events) {
 	}
 }
 
+// send sends the message, compressing its payload if the negotiated protocol version requires.
+func (p *peer) send(code uint64, data interface{}) error {
+	if !isCompressed(p.version, code) {
+		return p2p.Send(p.rw, code, data)
+	}
+	payload, err := compressMsg(data)
+	if err != nil {
+		return err
+	}
+	return p2p.Send(p.rw, code, payload)
+}
+
+// decode decodes the message payload into val, decompressing it if the negotiated protocol version requires.
+func (p *peer) decode(msg p2p.Msg, val interface{}) error {
+	if !isCompressed(p.version, msg.Code) {
+		return msg.Decode(val)
+	}
+	return decompressMsg(msg, val)
+}
+
+// knowsTxs returns true if all the event's transactions are known to be known by the peer.
+func (p *peer) knowsTxs(e *inter.Event) bool {
+	for _, tx := range e.Transactions {
+		if !p.knownTxs.Contains(tx.Hash()) {
+			return false
+		}
+	}
+	return true
+}
+
 // SendNewEvent propagates an entire event to a remote peer.
+// Since lachesis63, the events which transactions are known to the peer are sent
+// without transactions, only with their hashes.
 func (p *peer) SendEvents(events inter.Events) error {
 	// Mark all the event hash as known, but ensure we don't overflow our limits
 	for _, event := range events {
@@ -252,7 +301,35 @@ func (p *peer) SendEvents(events inter.Events) error {
 			p.knownEvents.Pop()
 		}
 	}
-	return p2p.Send(p.rw, EventsMsg, events)
+	if p.version < lachesis63 {
+		return p.send(EventsMsg, events)
+	}
+
+	full := make(inter.Events, 0, len(events))
+	compact := make([]*compactEvent, 0, len(events))
+	for _, event := range events {
+		if len(event.Transactions) == 0 || !p.knowsTxs(event) {
+			full = append(full, event)
+			continue
+		}
+		txHashes := make([]common.Hash, len(event.Transactions))
+		for i, tx := range event.Transactions {
+			txHashes[i] = tx.Hash()
+		}
+		compact = append(compact, &compactEvent{
+			Header:   &event.EventHeader,
+			TxHashes: txHashes,
+		})
+	}
+	if len(full) != 0 {
+		if err := p.send(EventsMsg, full); err != nil {
+			return err
+		}
+	}
+	if len(compact) != 0 {
+		return p.send(CompactEventsMsg, compact)
+	}
+	return nil
 }
 
 func (p *peer) SendEventsRLP(events []rlp.RawValue, ids []hash.Event) error {
@@ -263,17 +340,21 @@ func (p *peer) SendEventsRLP(events []rlp.RawValue, ids []hash.Event) error {
 			p.knownEvents.Pop()
 		}
 	}
-	return p2p.Send(p.rw, EventsMsg, events)
+	return p.send(EventsMsg, events)
 }
 
 func (p *peer) SendPackInfosRLP(packInfos *packInfosDataRLP) error {
-	return p2p.Send(p.rw, PackInfosMsg, packInfos)
+	return p.send(PackInfosMsg, packInfos)
 }
 
 func (p *peer) SendPack(pack *packData) error {
 	return p2p.Send(p.rw, PackMsg, pack)
 }
 
+func (p *peer) SendEpochTransitionsRLP(transitions []rlp.RawValue) error {
+	return p2p.Send(p.rw, EpochTransitionsMsg, transitions)
+}
+
 // AsyncSendEvents queues an entire event for propagation to a remote peer. If
 // the peer's broadcast queue is full, the event is silently dropped.
 func (p *peer) AsyncSendEvents(events inter.Events) {
@@ -325,6 +406,13 @@ func (p *peer) RequestEvents(ids hash.Events) error {
 			end = start + softLimitItems
 		}
 		p.Log().Debug("Fetching batch of events", "count", len(ids[start:end]))
+		// If we reached the memory allowance, drop a previously requested event hash
+		for _, id := range ids[start:end] {
+			for p.requested.Cardinality() >= maxRequested {
+				p.requested.Pop()
+			}
+			p.requested.Add(id)
+		}
 		err := p2p.Send(p.rw, GetEventsMsg, ids[start:end])
 		if err != nil {
 			return err
@@ -388,7 +476,24 @@ func (p *peer) Handshake(network uint64, progress PeerProgress, genesis common.H
 }
 
 func (p *peer) SendProgress(progress PeerProgress) error {
-	return p2p.Send(p.rw, ProgressMsg, progress)
+	return p2p.Send(p.rw, ProgressMsg, progressMsgOf(p.version, progress))
+}
+
+// decodeProgress decodes ProgressMsg in the encoding of the negotiated protocol version.
+func (p *peer) decodeProgress(msg p2p.Msg) (progress PeerProgress, err error) {
+	if p.version >= lachesis63 {
+		err = msg.Decode(&progress)
+		return
+	}
+	var old peerProgress62
+	err = msg.Decode(&old)
+	progress = PeerProgress{
+		Epoch:        old.Epoch,
+		NumOfBlocks:  old.NumOfBlocks,
+		LastPackInfo: old.LastPackInfo,
+		LastBlock:    old.LastBlock,
+	}
+	return
 }
 
 func (p *peer) readStatus(network uint64, status *ethStatusData, genesis common.Hash) (err error) {
@@ -7,6 +7,7 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 	"github.com/Fantom-foundation/go-lachesis/vector"
 )
 
@@ -89,6 +90,14 @@ func (hook *HookedEngine) GetConsensusTime(id hash.Event) (inter.Timestamp, erro
 	return hook.engine.GetConsensusTime(id)
 }
 
+// GetElectionTrace returns election trace of the decided frame of current epoch, if recorded.
+func (hook *HookedEngine) GetElectionTrace(f idx.Frame) *election.Trace {
+	if hook.engine == nil {
+		return nil
+	}
+	return hook.engine.GetElectionTrace(f)
+}
+
 // Bootstrap restores poset's state from store.
 func (hook *HookedEngine) Bootstrap(callbacks inter.ConsensusCallbacks) {
 	if hook.engine == nil {
@@ -10,22 +10,24 @@ import (
 
 	"github.com/Fantom-foundation/go-lachesis/evmcore"
 	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 )
 
 // Constants to match up protocol versions and messages
 const (
 	lachesis62 = 62 // derived from eth62
+	lachesis63 = 63 // lachesis62 with compressed payloads and compact events
 )
 
 // protocolName is the official short name of the protocol used during capability negotiation.
 const protocolName = "lachesis"
 
 // ProtocolVersions are the supported versions of the protocol (first is primary).
-var ProtocolVersions = []uint{lachesis62}
+var ProtocolVersions = []uint{lachesis63, lachesis62}
 
 // protocolLengths are the number of implemented message corresponding to different protocol versions.
-var protocolLengths = map[uint]uint64{lachesis62: PackMsg + 1}
+var protocolLengths = map[uint]uint64{lachesis63: CompactEventsMsg + 1, lachesis62: PackMsg + 1}
 
 const protocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -62,6 +64,18 @@ const (
 	GetPackMsg = 0xf6
 	// Contains the requested pack. An answer to GetPackMsg.
 	PackMsg = 0xf7
+
+	// Protocol messages belonging to lachesis/63
+	// Payloads of EventsMsg, PackInfosMsg and CompactEventsMsg are snappy-compressed.
+
+	// Request validators group transitions by epochs range. Sent by light clients.
+	GetEpochTransitionsMsg = 0xf8
+	// Contains the requested transitions. An answer to GetEpochTransitionsMsg.
+	EpochTransitionsMsg = 0xf9
+
+	// Contains the batch of events without transactions, only with their hashes.
+	// Sent during aggressive events propagation, if the peer is known to have all the transactions.
+	CompactEventsMsg = 0xfa
 )
 
 type errCode int
@@ -105,6 +119,9 @@ type txPool interface {
 	// The slice should be modifiable by the caller.
 	Pending() (map[common.Address]types.Transactions, error)
 
+	// Get should return the transaction from the pool, or nil if it isn't known.
+	Get(hash common.Hash) *types.Transaction
+
 	// SubscribeNewTxsNotify should return an event subscription of
 	// NewTxsNotify and send events to the given channel.
 	SubscribeNewTxsNotify(chan<- evmcore.NewTxsNotify) notify.Subscription
@@ -125,6 +142,28 @@ type PeerProgress struct {
 	NumOfBlocks  idx.Block
 	LastPackInfo PackInfo
 	LastBlock    hash.Event
+	LowestEpoch  idx.Epoch // lowest epoch which events and packs are served, 0 if no epochs are pruned. Since lachesis63
+}
+
+// peerProgress62 is PeerProgress in lachesis62 encoding, which has no LowestEpoch.
+type peerProgress62 struct {
+	Epoch        idx.Epoch
+	NumOfBlocks  idx.Block
+	LastPackInfo PackInfo
+	LastBlock    hash.Event
+}
+
+// progressMsgOf returns the progress in the encoding of the protocol version.
+func progressMsgOf(version int, progress PeerProgress) interface{} {
+	if version < lachesis63 {
+		return &peerProgress62{
+			Epoch:        progress.Epoch,
+			NumOfBlocks:  progress.NumOfBlocks,
+			LastPackInfo: progress.LastPackInfo,
+			LastBlock:    progress.LastBlock,
+		}
+	}
+	return &progress
 }
 
 type packInfosData struct {
@@ -149,6 +188,17 @@ type getPackData struct {
 	Index idx.Pack
 }
 
+// compactEvent is an event, which transactions are replaced by their hashes.
+type compactEvent struct {
+	Header   *inter.EventHeader
+	TxHashes []common.Hash
+}
+
+type getEpochTransitionsData struct {
+	From   idx.Epoch
+	Amount uint32
+}
+
 type packData struct {
 	Epoch idx.Epoch
 	Index idx.Pack
@@ -2,6 +2,7 @@ package gossip
 
 import (
 	"fmt"
+	"math/big"
 	"sync"
 	"testing"
 	"time"
@@ -10,7 +11,14 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
 
+	"github.com/Fantom-foundation/go-lachesis/gossip/reputation"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
 	"github.com/Fantom-foundation/go-lachesis/lachesis"
 	"github.com/Fantom-foundation/go-lachesis/logger"
 )
@@ -27,6 +35,11 @@ func TestStatusMsgErrors62(t *testing.T) {
 	testStatusMsgErrors(t, lachesis62)
 }
 
+func TestStatusMsgErrors63(t *testing.T) {
+	logger.SetTestMode(t)
+	testStatusMsgErrors(t, lachesis63)
+}
+
 func testStatusMsgErrors(t *testing.T, protocol int) {
 	pm, _ := newTestProtocolManagerMust(t, 5, 5, nil, nil)
 	var (
@@ -78,12 +91,74 @@ func testStatusMsgErrors(t *testing.T, protocol int) {
 	}
 }
 
+// Tests that ProgressMsg of lachesis62 keeps the baseline encoding, and LowestEpoch is sent since lachesis63.
+func TestProgressMsg(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	pm, _ := newTestProtocolManagerMust(t, 5, 5, nil, nil)
+	defer pm.Stop()
+
+	// baseline lachesis62 encoding
+	type progress62 struct {
+		Epoch        idx.Epoch
+		NumOfBlocks  idx.Block
+		LastPackInfo PackInfo
+		LastBlock    hash.Event
+	}
+
+	for _, protocol := range []int{lachesis62, lachesis63} {
+		p, errc := newTestPeer("peer", protocol, pm, true)
+
+		sent := PeerProgress{
+			Epoch:        1,
+			NumOfBlocks:  7,
+			LastPackInfo: PackInfo{Heads: hash.Events{}},
+			LowestEpoch:  1,
+		}
+		var msg interface{} = &sent
+		if protocol == lachesis62 {
+			msg = &progress62{
+				Epoch:        sent.Epoch,
+				NumOfBlocks:  sent.NumOfBlocks,
+				LastPackInfo: sent.LastPackInfo,
+			}
+			sent.LowestEpoch = 0
+		}
+		assertar.NoError(p2p.Send(p.app, ProgressMsg, msg))
+
+		var got PeerProgress
+		for i := 0; i < 100; i++ {
+			p.peer.RLock()
+			got = p.peer.progress
+			p.peer.RUnlock()
+			if got.NumOfBlocks == sent.NumOfBlocks {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		assertar.Equal(sent, got, protocol)
+		assertar.Equal(reputation.Good, pm.reputation.Status(p.peer.ID()), protocol)
+		select {
+		case err := <-errc:
+			t.Fatalf("peer is disconnected: %v", err)
+		default:
+		}
+		p.close()
+	}
+}
+
 // This test checks that received transactions are added to the local pool.
 func TestRecvTransactions62(t *testing.T) {
 	logger.SetTestMode(t)
 	testRecvTransactions(t, lachesis62)
 }
 
+func TestRecvTransactions63(t *testing.T) {
+	logger.SetTestMode(t)
+	testRecvTransactions(t, lachesis63)
+}
+
 func testRecvTransactions(t *testing.T, protocol int) {
 	txAdded := make(chan []*types.Transaction)
 	pm, _ := newTestProtocolManagerMust(t, 5, 5, txAdded, nil)
@@ -114,6 +189,11 @@ func TestSendTransactions62(t *testing.T) {
 	testSendTransactions(t, lachesis62)
 }
 
+func TestSendTransactions63(t *testing.T) {
+	logger.SetTestMode(t)
+	testSendTransactions(t, lachesis63)
+}
+
 func testSendTransactions(t *testing.T, protocol int) {
 	pm, _ := newTestProtocolManagerMust(t, 5, 5, nil, nil)
 	defer pm.Stop()
@@ -167,3 +247,239 @@ func testSendTransactions(t *testing.T, protocol int) {
 	}
 	wg.Wait()
 }
+
+// Tests that protocol violations are scored, and the peer is disconnected only after it gets banned.
+func TestInvalidMsgBan62(t *testing.T) {
+	logger.SetTestMode(t)
+	testInvalidMsgBan(t, lachesis62)
+}
+
+func TestInvalidMsgBan63(t *testing.T) {
+	logger.SetTestMode(t)
+	testInvalidMsgBan(t, lachesis63)
+}
+
+func testInvalidMsgBan(t *testing.T, protocol int) {
+	assertar := assert.New(t)
+
+	pm, _ := newTestProtocolManagerMust(t, 5, 5, nil, nil)
+	defer pm.Stop()
+
+	// scores don't decay, because the clock is stopped
+	now := time.Unix(1000000, 0)
+	cfg := reputation.DefaultConfig()
+	pm.reputation = reputation.New(cfg, memorydb.New())
+	pm.reputation.SetClock(func() time.Time { return now })
+
+	p, errc := newTestPeer("peer", protocol, pm, true)
+	defer p.close()
+
+	// a message is read after the previous one is handled, so every sent message is handled
+	// once the next one is sent. Announce of nothing is a harmless message to wait for it.
+	barrier := func() {
+		assertar.NoError(p2p.Send(p.app, NewEventHashesMsg, hash.Events{}))
+	}
+
+	penalty := cfg.Penalties.InvalidMsg
+	score := 0.
+	for score+penalty < cfg.BanScore {
+		assertar.NoError(p2p.Send(p.app, ProgressMsg, "invalid"))
+		score += penalty
+	}
+	barrier()
+	select {
+	case err := <-errc:
+		t.Fatalf("peer is disconnected with score %v: %v", score, err)
+	default:
+	}
+	assertar.Equal(score, pm.reputation.Score(p.peer.ID()))
+	assertar.Equal(reputation.Throttled, pm.reputation.Status(p.peer.ID()))
+
+	assertar.NoError(p2p.Send(p.app, ProgressMsg, "invalid"))
+	select {
+	case err := <-errc:
+		assertar.Error(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("banned peer is not disconnected")
+	}
+	assertar.Equal(reputation.Banned, pm.reputation.Status(p.peer.ID()))
+	assertar.Len(pm.reputation.Bans(), 1)
+
+	// banned peer isn't accepted
+	_, net := p2p.MsgPipe()
+	assertar.Equal(p2p.DiscUselessPeer, pm.handle(pm.newPeer(protocol, p.peer.Peer, net)))
+}
+
+// Tests that events are recognized as solicited only if all of them were requested from the peer.
+func TestPeerRequestedEvents(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	pm, _ := newTestProtocolManagerMust(t, 5, 5, nil, nil)
+	defer pm.Stop()
+
+	p, _ := newTestPeer("peer", lachesis63, pm, true)
+	defer p.close()
+
+	events := make(inter.Events, 3)
+	for i := range events {
+		events[i] = inter.NewEvent()
+		events[i].Seq = idx.Event(i + 1)
+	}
+
+	go func() {
+		_ = p.peer.RequestEvents(hash.Events{events[0].Hash(), events[1].Hash()})
+	}()
+	assertar.NoError(p2p.ExpectMsg(p.app, GetEventsMsg, hash.Events{events[0].Hash(), events[1].Hash()}))
+
+	assertar.False(p.peer.ForgetRequested(events[1:]))
+	assertar.True(p.peer.ForgetRequested(events[:1]))
+	// already received
+	assertar.False(p.peer.ForgetRequested(events[:1]))
+}
+
+// Wire types of lachesis62 as the nodes, which don't know lachesis63, encode them.
+type (
+	baselinePackInfo62 struct {
+		Index       idx.Pack
+		Size        uint32
+		NumOfEvents uint32
+		Heads       hash.Events
+	}
+	baselineProgress62 struct {
+		Epoch        idx.Epoch
+		NumOfBlocks  idx.Block
+		LastPackInfo baselinePackInfo62
+		LastBlock    hash.Event
+	}
+	baselinePackInfos62 struct {
+		Epoch           idx.Epoch
+		TotalNumOfPacks idx.Pack
+		Infos           []baselinePackInfo62
+	}
+)
+
+// Tests that a peer of the lachesis62 nodes, which don't know lachesis63, gets only the messages
+// in lachesis62 encoding, and its messages in lachesis62 encoding are accepted.
+func TestBaseline62Compatibility(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	// lachesis62 nodes accept message codes below GetEpochTransitionsMsg only
+	assertar.Equal(uint64(0xf8), protocolLengths[lachesis62])
+
+	pm, _ := newTestProtocolManagerMust(t, 5, 5, nil, nil)
+	defer pm.Stop()
+	// scores don't decay, because the clock is stopped
+	now := time.Unix(1000000, 0)
+	cfg := reputation.DefaultConfig()
+	pm.reputation = reputation.New(cfg, memorydb.New())
+	pm.reputation.SetClock(func() time.Time { return now })
+
+	p, errc := newTestPeer("baseline", lachesis62, pm, false)
+	defer p.close()
+
+	// handshake
+	var (
+		genesis       = pm.engine.GetGenesisHash()
+		blockI, block = pm.engine.LastBlock()
+		epoch         = pm.engine.GetEpoch()
+		lastPackInfo  = pm.store.GetPackInfoOrDefault(epoch, pm.store.GetPacksNumOrDefault(epoch)-1)
+		status        = &ethStatusData{
+			ProtocolVersion:   lachesis62,
+			NetworkID:         lachesis.FakeNetworkID,
+			Genesis:           genesis,
+			DummyTD:           big.NewInt(int64(blockI)),
+			DummyCurrentBlock: common.Hash(block),
+		}
+		progress = &baselineProgress62{
+			Epoch:       epoch,
+			NumOfBlocks: blockI,
+			LastPackInfo: baselinePackInfo62{
+				Index:       lastPackInfo.Index,
+				Size:        lastPackInfo.Size,
+				NumOfEvents: lastPackInfo.NumOfEvents,
+				Heads:       lastPackInfo.Heads,
+			},
+			LastBlock: block,
+		}
+	)
+	assertar.NoError(p2p.ExpectMsg(p.app, EthStatusMsg, status))
+	assertar.NoError(p2p.Send(p.app, EthStatusMsg, status))
+	assertar.NoError(p2p.ExpectMsg(p.app, ProgressMsg, progress))
+	assertar.NoError(p2p.Send(p.app, ProgressMsg, progress))
+
+	// a message is read after the previous one is handled, so every sent message is handled
+	// once the next one is sent. Progress is a harmless message to wait for it.
+	barrier := func() {
+		assertar.NoError(p2p.Send(p.app, ProgressMsg, progress))
+	}
+
+	// progress without LowestEpoch is accepted
+	progress.NumOfBlocks++
+	barrier()
+	barrier()
+	assertar.Equal(0., pm.reputation.Score(p.peer.ID()))
+	p.peer.RLock()
+	assertar.Equal(progress.NumOfBlocks, p.peer.progress.NumOfBlocks)
+	assertar.Equal(idx.Epoch(0), p.peer.progress.LowestEpoch)
+	p.peer.RUnlock()
+
+	// lachesis63 messages are rejected
+	for i, code := range []uint64{GetEpochTransitionsMsg, EpochTransitionsMsg, CompactEventsMsg} {
+		assertar.NoError(p2p.Send(p.app, code, []interface{}{}))
+		barrier()
+		assertar.Equal(cfg.Penalties.InvalidMsg*float64(i+1), pm.reputation.Score(p.peer.ID()), code)
+	}
+	select {
+	case err := <-errc:
+		t.Fatalf("peer is disconnected: %v", err)
+	default:
+	}
+
+	tx := newTestTransaction(testAccount, 0, 0)
+	e := inter.NewEvent()
+	e.Epoch = epoch
+	e.Seq = 1
+	e.Transactions = types.Transactions{tx}
+	e.TxHash = types.DeriveSha(e.Transactions)
+	info := baselinePackInfo62{Index: 1, Size: 1, NumOfEvents: 1, Heads: hash.Events{e.Hash()}}
+
+	// received payloads are uncompressed
+	msgOf := func(code uint64, val interface{}) p2p.Msg {
+		size, r, err := rlp.EncodeToReader(val)
+		assertar.NoError(err)
+		return p2p.Msg{Code: code, Size: uint32(size), Payload: r}
+	}
+	var events inter.Events
+	if assertar.NoError(p.peer.decode(msgOf(EventsMsg, []*inter.Event{e}), &events)) && assertar.Len(events, 1) {
+		assertar.Equal(e.Hash(), events[0].Hash())
+		assertar.Equal(e.Transactions[0].Hash(), events[0].Transactions[0].Hash())
+	}
+	var infos packInfosData
+	if assertar.NoError(p.peer.decode(msgOf(PackInfosMsg, &baselinePackInfos62{Epoch: epoch, TotalNumOfPacks: 2, Infos: []baselinePackInfo62{info}}), &infos)) {
+		assertar.Equal(packInfosData{Epoch: epoch, TotalNumOfPacks: 2, Infos: []PackInfo{{
+			Index:       info.Index,
+			Size:        info.Size,
+			NumOfEvents: info.NumOfEvents,
+			Heads:       info.Heads,
+		}}}, infos)
+	}
+
+	// sent payloads are uncompressed, events are sent in full
+	p.peer.MarkTransaction(tx.Hash())
+
+	sent := make(chan error, 3)
+	go func() {
+		sent <- p.peer.SendProgress(pm.myProgress())
+		sent <- p.peer.SendEvents(inter.Events{e})
+		sent <- p.peer.SendPackInfosRLP(&packInfosDataRLP{Epoch: epoch, TotalNumOfPacks: 1})
+	}()
+	progress.NumOfBlocks--
+	assertar.NoError(p2p.ExpectMsg(p.app, ProgressMsg, progress))
+	assertar.NoError(p2p.ExpectMsg(p.app, EventsMsg, []*inter.Event{e}))
+	assertar.NoError(p2p.ExpectMsg(p.app, PackInfosMsg, &baselinePackInfos62{Epoch: epoch, TotalNumOfPacks: 1, Infos: []baselinePackInfo62{}}))
+	for i := 0; i < 3; i++ {
+		assertar.NoError(<-sent)
+	}
+}
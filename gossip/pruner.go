@@ -0,0 +1,83 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// minRetainEpochs is a min number of retained epochs: events of current epoch are being processed,
+// and packs of previous epoch are served to the peers which are still syncing it.
+const minRetainEpochs = 2
+
+// schedulePruning wakes up the pruner without blocking.
+func (s *Service) schedulePruning() {
+	select {
+	case s.pruning <- struct{}{}:
+	default:
+	}
+}
+
+// pruneLoop deletes historical data on every new epoch.
+func (s *Service) pruneLoop() {
+	defer s.wg.Done()
+
+	for {
+		s.pruneHistory()
+
+		select {
+		case <-s.pruning:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// pruneHistory deletes historical data of all the epochs which aren't retained by config.
+func (s *Service) pruneHistory() {
+	retain := s.config.RetainEpochs
+	if retain < minRetainEpochs {
+		retain = minRetainEpochs
+	}
+
+	for {
+		// sealing proof is built from the events of the sealed epoch
+		lowest := s.store.GetLowestEpoch()
+		if lowest < firstEpochTransition-1 {
+			lowest = firstEpochTransition - 1
+		}
+		s.storeEpochTransitionProof(lowest + 1)
+
+		if !s.pruneLowestEpoch(retain) {
+			return
+		}
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+	}
+}
+
+// pruneLowestEpoch deletes the lowest stored epoch, if it isn't retained.
+// Returns false if there is nothing to prune.
+func (s *Service) pruneLowestEpoch(retain idx.Epoch) bool {
+	// one epoch at a time, to not stop events processing for long
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+
+	current := s.engine.GetEpoch()
+	if current <= retain {
+		return false
+	}
+	lowest := s.store.GetLowestEpoch()
+	if lowest > current-retain {
+		return false
+	}
+
+	s.store.PruneEpoch(lowest)
+	if s.config.RetainBlockHeadersOnly {
+		s.store.PruneBlocks(lowest + 1)
+	}
+
+	s.Log.Info("Pruned historical data", "epoch", lowest)
+	return true
+}
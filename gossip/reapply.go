@@ -0,0 +1,25 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// ReapplyBlocks bootstraps the engine with the block callbacks of the service, so the blocks which are
+// decided by the stored events, but aren't applied yet (e.g. after poset.Store.Rollback), are applied.
+// It's done without starting the service, the applied state is flushed. It returns the last applied block.
+func ReapplyBlocks(config *Config, store *Store, app *app.Store, engine Consensus) (idx.Block, error) {
+	svc := newConsensusService(config, store, app, engine)
+
+	err := svc.app.Commit(nil, true)
+	if err != nil {
+		return 0, err
+	}
+	err = svc.store.Commit(nil, true)
+	if err != nil {
+		return 0, err
+	}
+
+	block, _ := svc.engine.LastBlock()
+	return block, nil
+}
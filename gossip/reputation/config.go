@@ -0,0 +1,70 @@
+package reputation
+
+import (
+	"time"
+)
+
+type (
+	// Penalties are the scores added to the peer's score for each offence.
+	Penalties struct {
+		InvalidEvent      float64
+		Timeout           float64
+		UselessAnnounce   float64
+		OversizedResponse float64
+		InvalidMsg        float64
+	}
+
+	// Config of the peers reputation.
+	Config struct {
+		Penalties Penalties
+
+		// Score halves each HalfLife while the peer isn't banned. 0 disables the decay.
+		HalfLife time.Duration
+
+		// Announces and events of the peer are ignored if its score is ThrottleScore or more.
+		// 0 disables throttling.
+		ThrottleScore float64
+		// Peer is disconnected and isn't accepted during BanDuration if its score is BanScore or more.
+		// 0 disables temporary bans.
+		BanScore    float64
+		BanDuration time.Duration
+		// Peer is banned until the ban is cleared manually if its score is PersistentBanScore or more.
+		// 0 disables persistent bans.
+		PersistentBanScore float64
+	}
+)
+
+// DefaultConfig returns the default reputation config.
+func DefaultConfig() Config {
+	return Config{
+		Penalties: Penalties{
+			InvalidEvent:      50,
+			Timeout:           2,
+			UselessAnnounce:   10,
+			OversizedResponse: 25,
+			InvalidMsg:        25,
+		},
+		HalfLife:           10 * time.Minute,
+		ThrottleScore:      50,
+		BanScore:           100,
+		BanDuration:        time.Hour,
+		PersistentBanScore: 300,
+	}
+}
+
+// penalty of the offence.
+func (p *Penalties) penalty(o Offence) float64 {
+	switch o {
+	case InvalidEvent:
+		return p.InvalidEvent
+	case Timeout:
+		return p.Timeout
+	case UselessAnnounce:
+		return p.UselessAnnounce
+	case OversizedResponse:
+		return p.OversizedResponse
+	case InvalidMsg:
+		return p.InvalidMsg
+	}
+	return 0
+}
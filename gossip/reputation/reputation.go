@@ -0,0 +1,334 @@
+// Package reputation scores peers' misbehaviour, to throttle and ban the misbehaving peers
+// instead of dropping a peer on the first offence.
+package reputation
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+)
+
+// maxRecords is a number of the tracked peers, after which the forgiven peers are forgotten.
+const maxRecords = 1024
+
+// Offence is a kind of peer's misbehaviour.
+type Offence uint8
+
+const (
+	// InvalidEvent is an event which doesn't pass the checks.
+	InvalidEvent Offence = iota
+	// Timeout is a request which isn't answered in time.
+	Timeout
+	// UselessAnnounce is an announce of data which is never delivered or isn't of any use.
+	UselessAnnounce
+	// OversizedResponse is a message above the size limits.
+	OversizedResponse
+	// InvalidMsg is a message which violates the protocol.
+	InvalidMsg
+)
+
+var offenceNames = map[Offence]string{
+	InvalidEvent:      "invalid event",
+	Timeout:           "timeout",
+	UselessAnnounce:   "useless announce",
+	OversizedResponse: "oversized response",
+	InvalidMsg:        "invalid message",
+}
+
+// String returns the offence name.
+func (o Offence) String() string {
+	return offenceNames[o]
+}
+
+// MarshalText returns the offence name.
+func (o Offence) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// Status of the peer, derived from its score.
+type Status int
+
+const (
+	// Good peer is served as usual.
+	Good Status = iota
+	// Throttled peer's announces and events are ignored.
+	Throttled
+	// Banned peer is disconnected and isn't accepted.
+	Banned
+)
+
+type (
+	// Ban of the peer.
+	Ban struct {
+		ID         enode.ID   `json:"id"`
+		Score      float64    `json:"score"`
+		Offence    Offence    `json:"offence"` // the last one
+		Since      time.Time  `json:"since"`
+		Until      *time.Time `json:"until"` // nil if persistent
+		Persistent bool       `json:"persistent"`
+	}
+
+	// record is a peer's score. Temporary bans aren't stored.
+	record struct {
+		score   float64
+		updated time.Time // the time of the last score decay
+		offence Offence
+
+		since      time.Time // the ban start
+		until      time.Time // the temporary ban end
+		persistent bool
+	}
+
+	// persistentBan is a stored record of the persistent ban.
+	persistentBan struct {
+		Since   uint64 // unix time
+		Score   uint64
+		Offence Offence
+	}
+)
+
+// Reputation tracks peers' scores. Persistent bans are stored in the db.
+type Reputation struct {
+	cfg Config
+	db  kvdb.KeyValueStore
+
+	records map[enode.ID]*record
+
+	now func() time.Time
+
+	mu sync.Mutex
+}
+
+// New loads the persistent bans from the db.
+func New(cfg Config, db kvdb.KeyValueStore) *Reputation {
+	r := &Reputation{
+		cfg:     cfg,
+		db:      db,
+		records: make(map[enode.ID]*record),
+		now:     time.Now,
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if len(it.Key()) != len(enode.ID{}) {
+			continue
+		}
+		var id enode.ID
+		copy(id[:], it.Key())
+
+		var ban persistentBan
+		if err := rlp.DecodeBytes(it.Value(), &ban); err != nil {
+			log.Error("Failed to decode peer ban", "peer", id, "err", err)
+			continue
+		}
+		r.records[id] = &record{
+			score:      float64(ban.Score),
+			offence:    ban.Offence,
+			since:      time.Unix(int64(ban.Since), 0),
+			persistent: true,
+		}
+	}
+
+	return r
+}
+
+// SetClock sets the time source of the scores decay and bans. It's time.Now by default.
+func (r *Reputation) SetClock(now func() time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.now = now
+}
+
+// Punish adds the offence penalty to the peer's score, and returns the new peer's status.
+func (r *Reputation) Punish(id enode.ID, o Offence) Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	rec := r.records[id]
+	if rec == nil {
+		if len(r.records) >= maxRecords {
+			r.forget(now)
+		}
+		rec = &record{
+			updated: now,
+		}
+		r.records[id] = rec
+	}
+	if rec.persistent {
+		return Banned
+	}
+
+	r.decay(rec, now)
+	rec.score += r.cfg.Penalties.penalty(o)
+	rec.offence = o
+
+	if r.cfg.PersistentBanScore > 0 && rec.score >= r.cfg.PersistentBanScore {
+		rec.persistent = true
+		rec.since = now
+		r.store(id, rec)
+		log.Warn("Peer is banned persistently", "peer", id, "score", rec.score, "offence", o)
+	} else if r.cfg.BanScore > 0 && rec.score >= r.cfg.BanScore && !rec.until.After(now) {
+		rec.since = now
+		rec.until = now.Add(r.cfg.BanDuration)
+		log.Warn("Peer is banned", "peer", id, "score", rec.score, "offence", o, "until", rec.until)
+	}
+
+	return r.status(rec, now)
+}
+
+// Status returns the peer's status.
+func (r *Reputation) Status(id enode.ID) Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec := r.records[id]
+	if rec == nil {
+		return Good
+	}
+	now := r.now()
+	r.decay(rec, now)
+	return r.status(rec, now)
+}
+
+// Score returns the peer's score.
+func (r *Reputation) Score(id enode.ID) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec := r.records[id]
+	if rec == nil {
+		return 0
+	}
+	r.decay(rec, r.now())
+	return rec.score
+}
+
+// Bans returns the current bans, ordered by peer ID.
+func (r *Reputation) Bans() []*Ban {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	bans := make([]*Ban, 0, len(r.records))
+	for id, rec := range r.records {
+		if r.status(rec, now) != Banned {
+			continue
+		}
+		ban := &Ban{
+			ID:         id,
+			Score:      rec.score,
+			Offence:    rec.offence,
+			Since:      rec.since,
+			Persistent: rec.persistent,
+		}
+		if !rec.persistent {
+			until := rec.until
+			ban.Until = &until
+		}
+		bans = append(bans, ban)
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bytes.Compare(bans[i].ID[:], bans[j].ID[:]) < 0
+	})
+	return bans
+}
+
+// Unban clears the peer's ban and score. It returns false if the peer isn't banned.
+func (r *Reputation) Unban(id enode.ID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec := r.records[id]
+	if rec == nil || r.status(rec, r.now()) != Banned {
+		return false
+	}
+	r.unban(id, rec)
+	return true
+}
+
+// UnbanAll clears all the bans and scores of the banned peers. It returns the number of cleared bans.
+func (r *Reputation) UnbanAll() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	cleared := 0
+	for id, rec := range r.records {
+		if r.status(rec, now) != Banned {
+			continue
+		}
+		r.unban(id, rec)
+		cleared++
+	}
+	return cleared
+}
+
+func (r *Reputation) unban(id enode.ID, rec *record) {
+	if rec.persistent {
+		if err := r.db.Delete(id[:]); err != nil {
+			log.Error("Failed to erase peer ban", "peer", id, "err", err)
+		}
+	}
+	delete(r.records, id)
+}
+
+// decay reduces the score according to the time passed, except the time of the ban.
+func (r *Reputation) decay(rec *record, now time.Time) {
+	if rec.persistent {
+		return
+	}
+	from := rec.updated
+	if rec.until.After(from) {
+		from = rec.until
+	}
+	if now.After(from) && r.cfg.HalfLife > 0 {
+		rec.score *= math.Pow(0.5, float64(now.Sub(from))/float64(r.cfg.HalfLife))
+	}
+	rec.updated = now
+}
+
+func (r *Reputation) status(rec *record, now time.Time) Status {
+	if rec.persistent || rec.until.After(now) {
+		return Banned
+	}
+	if r.cfg.ThrottleScore > 0 && rec.score >= r.cfg.ThrottleScore {
+		return Throttled
+	}
+	return Good
+}
+
+// forget erases records of the forgiven peers.
+func (r *Reputation) forget(now time.Time) {
+	for id, rec := range r.records {
+		r.decay(rec, now)
+		if r.status(rec, now) == Good && rec.score < 1 {
+			delete(r.records, id)
+		}
+	}
+}
+
+func (r *Reputation) store(id enode.ID, rec *record) {
+	buf, err := rlp.EncodeToBytes(&persistentBan{
+		Since:   uint64(rec.since.Unix()),
+		Score:   uint64(rec.score),
+		Offence: rec.offence,
+	})
+	if err != nil {
+		log.Crit("Failed to encode rlp", "err", err)
+	}
+	if err := r.db.Put(common.CopyBytes(id[:]), buf); err != nil {
+		log.Error("Failed to store peer ban", "peer", id, "err", err)
+	}
+}
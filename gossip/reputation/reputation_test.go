@@ -0,0 +1,91 @@
+package reputation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestReputation(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	db := memorydb.New()
+	now := time.Unix(1000000, 0)
+	cfg := DefaultConfig()
+
+	r := New(cfg, db)
+	r.SetClock(func() time.Time { return now })
+
+	a := enode.ID{1}
+	b := enode.ID{2}
+
+	// thresholds
+	assertar.Equal(Good, r.Punish(a, Timeout))
+	assertar.Equal(Good, r.Status(b))
+	assertar.Equal(Throttled, r.Punish(a, InvalidEvent))
+	assertar.Equal(Banned, r.Punish(a, InvalidEvent))
+	assertar.Equal(Good, r.Status(b))
+	assertar.Len(r.Bans(), 1)
+	assertar.Equal(a, r.Bans()[0].ID)
+	assertar.Equal(InvalidEvent, r.Bans()[0].Offence)
+	assertar.False(r.Bans()[0].Persistent)
+
+	// score doesn't decay during the ban
+	now = now.Add(cfg.BanDuration - time.Second)
+	assertar.Equal(Banned, r.Status(a))
+	assertar.InDelta(102, r.Score(a), 0.001)
+
+	// score decays after the ban
+	now = now.Add(time.Second + cfg.HalfLife)
+	assertar.Equal(Throttled, r.Status(a))
+	assertar.InDelta(51, r.Score(a), 0.001)
+	assertar.Empty(r.Bans())
+	now = now.Add(cfg.HalfLife)
+	assertar.Equal(Good, r.Status(a))
+
+	// persistent ban
+	for r.Punish(b, InvalidMsg) != Banned {
+	}
+	now = now.Add(cfg.BanDuration)
+	assertar.Equal(Throttled, r.Status(b))
+	for i := 0; i < 10; i++ {
+		r.Punish(b, InvalidEvent)
+	}
+	now = now.Add(cfg.BanDuration * 10)
+	assertar.Equal(Banned, r.Status(b))
+	assertar.Len(r.Bans(), 1)
+	assertar.True(r.Bans()[0].Persistent)
+	assertar.Nil(r.Bans()[0].Until)
+
+	// persistent ban is loaded from db
+	r = New(cfg, db)
+	r.SetClock(func() time.Time { return now })
+	assertar.Equal(Banned, r.Status(b))
+	assertar.Equal(Good, r.Status(a))
+	assertar.Len(r.Bans(), 1)
+
+	// unban
+	assertar.False(r.Unban(a))
+	assertar.True(r.Unban(b))
+	assertar.Equal(Good, r.Status(b))
+	assertar.Empty(r.Bans())
+
+	r = New(cfg, db)
+	assertar.Equal(Good, r.Status(b))
+
+	// unban all
+	r.SetClock(func() time.Time { return now })
+	r.Punish(a, InvalidEvent)
+	r.Punish(a, InvalidEvent)
+	r.Punish(b, InvalidEvent)
+	r.Punish(b, InvalidEvent)
+	assertar.Equal(2, r.UnbanAll())
+	assertar.Equal(Good, r.Status(a))
+	assertar.Equal(Good, r.Status(b))
+}
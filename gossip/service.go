@@ -3,6 +3,7 @@ package gossip
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
 	notify "github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -30,12 +32,15 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/gossip/filters"
 	"github.com/Fantom-foundation/go-lachesis/gossip/gasprice"
 	"github.com/Fantom-foundation/go-lachesis/gossip/occuredtxs"
+	"github.com/Fantom-foundation/go-lachesis/gossip/snapshot"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/keyspace"
 	"github.com/Fantom-foundation/go-lachesis/lachesis"
 	"github.com/Fantom-foundation/go-lachesis/lachesis/params"
 	"github.com/Fantom-foundation/go-lachesis/logger"
+	"github.com/Fantom-foundation/go-lachesis/utils/sigcache"
 )
 
 const (
@@ -81,8 +86,10 @@ func (f *ServiceFeed) SubscribeNewLogs(ch chan<- []*types.Log) notify.Subscripti
 type Service struct {
 	config *Config
 
-	wg   sync.WaitGroup
-	done chan struct{}
+	wg      sync.WaitGroup
+	done    chan struct{}
+	pruning chan struct{}
+	proving chan struct{}
 
 	// server
 	Name  string
@@ -106,6 +113,11 @@ type Service struct {
 	// global variables. TODO refactor to pass them as arguments if possible
 	blockParticipated map[idx.StakerID]bool // validators who participated in last block
 	currentEvent      hash.Event            // current event which is being processed
+	// cheaters of the epoch, whose fork evidence isn't found, to not rescan the epoch on each block
+	missedForkEvidences struct {
+		epoch    idx.Epoch
+		cheaters map[idx.StakerID]bool
+	}
 
 	feed ServiceFeed
 
@@ -119,35 +131,9 @@ type Service struct {
 }
 
 func NewService(ctx *node.ServiceContext, config *Config, store *Store, engine Consensus, app *app.Store) (*Service, error) {
-	svc := &Service{
-		config: config,
-
-		done: make(chan struct{}),
-
-		Name: fmt.Sprintf("Node-%d", rand.Int()),
-
-		node:  ctx,
-		store: store,
-		app:   app,
-
-		engineMu:          new(sync.RWMutex),
-		occurredTxs:       occuredtxs.New(txsRingBufferSize, types.NewEIP155Signer(config.Net.EvmChainConfig().ChainID)),
-		blockParticipated: make(map[idx.StakerID]bool),
-
-		Instance: logger.MakeInstance(),
-	}
-
-	// wrap engine
-	svc.engine = &HookedEngine{
-		engine:       engine,
-		processEvent: svc.processEvent,
-	}
-	svc.engine.Bootstrap(inter.ConsensusCallbacks{
-		ApplyBlock:              svc.applyBlock,
-		SelectValidatorsGroup:   svc.selectValidatorsGroup,
-		OnEventConfirmed:        svc.onEventConfirmed,
-		IsEventAllowedIntoBlock: svc.isEventAllowedIntoBlock,
-	})
+	svc := newConsensusService(config, store, app, engine)
+	svc.Name = fmt.Sprintf("Node-%d", rand.Int())
+	svc.node = ctx
 
 	// create server pool
 	trustedNodes := []string{}
@@ -155,15 +141,19 @@ func NewService(ctx *node.ServiceContext, config *Config, store *Store, engine C
 
 	// create tx pool
 	stateReader := svc.GetEvmStateReader()
+	if config.ReadOnly {
+		config.TxPool.Journal = ""
+	}
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
-	svc.txpool = evmcore.NewTxPool(config.TxPool, config.Net.EvmChainConfig(), stateReader)
+	sigs := sigcache.New(config.SigCacheSize)
+	svc.txpool = evmcore.NewTxPoolWithSigCache(config.TxPool, config.Net.EvmChainConfig(), stateReader, sigs)
 
 	// create checkers
 	svc.heavyCheckReader.Addrs.Store(ReadEpochPubKeys(svc.app, svc.engine.GetEpoch()))                                                                     // read pub keys of current epoch from disk
 	svc.gasPowerCheckReader.Ctx.Store(ReadGasPowerContext(svc.store, svc.app, svc.engine.GetValidators(), svc.engine.GetEpoch(), &svc.config.Net.Economy)) // read gaspower check data from disk
-	svc.checkers = makeCheckers(&svc.config.Net, &svc.heavyCheckReader, &svc.gasPowerCheckReader, svc.engine, svc.store)
+	svc.checkers = makeCheckers(&svc.config.Net, &svc.heavyCheckReader, &svc.gasPowerCheckReader, svc.engine, svc.store, sigs)
 
 	// create protocol manager
 	var err error
@@ -176,22 +166,56 @@ func NewService(ctx *node.ServiceContext, config *Config, store *Store, engine C
 	return svc, err
 }
 
+// newConsensusService makes the service with the bootstrapped engine, which applies the decided blocks.
+// The network, the tx pool and the emitter aren't created.
+func newConsensusService(config *Config, store *Store, app *app.Store, engine Consensus) *Service {
+	svc := &Service{
+		config: config,
+
+		done:    make(chan struct{}),
+		pruning: make(chan struct{}, 1),
+		proving: make(chan struct{}, 1),
+
+		store: store,
+		app:   app,
+
+		engineMu:          new(sync.RWMutex),
+		occurredTxs:       occuredtxs.New(txsRingBufferSize, types.NewEIP155Signer(config.Net.EvmChainConfig().ChainID)),
+		blockParticipated: make(map[idx.StakerID]bool),
+
+		Instance: logger.MakeInstance(),
+	}
+
+	// wrap engine
+	svc.engine = &HookedEngine{
+		engine:       engine,
+		processEvent: svc.processEvent,
+	}
+	svc.engine.Bootstrap(svc.consensusCallbacks())
+
+	return svc
+}
+
 // makeCheckers builds event checkers
-func makeCheckers(net *lachesis.Config, heavyCheckReader *HeavyCheckReader, gasPowerCheckReader *GasPowerCheckReader, engine Consensus, store *Store) *eventcheck.Checkers {
+func makeCheckers(net *lachesis.Config, heavyCheckReader *HeavyCheckReader, gasPowerCheckReader *GasPowerCheckReader, engine Consensus, store *Store, sigs *sigcache.Cache) *eventcheck.Checkers {
 	// create signatures checker
 	ledgerID := net.EvmChainConfig().ChainID
-	heavyCheck := heavycheck.NewDefault(&net.Dag, heavyCheckReader, types.NewEIP155Signer(ledgerID))
+	heavyCheck := heavycheck.NewDefault(&net.Dag, heavyCheckReader, sigcache.NewSigner(types.NewEIP155Signer(ledgerID), sigs), sigs)
 
 	// create gaspower checker
 	gaspowerCheck := gaspowercheck.New(gasPowerCheckReader)
 
-	return &eventcheck.Checkers{
+	checkers := &eventcheck.Checkers{
 		Basiccheck:    basiccheck.New(&net.Dag),
 		Epochcheck:    epochcheck.New(&net.Dag, engine),
 		Parentscheck:  parentscheck.New(&net.Dag),
 		Heavycheck:    heavyCheck,
 		Gaspowercheck: gaspowerCheck,
 	}
+	// custom rules
+	checkers.AddRules(eventcheck.Registered()...)
+
+	return checkers
 }
 
 func (s *Service) makeEmitter() *Emitter {
@@ -231,11 +255,15 @@ func (s *Service) makeEmitter() *Emitter {
 			AddVersion: func(e *inter.Event) *inter.Event {
 				// serialization version
 				e.Version = 0
-				// node version
+				// node version and gas price hint
 				if e.Seq <= 1 && len(s.config.Emitter.VersionToPublish) > 0 {
-					version := []byte("v-" + s.config.Emitter.VersionToPublish)
-					if len(version) <= params.MaxExtraData {
-						e.Extra = version
+					extra := inter.ExtraFields{
+						NodeVersion:  s.config.Emitter.VersionToPublish,
+						GasPriceHint: s.txpool.GasPrice(),
+					}
+					raw, err := extra.Encode()
+					if err == nil && len(raw) <= params.MaxExtraData {
+						e.Extra = raw
 					}
 				}
 
@@ -248,6 +276,10 @@ func (s *Service) makeEmitter() *Emitter {
 
 // Protocols returns protocols the service can communicate on.
 func (s *Service) Protocols() []p2p.Protocol {
+	if s.config.ReadOnly {
+		return nil
+	}
+
 	protos := make([]p2p.Protocol, len(ProtocolVersions))
 	for i, vsn := range ProtocolVersions {
 		protos[i] = s.pm.makeProtocol(vsn)
@@ -276,6 +308,14 @@ func (s *Service) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   s.netRPCService,
 			Public:    true,
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewPrivateDebugAPI(s),
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateAdminAPI(s),
 		},
 	}...)
 
@@ -302,12 +342,25 @@ func (s *Service) Start(srv *p2p.Server) error {
 
 	s.pm.Start(srv.MaxPeers)
 
+	s.emitter = s.makeEmitter()
+	if s.config.ReadOnly {
+		// serve API only, nothing may be written
+		return nil
+	}
+
 	s.serverPool.start(srv, s.Topic)
 
-	s.emitter = s.makeEmitter()
 	s.emitter.SetValidator(s.config.Emitter.Validator)
 	s.emitter.StartEventEmission()
 
+	s.wg.Add(1)
+	go s.provingLoop()
+
+	if s.config.RetainEpochs != 0 {
+		s.wg.Add(1)
+		go s.pruneLoop()
+	}
+
 	return nil
 }
 
@@ -319,15 +372,71 @@ func (s *Service) Stop() error {
 	s.wg.Wait()
 	s.feed.scope.Close()
 
-	// flush the state at exit, after all the routines stopped
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+
+	if !s.config.ReadOnly {
+		// flush the state at exit, after all the routines stopped
+		err := s.app.Commit(nil, true)
+		if err != nil {
+			return err
+		}
+		err = s.store.Commit(nil, true)
+		if err != nil {
+			return err
+		}
+	}
+	return s.store.dbs.CloseAll()
+}
+
+// SnapshotDb makes a consistent copy of all the databases into the dir.
+func (s *Service) SnapshotDb(dir string) (*snapshot.Manifest, error) {
+	// stop events processing, to get epoch and block of the flushed state
 	s.engineMu.Lock()
 	defer s.engineMu.Unlock()
 
 	err := s.app.Commit(nil, true)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	err = s.store.Commit(nil, true)
+	if err != nil {
+		return nil, err
 	}
-	return s.store.Commit(nil, true)
+
+	block, _ := s.engine.LastBlock()
+	return snapshot.Make(dir, s.config.DBEngine, s.store.dbs, s.engine.GetEpoch(), block)
+}
+
+// DbStats walks all the flushed dbs and reports their key-space usage by tables.
+// Flushing is paused only while the iterators are taken. Iterators are the dbs snapshots,
+// so the walk isn't affected by the following flushes and doesn't block them.
+func (s *Service) DbStats(top int) ([]*keyspace.DbStats, error) {
+	dbs, resume := s.store.dbs.Pause()
+	its := make(map[string]ethdb.Iterator, len(dbs))
+	for name, db := range dbs {
+		its[name] = db.NewIterator()
+	}
+	resume()
+	defer func() {
+		for _, it := range its {
+			it.Release()
+		}
+	}()
+
+	schema := DbSchema()
+	res := make([]*keyspace.DbStats, 0, len(its))
+	for name, it := range its {
+		stats, err := keyspace.CollectIterator(name, it, schema.Find(name), top)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, stats)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Db < res[j].Db
+	})
+	return res, nil
 }
 
 // AccountManager return node's account manager
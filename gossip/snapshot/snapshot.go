@@ -0,0 +1,222 @@
+// Package snapshot makes and restores consistent copies of node databases.
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/dbengine"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
+)
+
+// ManifestFile is a name of the manifest file in the snapshot dir.
+const ManifestFile = "manifest.json"
+
+// Manifest describes the snapshot content.
+type Manifest struct {
+	Engine  string        `json:"engine"`
+	Epoch   idx.Epoch     `json:"epoch"`
+	Block   idx.Block     `json:"block"`
+	FlushID hexutil.Bytes `json:"flushId"`
+	Dbs     []string      `json:"dbs"`
+}
+
+// Make copies all the flushed dbs of the pool into the empty dir.
+// Flushing is paused while copying, so the copy is consistent.
+func Make(dir, engine string, pool *flushable.SyncedPool, epoch idx.Epoch, block idx.Block) (*Manifest, error) {
+	if !isEmptyDir(dir) {
+		return nil, fmt.Errorf("snapshot dir %s isn't empty", dir)
+	}
+	if engine == "" {
+		engine = dbengine.LevelDb
+	}
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := dbengine.NewProducer(engine, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	dbs, resume := pool.Pause()
+	defer resume()
+
+	m := &Manifest{
+		Engine: engine,
+		Epoch:  epoch,
+		Block:  block,
+	}
+	for name := range dbs {
+		m.Dbs = append(m.Dbs, name)
+	}
+	if len(m.Dbs) == 0 {
+		return nil, fmt.Errorf("no flushed dbs")
+	}
+	sort.Strings(m.Dbs)
+
+	for _, name := range m.Dbs {
+		id, err := flushable.GetFlushID(dbs[name])
+		if err != nil {
+			return nil, err
+		}
+		if id == nil {
+			return nil, fmt.Errorf("db %s isn't synced: no flush id", name)
+		}
+		if m.FlushID == nil {
+			m.FlushID = id
+		} else if !bytes.Equal(m.FlushID, id) {
+			return nil, fmt.Errorf("db %s isn't synced: flush id %s != %s", name, hexutil.Encode(id), m.FlushID)
+		}
+
+		err = copyDb(producer, name, dbs[name])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return m, WriteManifest(dir, m)
+}
+
+// Verify checks that snapshot in the dir matches its manifest.
+func Verify(dir string) (*Manifest, error) {
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := dbengine.NewProducer(m.Engine, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := producer.Names()
+	sort.Strings(names)
+	if len(names) != len(m.Dbs) {
+		return nil, fmt.Errorf("snapshot contains dbs %v, but manifest lists %v", names, m.Dbs)
+	}
+	for i, name := range m.Dbs {
+		if names[i] != name {
+			return nil, fmt.Errorf("snapshot contains dbs %v, but manifest lists %v", names, m.Dbs)
+		}
+
+		db := producer.OpenDb(name)
+		id, err := flushable.GetFlushID(db)
+		_ = db.Close()
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(id, m.FlushID) {
+			return nil, fmt.Errorf("db %s has flush id %s, but manifest has %s", name, hexutil.Encode(id), m.FlushID)
+		}
+	}
+
+	return m, nil
+}
+
+// Restore verifies the snapshot in the dir and copies it into the datadir, which must have no dbs.
+func Restore(dir, datadir string) (*Manifest, error) {
+	m, err := Verify(dir)
+	if err != nil {
+		return nil, err
+	}
+	if engines := dbengine.Detect(datadir); len(engines) > 0 {
+		return nil, fmt.Errorf("datadir %s already contains %v databases", datadir, engines)
+	}
+
+	err = os.MkdirAll(datadir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	src, _ := dbengine.NewProducer(m.Engine, dir)
+	dst, _ := dbengine.NewProducer(m.Engine, datadir)
+
+	for _, name := range m.Dbs {
+		db := src.OpenDb(name)
+		err = copyDb(dst, name, db)
+		_ = db.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// WriteManifest into the dir.
+func WriteManifest(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, ManifestFile), data, 0600)
+}
+
+// ReadManifest from the dir.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	err = json.Unmarshal(data, m)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest: %v", err)
+	}
+	if len(m.Dbs) == 0 || len(m.FlushID) == 0 {
+		return nil, fmt.Errorf("invalid manifest: no dbs or flush id")
+	}
+	sort.Strings(m.Dbs)
+	return m, nil
+}
+
+// copyDb into the new db with the name.
+func copyDb(producer kvdb.DbProducer, name string, src ethdb.Iteratee) (err error) {
+	dst := producer.OpenDb(name)
+	defer func() {
+		closeErr := dst.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	it := src.NewIterator()
+	defer it.Release()
+
+	batch := dst.NewBatch()
+	for it.Next() {
+		err = batch.Put(it.Key(), it.Value())
+		if err != nil {
+			return err
+		}
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			err = batch.Write()
+			if err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if it.Error() != nil {
+		return it.Error()
+	}
+	return batch.Write()
+}
+
+func isEmptyDir(dir string) bool {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return true
+	}
+	return err == nil && len(files) == 0
+}
@@ -0,0 +1,170 @@
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb/dbengine"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "snapshot-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestSnapshot(t *testing.T) {
+	for _, engine := range dbengine.Engines {
+		t.Run(engine, func(t *testing.T) {
+			testSnapshot(t, engine)
+		})
+	}
+}
+
+func testSnapshot(t *testing.T, engine string) {
+	assertar := assert.New(t)
+	noErr := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root := tempDir(t)
+	defer os.RemoveAll(root)
+
+	datadir := filepath.Join(root, "datadir")
+	noErr(os.MkdirAll(datadir, 0700))
+	producer, err := dbengine.NewProducer(engine, datadir)
+	noErr(err)
+
+	pool := flushable.NewSyncedPool(producer)
+	noErr(pool.GetDb("a").Put([]byte("key"), []byte("a")))
+	noErr(pool.GetDb("b").Put([]byte("key"), []byte("b")))
+	noErr(pool.Flush([]byte("1")))
+	// not flushed data isn't in snapshot
+	noErr(pool.GetDb("a").Put([]byte("key"), []byte("a2")))
+	noErr(pool.GetDb("c").Put([]byte("key"), []byte("c")))
+
+	dir := filepath.Join(root, "snapshot")
+	m, err := Make(dir, engine, pool, 2, 3)
+	noErr(err)
+	assertar.Equal([]string{"a", "b"}, m.Dbs)
+	assertar.Equal([]byte("1"), []byte(m.FlushID))
+
+	_, err = Make(dir, engine, pool, 2, 3)
+	assertar.Error(err, "dir isn't empty")
+
+	// flushing is resumed
+	noErr(pool.Flush([]byte("2")))
+
+	got, err := Verify(dir)
+	noErr(err)
+	assertar.Equal(m, got)
+
+	_, err = Restore(dir, datadir)
+	assertar.Error(err, "datadir has dbs")
+
+	restored := filepath.Join(root, "restored")
+	got, err = Restore(dir, restored)
+	noErr(err)
+	assertar.Equal(m, got)
+
+	producer, err = dbengine.NewProducer(engine, restored)
+	noErr(err)
+	pool = flushable.NewSyncedPool(producer)
+	val, err := pool.GetDb("a").Get([]byte("key"))
+	noErr(err)
+	assertar.Equal([]byte("a"), val)
+	val, err = pool.GetDb("b").Get([]byte("key"))
+	noErr(err)
+	assertar.Equal([]byte("b"), val)
+	val, err = pool.GetDb("c").Get([]byte("key"))
+	noErr(err)
+	assertar.Nil(val)
+}
+
+func TestSnapshotVerify(t *testing.T) {
+	assertar := assert.New(t)
+	noErr := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root := tempDir(t)
+	defer os.RemoveAll(root)
+
+	datadir := filepath.Join(root, "datadir")
+	noErr(os.MkdirAll(datadir, 0700))
+	producer, err := dbengine.NewProducer(dbengine.LevelDb, datadir)
+	noErr(err)
+	pool := flushable.NewSyncedPool(producer)
+	noErr(pool.GetDb("a").Put([]byte("key"), []byte("a")))
+	noErr(pool.Flush([]byte("1")))
+
+	dir := filepath.Join(root, "snapshot")
+	m, err := Make(dir, dbengine.LevelDb, pool, 1, 1)
+	noErr(err)
+
+	// wrong flush id
+	m.FlushID = []byte("2")
+	noErr(WriteManifest(dir, m))
+	_, err = Verify(dir)
+	assertar.Error(err)
+
+	// missing db
+	m.FlushID = []byte("1")
+	m.Dbs = []string{"a", "b"}
+	noErr(WriteManifest(dir, m))
+	_, err = Verify(dir)
+	assertar.Error(err)
+
+	// unknown engine
+	m.Dbs = []string{"a"}
+	m.Engine = "unknown"
+	noErr(WriteManifest(dir, m))
+	_, err = Verify(dir)
+	assertar.Error(err)
+
+	// no manifest
+	noErr(os.Remove(filepath.Join(dir, ManifestFile)))
+	_, err = Restore(dir, filepath.Join(root, "restored"))
+	assertar.Error(err)
+}
+
+func TestSnapshotUnsynced(t *testing.T) {
+	assertar := assert.New(t)
+	noErr := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root := tempDir(t)
+	defer os.RemoveAll(root)
+
+	datadir := filepath.Join(root, "datadir")
+	noErr(os.MkdirAll(datadir, 0700))
+	producer, err := dbengine.NewProducer(dbengine.LevelDb, datadir)
+	noErr(err)
+
+	pool := flushable.NewSyncedPool(producer)
+	noErr(pool.GetDb("b").Put([]byte("key"), []byte("b")))
+	noErr(pool.Flush([]byte("1")))
+
+	// db without flush id, which is listed before the flushed one
+	db := producer.OpenDb("a")
+	noErr(db.Put([]byte("key"), []byte("a")))
+	noErr(db.Close())
+	pool.GetDb("a")
+
+	_, err = Make(filepath.Join(root, "snapshot"), dbengine.LevelDb, pool, 1, 1)
+	assertar.Error(err)
+}
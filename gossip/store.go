@@ -12,11 +12,13 @@ import (
 
 	"github.com/Fantom-foundation/go-lachesis/common/bigendian"
 	"github.com/Fantom-foundation/go-lachesis/gossip/temporary"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/kvdb"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/table"
 	"github.com/Fantom-foundation/go-lachesis/logger"
+	"github.com/Fantom-foundation/go-lachesis/utils/bloom"
 )
 
 // Store is a node persistent storage working over physical key-value database.
@@ -30,15 +32,25 @@ type Store struct {
 		Peers kvdb.KeyValueStore `table:"Z"`
 
 		// Main DAG tables
-		Events    kvdb.KeyValueStore `table:"e"`
-		Blocks    kvdb.KeyValueStore `table:"b"`
-		PackInfos kvdb.KeyValueStore `table:"p"`
-		Packs     kvdb.KeyValueStore `table:"P"`
-		PacksNum  kvdb.KeyValueStore `table:"n"`
+		Events      kvdb.KeyValueStore `table:"e"`
+		EventsBloom kvdb.KeyValueStore `table:"B"`
+		Blocks      kvdb.KeyValueStore `table:"b"`
+		PackInfos   kvdb.KeyValueStore `table:"p"`
+		Packs       kvdb.KeyValueStore `table:"P"`
+		PacksNum    kvdb.KeyValueStore `table:"n"`
+
+		// History retention tables
+		Pruned kvdb.KeyValueStore `table:"k"`
 
 		// general economy tables
 		EpochStats kvdb.KeyValueStore `table:"E"`
 
+		// light client tables
+		EpochTransitions kvdb.KeyValueStore `table:"t"`
+
+		// slashing audit tables
+		ForkEvidences kvdb.KeyValueStore `table:"f"`
+
 		// gas power economy tables
 		LastEpochHeaders kvdb.KeyValueStore `table:"l"`
 
@@ -49,10 +61,14 @@ type Store struct {
 		EventLocalTimes kvdb.KeyValueStore `table:"!"`
 
 		TmpDbs kvdb.KeyValueStore `table:"T"`
+
+		Version kvdb.KeyValueStore `table:"_"`
 	}
 
 	EpochDbs *temporary.Dbs
 
+	bloom eventsBloom
+
 	cache struct {
 		Events        *lru.Cache `cache:"-"` // store by pointer
 		EventsHeaders *lru.Cache `cache:"-"` // store by pointer
@@ -61,10 +77,12 @@ type Store struct {
 		EpochStats    *lru.Cache `cache:"-"` // store by value
 		TxPositions   *lru.Cache `cache:"-"` // store by pointer
 		BlockHashes   *lru.Cache `cache:"-"` // store by pointer
+		BlockProofs   *lru.Cache `cache:"-"` // store by pointer
 	}
 
 	mutex struct {
-		Inc sync.Mutex
+		Inc         sync.Mutex
+		BlockProofs sync.Mutex
 	}
 
 	logger.Instance
@@ -87,6 +105,8 @@ func NewStore(dbs *flushable.SyncedPool, cfg StoreConfig) *Store {
 		mainDb:   dbs.GetDb("gossip-main"),
 		Instance: logger.MakeInstance(),
 	}
+	s.bloom.filters = make(map[idx.Epoch]*bloom.Filter)
+	s.bloom.dirty = make(map[idx.Epoch]bool)
 
 	table.MigrateTables(&s.table, s.mainDb)
 
@@ -101,9 +121,6 @@ func NewStore(dbs *flushable.SyncedPool, cfg StoreConfig) *Store {
 
 	s.initCache()
 
-	// for compability with db before commit 591ede6
-	s.rmPrefix(s.table.PackInfos, "serverPool")
-
 	return s
 }
 
@@ -123,6 +140,7 @@ func (s *Store) initCache() {
 	s.cache.EpochStats = s.makeCache(s.cfg.EpochStatsCacheSize)
 	s.cache.TxPositions = s.makeCache(s.cfg.TxPositionsCacheSize)
 	s.cache.BlockHashes = s.makeCache(s.cfg.BlockCacheSize)
+	s.cache.BlockProofs = s.makeCache(s.cfg.BlockProofsCacheSize)
 }
 
 // Close leaves underlying database.
@@ -137,6 +155,26 @@ func (s *Store) Close() {
 	s.mainDb.Close()
 }
 
+// AddCheckpointedTables adds the tables, which are modified by the blocks applying, into the scope
+// of the poset checkpoints. The network and DAG tables are modified concurrently, so they aren't added.
+func (s *Store) AddCheckpointedTables(scope flushable.UndoScope) {
+	scope.Add("gossip-main", table.Prefixes(
+		s.table.Blocks,
+		s.table.EpochStats,
+		s.table.EpochTransitions,
+		s.table.ForkEvidences,
+		s.table.LastEpochHeaders,
+		s.table.BlockHashes,
+		s.table.TxPositions,
+		s.table.DecisiveEvents,
+	)...)
+}
+
+// IsCommitNeeded returns true if changes should be flushed.
+func (s *Store) IsCommitNeeded() bool {
+	return s.dbs.IsFlushNeeded()
+}
+
 // Commit changes.
 func (s *Store) Commit(flushID []byte, immediately bool) error {
 	if flushID == nil {
@@ -151,6 +189,8 @@ func (s *Store) Commit(flushID []byte, immediately bool) error {
 		return nil
 	}
 
+	s.flushEventsBloom()
+
 	// Flush the DBs
 	return s.dbs.Flush(flushID)
 }
@@ -90,6 +90,8 @@ func (s *Store) GetLastEvent(epoch idx.Epoch, from idx.StakerID) *hash.Event {
 
 // SetEventHeader returns stored event header.
 func (s *Store) SetEventHeader(epoch idx.Epoch, h hash.Event, e *inter.EventHeaderData) {
+	s.addToEventsBloom(h)
+
 	es := s.getEpochStore(epoch)
 	if es == nil {
 		return
@@ -140,7 +142,10 @@ func (s *Store) HasEventHeader(h hash.Event) bool {
 		return false
 	}
 
-	return s.has(es.Headers, h.Bytes())
+	// header is stored together with event, so bloom filter of events is suitable
+	return s.checkEventsBloom(h, func() bool {
+		return s.has(es.Headers, h.Bytes())
+	})
 }
 
 // DelEventHeader removes stored event header.
@@ -0,0 +1,31 @@
+package gossip
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-lachesis/finality"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+// firstEpochTransition is the epoch after the genesis one, genesis validators have no transition.
+const firstEpochTransition = idx.Epoch(2)
+
+// SetEpochTransition stores the validators group transition to the epoch.
+func (s *Store) SetEpochTransition(t *finality.EpochTransition) {
+	s.set(s.table.EpochTransitions, t.Epoch.Bytes(), t)
+}
+
+// GetEpochTransition returns the stored validators group transition to the epoch.
+func (s *Store) GetEpochTransition(epoch idx.Epoch) *finality.EpochTransition {
+	t, _ := s.get(s.table.EpochTransitions, epoch.Bytes(), &finality.EpochTransition{}).(*finality.EpochTransition)
+	return t
+}
+
+// GetEpochTransitionRLP returns the stored validators group transition to the epoch in RLP.
+func (s *Store) GetEpochTransitionRLP(epoch idx.Epoch) rlp.RawValue {
+	buf, err := s.table.EpochTransitions.Get(epoch.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	return buf
+}
@@ -38,6 +38,7 @@ func (s *Store) SetEvent(e *inter.Event) {
 
 	s.set(s.table.Events, key, e)
 	s.SetEventHeader(e.Epoch, e.Hash(), &e.EventHeaderData)
+	s.addToEventsBloom(e.Hash())
 
 	// Add to LRU cache.
 	if s.cache.Events != nil {
@@ -58,7 +59,11 @@ func (s *Store) GetEvent(id hash.Event) *inter.Event {
 		}
 	}
 
-	w, _ := s.get(s.table.Events, key, &inter.Event{}).(*inter.Event)
+	var w *inter.Event
+	s.checkEventsBloom(id, func() bool {
+		w, _ = s.get(s.table.Events, key, &inter.Event{}).(*inter.Event)
+		return w != nil
+	})
 
 	// Put event to LRU cache.
 	if w != nil && s.cache.Events != nil {
@@ -112,5 +117,7 @@ func (s *Store) GetEventRLP(id hash.Event) rlp.RawValue {
 
 // HasEvent returns true if event exists.
 func (s *Store) HasEvent(h hash.Event) bool {
-	return s.has(s.table.Events, h.Bytes())
+	return s.checkEventsBloom(h, func() bool {
+		return s.has(s.table.Events, h.Bytes())
+	})
 }
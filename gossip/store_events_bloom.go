@@ -0,0 +1,182 @@
+package gossip
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/utils/bloom"
+)
+
+const (
+	// eventsBloomFpRate is a false positive rate of the events bloom filter, if epoch has EventsBloomSize events.
+	eventsBloomFpRate = 0.01
+	// eventsBloomEpochs is a max number of the epoch filters kept in memory.
+	eventsBloomEpochs = 3
+)
+
+// eventsBloom keeps bloom filters of the Events table, by epochs.
+type eventsBloom struct {
+	filters map[idx.Epoch]*bloom.Filter
+	dirty   map[idx.Epoch]bool
+	mu      sync.Mutex
+}
+
+// isEventsBloomEnabled returns true if bloom filter of the Events table is used.
+func (s *Store) isEventsBloomEnabled() bool {
+	return s.cfg.EventsBloomSize > 0
+}
+
+// checkEventsBloom returns false if event definitely isn't stored, according to the bloom filter.
+// Otherwise it returns result of the disk lookup.
+func (s *Store) checkEventsBloom(id hash.Event, lookup func() bool) bool {
+	if !s.isEventsBloomEnabled() {
+		return lookup()
+	}
+
+	s.bloom.mu.Lock()
+	maybe := s.getEventsBloom(id.Epoch()).Has(id.Bytes())
+	s.bloom.mu.Unlock()
+
+	if !maybe {
+		eventsBloomNegativeMeter.Mark(1)
+		updateEventsBloomFpRate()
+		return false
+	}
+
+	found := lookup()
+	if !found {
+		eventsBloomFalsePositiveMeter.Mark(1)
+		updateEventsBloomFpRate()
+	}
+	return found
+}
+
+// addToEventsBloom adds stored event into the bloom filter.
+func (s *Store) addToEventsBloom(id hash.Event) {
+	if !s.isEventsBloomEnabled() {
+		return
+	}
+
+	s.bloom.mu.Lock()
+	defer s.bloom.mu.Unlock()
+
+	epoch := id.Epoch()
+	s.getEventsBloom(epoch).Add(id.Bytes())
+	if !s.bloom.dirty[epoch] {
+		// erase the saved filter until the changed one is saved, so the filter is
+		// rebuilt if DBs are flushed directly, without Store.Commit
+		if err := s.table.EventsBloom.Delete(epoch.Bytes()); err != nil {
+			s.Log.Crit("Failed to erase key-value", "err", err)
+		}
+		s.bloom.dirty[epoch] = true
+	}
+}
+
+// getEventsBloom returns filter of the epoch. The filter is loaded from DB,
+// or rebuilt from the Events table if it's missing. s.bloom.mu must be locked.
+func (s *Store) getEventsBloom(epoch idx.Epoch) *bloom.Filter {
+	if f := s.bloom.filters[epoch]; f != nil {
+		return f
+	}
+
+	var f *bloom.Filter
+	if b, err := s.table.EventsBloom.Get(epoch.Bytes()); err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	} else if b != nil {
+		f, err = bloom.FromBytes(b)
+		if err != nil {
+			s.Log.Warn("Bloom filter of events is corrupted", "epoch", epoch, "err", err)
+		}
+	}
+	if f == nil {
+		var count int
+		f, count = s.rebuildEventsBloom(epoch)
+		// don't write filters of the pruned and future epochs
+		s.bloom.dirty[epoch] = count != 0
+	}
+
+	s.bloom.filters[epoch] = f
+	s.evictEventsBloom()
+	return f
+}
+
+// rebuildEventsBloom makes filter of the epoch from the Events table.
+func (s *Store) rebuildEventsBloom(epoch idx.Epoch) (f *bloom.Filter, count int) {
+	f = bloom.New(s.cfg.EventsBloomSize, eventsBloomFpRate)
+
+	it := s.table.Events.NewIteratorWithPrefix(epoch.Bytes())
+	defer it.Release()
+	for it.Next() {
+		f.Add(it.Key())
+		count++
+	}
+	if count != 0 {
+		s.Log.Info("Rebuilt bloom filter of events", "epoch", epoch, "events", count)
+	}
+
+	return f, count
+}
+
+// evictEventsBloom saves and unloads filters of the oldest epochs. s.bloom.mu must be locked.
+func (s *Store) evictEventsBloom() {
+	if len(s.bloom.filters) <= eventsBloomEpochs {
+		return
+	}
+
+	epochs := make([]idx.Epoch, 0, len(s.bloom.filters))
+	for e := range s.bloom.filters {
+		epochs = append(epochs, e)
+	}
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i] < epochs[j]
+	})
+
+	for _, e := range epochs[:len(epochs)-eventsBloomEpochs] {
+		s.saveEventsBloom(e)
+		delete(s.bloom.filters, e)
+	}
+}
+
+// saveEventsBloom writes filter of the epoch if it's changed. s.bloom.mu must be locked.
+func (s *Store) saveEventsBloom(epoch idx.Epoch) {
+	if !s.bloom.dirty[epoch] {
+		return
+	}
+	if err := s.table.EventsBloom.Put(epoch.Bytes(), s.bloom.filters[epoch].Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+	delete(s.bloom.dirty, epoch)
+}
+
+// flushEventsBloom writes all the changed filters, so they are flushed together with the events.
+func (s *Store) flushEventsBloom() {
+	s.bloom.mu.Lock()
+	defer s.bloom.mu.Unlock()
+
+	for epoch := range s.bloom.dirty {
+		s.saveEventsBloom(epoch)
+	}
+}
+
+// dropEventsBloom deletes filter of the pruned epoch.
+func (s *Store) dropEventsBloom(epoch idx.Epoch) {
+	s.bloom.mu.Lock()
+	defer s.bloom.mu.Unlock()
+
+	delete(s.bloom.filters, epoch)
+	delete(s.bloom.dirty, epoch)
+	if err := s.table.EventsBloom.Delete(epoch.Bytes()); err != nil {
+		s.Log.Crit("Failed to erase key-value", "err", err)
+	}
+}
+
+func updateEventsBloomFpRate() {
+	negative := eventsBloomNegativeMeter.Count()
+	falsePositive := eventsBloomFalsePositiveMeter.Count()
+	if negative+falsePositive == 0 {
+		return
+	}
+	eventsBloomFpRateGauge.Update(float64(falsePositive) / float64(negative+falsePositive))
+}
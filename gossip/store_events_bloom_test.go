@@ -0,0 +1,73 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestStoreEventsBloom(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	dbs := flushable.NewSyncedPool(memorydb.NewProducer(""))
+	store := NewStore(dbs, LiteStoreConfig())
+
+	events := make([]*inter.Event, 10)
+	for i := range events {
+		e := fakeEvent()
+		e.Epoch = 1
+		events[i] = e
+		store.SetEvent(e)
+	}
+	unknown := fakeEvent()
+	unknown.Epoch = 1
+
+	check := func(store *Store) {
+		for _, e := range events {
+			assertar.True(store.HasEvent(e.Hash()))
+			assertar.Equal(e.Hash(), store.GetEvent(e.Hash()).Hash())
+		}
+		assertar.False(store.HasEvent(unknown.Hash()))
+		assertar.Nil(store.GetEvent(unknown.Hash()))
+		assertar.False(store.HasEvent(hash.ZeroEvent))
+	}
+	check(store)
+
+	// filter is persisted
+	assertar.NoError(store.Commit(nil, true))
+	b, err := store.table.EventsBloom.Get(events[0].Epoch.Bytes())
+	assertar.NoError(err)
+	assertar.NotNil(b)
+	check(NewStore(dbs, LiteStoreConfig()))
+
+	// filter isn't stale if DBs are flushed without Commit
+	e := fakeEvent()
+	e.Epoch = 1
+	store.SetEvent(e)
+	events = append(events, e)
+	assertar.NoError(dbs.Flush([]byte("direct")))
+	b, err = store.table.EventsBloom.Get(events[0].Epoch.Bytes())
+	assertar.NoError(err)
+	assertar.Nil(b)
+	check(NewStore(dbs, LiteStoreConfig()))
+	assertar.NoError(store.Commit(nil, true))
+	check(NewStore(dbs, LiteStoreConfig()))
+
+	// missing filter is rebuilt
+	assertar.NoError(store.table.EventsBloom.Delete(events[0].Epoch.Bytes()))
+	check(NewStore(dbs, LiteStoreConfig()))
+
+	// pruned filter is erased
+	store.PruneEpoch(1)
+	b, err = store.table.EventsBloom.Get(events[0].Epoch.Bytes())
+	assertar.NoError(err)
+	assertar.Nil(b)
+	assertar.False(store.HasEvent(events[0].Hash()))
+}
@@ -0,0 +1,50 @@
+package gossip
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+func forkEvidenceKey(epoch idx.Epoch, cheater idx.StakerID) []byte {
+	return append(epoch.Bytes(), cheater.Bytes()...)
+}
+
+// SetForkEvidence stores the evidence of the cheater's fork in the epoch.
+func (s *Store) SetForkEvidence(epoch idx.Epoch, cheater idx.StakerID, ev *inter.ForkEvidence) {
+	s.set(s.table.ForkEvidences, forkEvidenceKey(epoch, cheater), ev)
+}
+
+// GetForkEvidence returns the stored evidence of the cheater's fork in the epoch.
+func (s *Store) GetForkEvidence(epoch idx.Epoch, cheater idx.StakerID) *inter.ForkEvidence {
+	ev, _ := s.get(s.table.ForkEvidences, forkEvidenceKey(epoch, cheater), &inter.ForkEvidence{}).(*inter.ForkEvidence)
+	return ev
+}
+
+// HasForkEvidence returns true if the evidence of the cheater's fork in the epoch is stored.
+func (s *Store) HasForkEvidence(epoch idx.Epoch, cheater idx.StakerID) bool {
+	return s.has(s.table.ForkEvidences, forkEvidenceKey(epoch, cheater))
+}
+
+// GetForkEvidenceHashes returns hashes of the stored evidences of the epoch, ordered by cheaters.
+func (s *Store) GetForkEvidenceHashes(epoch idx.Epoch) []common.Hash {
+	hashes := []common.Hash{}
+
+	it := s.table.ForkEvidences.NewIteratorWithPrefix(epoch.Bytes())
+	defer it.Release()
+	for it.Next() {
+		ev := &inter.ForkEvidence{}
+		err := rlp.DecodeBytes(it.Value(), ev)
+		if err != nil {
+			s.Log.Crit("Failed to decode rlp", "err", err, "size", len(it.Value()))
+		}
+		hashes = append(hashes, ev.Hash())
+	}
+	if it.Error() != nil {
+		s.Log.Crit("Failed to iterate keys", "err", it.Error())
+	}
+
+	return hashes
+}
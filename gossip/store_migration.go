@@ -0,0 +1,27 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/utils/migration"
+)
+
+// Migrations returns migrations of the store data, in order of applying.
+// New migrations must be appended to the end only.
+func (s *Store) Migrations() *migration.Schema {
+	return &migration.Schema{
+		Name:    "gossip",
+		Version: s.table.Version,
+		IsEmpty: func() bool {
+			return s.GetBlock(0) == nil
+		},
+		Migrations: []migration.Migration{
+			{
+				// for compability with db before commit 591ede6
+				Name: "remove serverPool records from PackInfos",
+				Exec: func() error {
+					s.rmPrefix(s.table.PackInfos, "serverPool")
+					return nil
+				},
+			},
+		},
+	}
+}
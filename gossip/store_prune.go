@@ -0,0 +1,81 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+var (
+	lowestEpochKey     = []byte("e") // lowest epoch which events and packs are stored
+	lowestFullBlockKey = []byte("b") // lowest block which events list is stored
+)
+
+// GetLowestEpoch returns the lowest epoch which events and packs are stored.
+// Returns 0 if none of the epochs was pruned.
+func (s *Store) GetLowestEpoch() idx.Epoch {
+	b, err := s.table.Pruned.Get(lowestEpochKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if b == nil {
+		return 0
+	}
+	return idx.BytesToEpoch(b)
+}
+
+// IsEpochPruned returns true if events and packs of the epoch were deleted.
+func (s *Store) IsEpochPruned(epoch idx.Epoch) bool {
+	return epoch < s.GetLowestEpoch()
+}
+
+// PruneEpoch deletes events, packs and pack infos of the epoch.
+// Epoch must be the lowest stored one, because all the epochs before it become considered as pruned.
+func (s *Store) PruneEpoch(epoch idx.Epoch) {
+	prefix := string(epoch.Bytes())
+	s.rmPrefix(s.table.Events, prefix)
+	s.rmPrefix(s.table.EventLocalTimes, prefix)
+	s.rmPrefix(s.table.PackInfos, prefix)
+	s.rmPrefix(s.table.Packs, prefix)
+	if err := s.table.PacksNum.Delete(epoch.Bytes()); err != nil {
+		s.Log.Crit("Failed to erase key-value", "err", err)
+	}
+	s.dropEventsBloom(epoch)
+
+	if err := s.table.Pruned.Put(lowestEpochKey, (epoch + 1).Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+
+	// Clear LRU caches, it's cheaper than to remove the entries one by one.
+	if s.cache.Events != nil {
+		s.cache.Events.Purge()
+	}
+	if s.cache.PackInfos != nil {
+		s.cache.PackInfos.Purge()
+	}
+}
+
+// PruneBlocks drops events lists of the blocks decided before the epoch, keeping the blocks headers.
+func (s *Store) PruneBlocks(until idx.Epoch) {
+	n := idx.Block(0)
+	if b, err := s.table.Pruned.Get(lowestFullBlockKey); err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	} else if b != nil {
+		n = idx.BytesToBlock(b)
+	}
+
+	for ; ; n++ {
+		block := s.GetBlock(n)
+		if block == nil || block.Atropos.Epoch() >= until {
+			break
+		}
+
+		// cached block may be in use, so modify the copy
+		header := *block
+		header.Events = nil
+		header.SkippedTxs = nil
+		s.SetBlock(&header)
+	}
+
+	if err := s.table.Pruned.Put(lowestFullBlockKey, n.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
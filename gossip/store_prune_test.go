@@ -0,0 +1,101 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestStorePruneEpoch(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := cachedStore()
+
+	events := make(map[idx.Epoch]*inter.Event)
+	for epoch := idx.Epoch(1); epoch <= 3; epoch++ {
+		e := fakeEvent()
+		e.Epoch = epoch
+		e.Lamport = 1
+		events[epoch] = e
+
+		store.SetEvent(e)
+		store.SetEventReceivingTime(e.Hash(), e.ClaimedTime)
+		store.AddToPack(epoch, 1, e.Hash())
+		store.SetPackInfo(epoch, 1, PackInfo{Index: 1, Heads: hash.Events{e.Hash()}})
+		store.SetPacksNum(epoch, 2)
+	}
+	assertar.Equal(idx.Epoch(0), store.GetLowestEpoch())
+
+	store.PruneEpoch(0)
+	store.PruneEpoch(1)
+	assertar.Equal(idx.Epoch(2), store.GetLowestEpoch())
+	assertar.True(store.IsEpochPruned(1))
+	assertar.False(store.IsEpochPruned(2))
+
+	// pruned
+	e := events[1]
+	assertar.Nil(store.GetEvent(e.Hash()))
+	assertar.Equal(inter.Timestamp(0), store.GetEventReceivingTime(e.Hash()))
+	assertar.Nil(store.GetPack(1, 1))
+	assertar.Nil(store.GetPackInfo(1, 1))
+	_, ok := store.GetPacksNum(1)
+	assertar.False(ok)
+
+	// retained
+	for epoch := idx.Epoch(2); epoch <= 3; epoch++ {
+		e := events[epoch]
+		assertar.Equal(e.Hash(), store.GetEvent(e.Hash()).Hash())
+		assertar.Equal(e.ClaimedTime, store.GetEventReceivingTime(e.Hash()))
+		assertar.Equal(hash.Events{e.Hash()}, store.GetPack(epoch, 1))
+		assertar.Equal(hash.Events{e.Hash()}, store.GetPackInfo(epoch, 1).Heads)
+		num, ok := store.GetPacksNum(epoch)
+		assertar.True(ok)
+		assertar.Equal(idx.Pack(2), num)
+	}
+}
+
+func TestStorePruneBlocks(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	store := cachedStore()
+
+	atropos := func(epoch idx.Epoch, n idx.Block) hash.Event {
+		e := fakeEvent()
+		e.Epoch = epoch
+		e.Lamport = idx.Lamport(n)
+		return e.Hash()
+	}
+	epochs := []idx.Epoch{1, 1, 2, 3}
+	for i, epoch := range epochs {
+		n := idx.Block(i)
+		a := atropos(epoch, n)
+		block := inter.NewBlock(n, 0, a, hash.ZeroEvent, hash.Events{a})
+		block.SkippedTxs = []uint{0}
+		store.SetBlock(block)
+	}
+
+	store.PruneBlocks(2)
+	store.PruneBlocks(2)
+	for i, epoch := range epochs {
+		block := store.GetBlock(idx.Block(i))
+		assertar.Equal(idx.Block(i), block.Index)
+		if epoch < 2 {
+			assertar.Empty(block.Events)
+			assertar.Empty(block.SkippedTxs)
+		} else {
+			assertar.Equal(hash.Events{block.Atropos}, block.Events)
+			assertar.Equal([]uint{0}, block.SkippedTxs)
+		}
+	}
+
+	store.PruneBlocks(3)
+	assertar.Empty(store.GetBlock(2).Events)
+	assertar.Equal(1, len(store.GetBlock(3).Events))
+}
@@ -2,6 +2,7 @@ package integration
 
 import (
 	"crypto/ecdsa"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -11,45 +12,42 @@ import (
 
 	"github.com/Fantom-foundation/go-lachesis/app"
 	"github.com/Fantom-foundation/go-lachesis/gossip"
-	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
 	"github.com/Fantom-foundation/go-lachesis/poset"
 )
 
 // MakeEngine makes consensus engine from config.
 func MakeEngine(dataDir string, gossipCfg *gossip.Config) (*poset.Poset, *app.Store, *gossip.Store) {
-	dbs := flushable.NewSyncedPool(dbProducer(dataDir))
+	dbs := syncedPool(dataDir, gossipCfg.StoreConfig)
+	adb, gdb, cdb := makeStores(dbs, gossipCfg)
 
-	appStoreConfig := app.StoreConfig{
-		ReceiptsCacheSize:   gossipCfg.ReceiptsCacheSize,
-		DelegatorsCacheSize: gossipCfg.DelegatorsCacheSize,
-		StakersCacheSize:    gossipCfg.StakersCacheSize,
-	}
-	adb := app.NewStore(dbs, appStoreConfig)
-	gdb := gossip.NewStore(dbs, gossipCfg.StoreConfig)
-	cdb := poset.NewStore(dbs, poset.DefaultStoreConfig())
-
-	// write genesis
-
-	// TODO: replace first block with DB-migrations
-	firstBlock := gdb.GetBlock(0)
-	state, _, err := adb.ApplyGenesis(&gossipCfg.Net, firstBlock)
-	if err != nil {
-		utils.Fatalf("Failed to write App genesis state: %v", err)
+	// migrate data before genesis, so new stores are just marked with the last versions
+	var err error
+	if gossipCfg.ReadOnly {
+		err = checkMigrated(adb, gdb, cdb)
+	} else {
+		err = migrate(dbs, adb, gdb, cdb)
 	}
-
-	genesisAtropos, genesisState, isNew, err := gdb.ApplyGenesis(&gossipCfg.Net, state)
 	if err != nil {
-		utils.Fatalf("Failed to write Gossip genesis state: %v", err)
+		utils.Fatalf("Failed to migrate DB: %v", err)
 	}
 
-	err = cdb.ApplyGenesis(&gossipCfg.Net.Genesis, genesisAtropos, genesisState)
+	// write genesis
+	genesisAtropos, isNew, err := applyGenesis(adb, gdb, cdb, &gossipCfg.Net)
 	if err != nil {
-		utils.Fatalf("Failed to write Poset genesis state: %v", err)
+		utils.Fatalf("Failed to apply genesis: %v", err)
 	}
 
-	err = dbs.Flush(genesisAtropos.Bytes())
-	if err != nil {
-		utils.Fatalf("Failed to flush genesis state: %v", err)
+	if gossipCfg.ReadOnly {
+		if isNew {
+			utils.Fatalf("Datadir %s has no genesis state, it can't be opened in read-only mode", dataDir)
+		}
+	} else {
+		err = dbs.Flush(genesisAtropos.Bytes())
+		if err != nil {
+			utils.Fatalf("Failed to flush genesis state: %v", err)
+		}
 	}
 
 	if isNew {
@@ -59,11 +57,35 @@ func MakeEngine(dataDir string, gossipCfg *gossip.Config) (*poset.Poset, *app.St
 	}
 
 	// create consensus
+	if err := gossipCfg.Net.CheckElection(); err != nil {
+		utils.Fatalf("Invalid election config: %v", err)
+	}
 	engine := poset.New(gossipCfg.Net.Dag, cdb, gdb)
 
 	return engine, adb, gdb
 }
 
+// applyGenesis writes genesis state of the stores, if it isn't written yet.
+func applyGenesis(adb *app.Store, gdb *gossip.Store, cdb *poset.Store, net *lachesis.Config) (genesisAtropos hash.Event, isNew bool, err error) {
+	firstBlock := gdb.GetBlock(0)
+	state, _, err := adb.ApplyGenesis(net, firstBlock)
+	if err != nil {
+		return genesisAtropos, isNew, fmt.Errorf("failed to write App genesis state: %v", err)
+	}
+
+	genesisAtropos, genesisState, isNew, err := gdb.ApplyGenesis(net, state)
+	if err != nil {
+		return genesisAtropos, isNew, fmt.Errorf("failed to write Gossip genesis state: %v", err)
+	}
+
+	err = cdb.ApplyGenesis(&net.Genesis, genesisAtropos, genesisState)
+	if err != nil {
+		return genesisAtropos, isNew, fmt.Errorf("failed to write Poset genesis state: %v", err)
+	}
+
+	return genesisAtropos, isNew, nil
+}
+
 // SetAccountKey sets key into accounts manager and unlocks it with pswd.
 func SetAccountKey(
 	am *accounts.Manager, key *ecdsa.PrivateKey, pswd string,
@@ -1,15 +1,60 @@
 package integration
 
 import (
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip"
 	"github.com/Fantom-foundation/go-lachesis/kvdb"
-	"github.com/Fantom-foundation/go-lachesis/kvdb/leveldb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/dbengine"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/metered"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/readonly"
 )
 
-func dbProducer(dbdir string) kvdb.DbProducer {
+func dbProducer(dbdir string, cfg gossip.StoreConfig) kvdb.DbProducer {
+	producer := rawDbProducer(dbdir, cfg.DBEngine, cfg.ReadOnly)
+	if metrics.Enabled {
+		producer = metered.WrapProducer(producer, gossip.DbSchema())
+	}
+	if cfg.ReadOnly {
+		producer = readonly.WrapProducer(producer)
+	}
+	return producer
+}
+
+// syncedPool opens the pool of the node dbs, read-only if configured.
+func syncedPool(dbdir string, cfg gossip.StoreConfig) *flushable.SyncedPool {
+	producer := dbProducer(dbdir, cfg)
+	if cfg.ReadOnly {
+		return flushable.NewReadOnlySyncedPool(producer)
+	}
+	return flushable.NewSyncedPool(producer)
+}
+
+func rawDbProducer(dbdir, engine string, readonly bool) kvdb.DbProducer {
 	if dbdir == "inmemory" || dbdir == "" {
 		return memorydb.NewProducer("")
 	}
 
-	return leveldb.NewProducer(dbdir)
+	if engine == "" {
+		engine = dbengine.LevelDb
+	}
+	newProducer := dbengine.NewProducer
+	if readonly {
+		newProducer = dbengine.NewReadOnlyProducer
+	}
+	producer, err := newProducer(engine, dbdir)
+	if err != nil {
+		utils.Fatalf("Failed to open databases: %v", err)
+	}
+	// datadir must not be shared by engines, because each of them would see only its own dbs
+	for _, another := range dbengine.Detect(dbdir) {
+		if another != engine {
+			utils.Fatalf("Datadir %s contains %s databases, but %s engine is selected", dbdir, another, engine)
+		}
+	}
+
+	return producer
 }
@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"fmt"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+	"github.com/Fantom-foundation/go-lachesis/utils/migration"
+)
+
+func makeStores(dbs *flushable.SyncedPool, gossipCfg *gossip.Config) (*app.Store, *gossip.Store, *poset.Store) {
+	appStoreConfig := app.StoreConfig{
+		GCMode:              gossipCfg.GCMode,
+		ReceiptsCacheSize:   gossipCfg.ReceiptsCacheSize,
+		DelegatorsCacheSize: gossipCfg.DelegatorsCacheSize,
+		StakersCacheSize:    gossipCfg.StakersCacheSize,
+	}
+	adb := app.NewStore(dbs, appStoreConfig)
+	gdb := gossip.NewStore(dbs, gossipCfg.StoreConfig)
+	cdb := makePosetStore(dbs, gossipCfg, adb, gdb)
+
+	return adb, gdb, cdb
+}
+
+// makePosetStore makes the poset store, which rolls back the app and gossip stores along with its checkpoints.
+func makePosetStore(dbs *flushable.SyncedPool, gossipCfg *gossip.Config, adb *app.Store, gdb *gossip.Store) *poset.Store {
+	cdb := poset.NewStore(dbs, posetStoreConfig(gossipCfg))
+
+	checkpointed := flushable.UndoScope{}
+	adb.AddCheckpointedTables(checkpointed)
+	gdb.AddCheckpointedTables(checkpointed)
+	cdb.SetCheckpointedTables(checkpointed)
+
+	return cdb
+}
+
+func posetStoreConfig(gossipCfg *gossip.Config) poset.StoreConfig {
+	cfg := poset.DefaultStoreConfig()
+	cfg.ElectionTraces = gossipCfg.ElectionTraces
+	cfg.CheckpointHistory = gossipCfg.CheckpointHistory
+	return cfg
+}
+
+// migrations of the stores, in order of applying.
+func migrations(adb *app.Store, gdb *gossip.Store, cdb *poset.Store) []*migration.Schema {
+	return []*migration.Schema{
+		adb.Migrations(),
+		gdb.Migrations(),
+		cdb.Migrations(),
+	}
+}
+
+// PendingMigrations returns names of not applied migrations, by the store names.
+// Databases are opened in read-only mode.
+func PendingMigrations(dataDir string, gossipCfg *gossip.Config) (map[string][]string, error) {
+	storeCfg := gossipCfg.StoreConfig
+	storeCfg.ReadOnly = true
+	dbs := syncedPool(dataDir, storeCfg)
+	defer dbs.CloseAll()
+	adb, gdb, cdb := makeStores(dbs, gossipCfg)
+
+	pending := make(map[string][]string)
+	for _, m := range migrations(adb, gdb, cdb) {
+		mm, err := m.Pending()
+		if err != nil {
+			return nil, err
+		}
+		for _, one := range mm {
+			pending[m.Name] = append(pending[m.Name], one.Name)
+		}
+	}
+	return pending, nil
+}
+
+// Migrate applies pending migrations of the stores.
+func Migrate(dataDir string, gossipCfg *gossip.Config) error {
+	dbs := syncedPool(dataDir, gossipCfg.StoreConfig)
+	adb, gdb, cdb := makeStores(dbs, gossipCfg)
+
+	err := migrate(dbs, adb, gdb, cdb)
+	if closeErr := dbs.CloseAll(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func migrate(dbs *flushable.SyncedPool, adb *app.Store, gdb *gossip.Store, cdb *poset.Store) error {
+	for _, m := range migrations(adb, gdb, cdb) {
+		err := m.Exec(dbs.Flush)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkMigrated returns error if any store has pending migrations.
+func checkMigrated(adb *app.Store, gdb *gossip.Store, cdb *poset.Store) error {
+	for _, m := range migrations(adb, gdb, cdb) {
+		pending, err := m.Pending()
+		if err != nil {
+			return err
+		}
+		if len(pending) != 0 {
+			return fmt.Errorf("%s: %d migrations are pending, apply them with 'lachesis db migrate'", m.Name, len(pending))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package integration
+
+import (
+	"strings"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+)
+
+// ReindexConsensus drops the consensus state (poset store and vector clock index),
+// and rebuilds it by replaying the stored events. It returns the last replayed block,
+// and the first divergence of the decided blocks from the stored ones (see ConsensusReplay.Run).
+// Blocks aren't re-executed, so their state roots aren't verified.
+func ReindexConsensus(dataDir string, gossipCfg *gossip.Config) (idx.Block, error) {
+	err := gossipCfg.Net.CheckElection()
+	if err != nil {
+		return 0, err
+	}
+
+	producer := dbProducer(dataDir, gossipCfg.StoreConfig)
+	dbs := flushable.NewSyncedPool(producer)
+	adb, gdb, cdb := makeStores(dbs, gossipCfg)
+
+	defer dbs.CloseAll()
+
+	// check everything before the consensus state is dropped
+	err = checkMigrated(adb, gdb, cdb)
+	if err != nil {
+		return 0, err
+	}
+	replay := gossip.NewConsensusReplay(gdb, adb, &gossipCfg.Net)
+	err = replay.CheckPruned()
+	if err != nil {
+		return 0, err
+	}
+
+	// vector clock index is stored in the poset epoch DBs
+	for _, name := range producer.Names() {
+		if !strings.HasPrefix(name, "poset-") {
+			continue
+		}
+		db := dbs.GetDb(name)
+		err = db.Close()
+		if err != nil {
+			return 0, err
+		}
+		db.Drop()
+	}
+	err = dbs.Flush([]byte("reindex-consensus"))
+	if err != nil {
+		return 0, err
+	}
+
+	cdb = makePosetStore(dbs, gossipCfg, adb, gdb)
+	err = cdb.Migrations().Exec(dbs.Flush)
+	if err != nil {
+		return 0, err
+	}
+	_, _, err = applyGenesis(adb, gdb, cdb, &gossipCfg.Net)
+	if err != nil {
+		return 0, err
+	}
+
+	engine := poset.New(gossipCfg.Net.Dag, cdb, replay)
+	err = replay.Run(engine)
+
+	return replay.LastBlock(), err
+}
@@ -0,0 +1,56 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/go-lachesis/app"
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/poset"
+)
+
+// Rollback rewinds the poset, gossip and app stores to the checkpoint of the block, within the current epoch,
+// and re-executes the subsequent blocks from the stored events. It returns the last re-executed block.
+// EVM state of the block must be kept, so it requires archive GC mode.
+func Rollback(dataDir string, gossipCfg *gossip.Config, to idx.Block) (idx.Block, error) {
+	if gossipCfg.GCMode == app.GCModeFull {
+		return 0, errors.New("EVM states of the recent blocks aren't kept in full GC mode, use gcmode=archive")
+	}
+	err := gossipCfg.Net.CheckElection()
+	if err != nil {
+		return 0, err
+	}
+
+	dbs := syncedPool(dataDir, gossipCfg.StoreConfig)
+	defer dbs.CloseAll()
+	adb, gdb, cdb := makeStores(dbs, gossipCfg)
+
+	err = checkMigrated(adb, gdb, cdb)
+	if err != nil {
+		return 0, err
+	}
+
+	err = cdb.Rollback(to)
+	if err != nil {
+		return 0, err
+	}
+	block := gdb.GetBlock(to)
+	if block == nil {
+		return 0, fmt.Errorf("block %d isn't found", to)
+	}
+	_, err = adb.OpenStateDB(block.Root)
+	if err != nil {
+		return 0, err
+	}
+	err = dbs.Flush([]byte("rollback"))
+	if err != nil {
+		return 0, err
+	}
+
+	// stores caches may contain the rolled back data
+	adb, gdb, cdb = makeStores(dbs, gossipCfg)
+	engine := poset.New(gossipCfg.Net.Dag, cdb, gdb)
+
+	return gossip.ReapplyBlocks(gossipCfg, gdb, adb, engine)
+}
@@ -0,0 +1,155 @@
+package simulator
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+)
+
+// linkQueueSize is a number of messages in flight, writer is blocked above it.
+const linkQueueSize = 1024
+
+var (
+	errNoDial     = errors.New("connections are established by the simulator")
+	errLinkClosed = errors.New("link is closed")
+)
+
+// noDialer prevents p2p servers from dialing on their own.
+type noDialer struct{}
+
+func (noDialer) Dial(*enode.Node) (net.Conn, error) {
+	return nil, errNoDial
+}
+
+type (
+	// link is a connection between two nodes, which is maintained by the simulator.
+	link struct {
+		a, b int
+		cfg  LinkConfig
+
+		conn net.Conn // nil if disconnected
+		rand *rand.Rand
+		mu   sync.Mutex
+	}
+
+	// linkRW delays and drops messages written to the protocol connection.
+	linkRW struct {
+		p2p.MsgReadWriter
+		link *link
+
+		queue chan delayedMsg
+		last  time.Time // delivery time of the last message, to keep the order
+		done  chan struct{}
+		mu    sync.Mutex
+	}
+
+	delayedMsg struct {
+		code    uint64
+		payload []byte
+		at      time.Time
+	}
+)
+
+func newLink(a, b int, cfg LinkConfig, seed int64) *link {
+	return &link{
+		a:    a,
+		b:    b,
+		cfg:  cfg,
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// delay returns random delay of the next message.
+func (l *link) delay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d := time.Duration(l.cfg.Latency)
+	if l.cfg.Jitter > 0 {
+		d += time.Duration(l.rand.Int63n(int64(l.cfg.Jitter)))
+	}
+	return d
+}
+
+// lost returns true if the next message is dropped.
+func (l *link) lost() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.cfg.Loss > 0 && l.rand.Float64() < l.cfg.Loss
+}
+
+// wrap applies the link conditions to the protocol connection until it's closed.
+func (l *link) wrap(rw p2p.MsgReadWriter, closed <-chan struct{}) p2p.MsgReadWriter {
+	if l.cfg.Latency == 0 && l.cfg.Jitter == 0 && l.cfg.Loss == 0 {
+		return rw
+	}
+	lrw := &linkRW{
+		MsgReadWriter: rw,
+		link:          l,
+		queue:         make(chan delayedMsg, linkQueueSize),
+		done:          make(chan struct{}),
+	}
+	go lrw.deliver(closed)
+	return lrw
+}
+
+// WriteMsg queues the message for the delivery, or drops it.
+func (rw *linkRW) WriteMsg(msg p2p.Msg) error {
+	payload, err := ioutil.ReadAll(msg.Payload)
+	if err != nil {
+		return err
+	}
+	if msg.Code != gossip.EthStatusMsg && rw.link.lost() {
+		return nil
+	}
+
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	at := time.Now().Add(rw.link.delay())
+	if at.Before(rw.last) {
+		at = rw.last
+	}
+	rw.last = at
+
+	select {
+	case rw.queue <- delayedMsg{code: msg.Code, payload: payload, at: at}:
+		return nil
+	case <-rw.done:
+		return errLinkClosed
+	}
+}
+
+func (rw *linkRW) deliver(closed <-chan struct{}) {
+	defer close(rw.done)
+	for {
+		select {
+		case msg := <-rw.queue:
+			select {
+			case <-time.After(time.Until(msg.at)):
+			case <-closed:
+				return
+			}
+			err := rw.MsgReadWriter.WriteMsg(p2p.Msg{
+				Code:    msg.code,
+				Size:    uint32(len(msg.payload)),
+				Payload: bytes.NewReader(msg.payload),
+			})
+			if err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
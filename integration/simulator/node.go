@@ -0,0 +1,169 @@
+package simulator
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	notify "github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/Fantom-foundation/go-lachesis/evmcore"
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/integration"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+// simNode is a validator node with in-memory DBs, which is connected to the others by the simulator.
+type simNode struct {
+	index int
+	key   *ecdsa.PrivateKey
+	id    enode.ID
+
+	svc   *gossip.Service
+	store *gossip.Store
+	srv   *p2p.Server
+	am    *accounts.Manager
+
+	blocks chan evmcore.ChainHeadNotify
+	sub    notify.Subscription
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	height   idx.Block
+	finality []time.Duration // time from event creation until it's confirmed on this node
+
+	logger.Instance
+}
+
+func (s *Simulator) newNode(i int) (*simNode, error) {
+	addr := s.net.Genesis.Alloc.Validators.Addresses()[i]
+	key := s.net.Genesis.Alloc.Accounts[addr].PrivateKey
+
+	gossipCfg := gossip.DefaultConfig(s.net)
+	gossipCfg.Emitter = s.scenario.Emitter
+	gossipCfg.Emitter.Validator = addr
+	gossipCfg.TxPool.Journal = "" // no datadir
+
+	engine, adb, gdb := integration.MakeEngine("inmemory", &gossipCfg)
+
+	ks := keystore.NewKeyStore(filepath.Join(s.dir, fmt.Sprintf("keystore-%d", i)), keystore.LightScryptN, keystore.LightScryptP)
+	am := accounts.NewManager(&accounts.Config{InsecureUnlockAllowed: true}, ks)
+	integration.SetAccountKey(am, key, "fakepassword")
+
+	svc, err := gossip.NewService(&node.ServiceContext{AccountManager: am}, &gossipCfg, gdb, engine, adb)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &simNode{
+		index: i,
+		key:   key,
+		id:    enode.PubkeyToIDV4(&key.PublicKey),
+		svc:   svc,
+		store: gdb,
+		am:    am,
+
+		blocks: make(chan evmcore.ChainHeadNotify, 256),
+		done:   make(chan struct{}),
+
+		Instance: logger.MakeInstance(),
+	}
+	name := fmt.Sprintf("Node-%d", i)
+	n.SetName(name)
+
+	n.srv = &p2p.Server{Config: p2p.Config{
+		PrivateKey:  key,
+		Name:        name,
+		MaxPeers:    s.scenario.Nodes,
+		NoDiscovery: true,
+		NoDial:      true,
+		Dialer:      noDialer{},
+		Protocols:   s.linkProtocols(i, svc.Protocols()),
+	}}
+
+	return n, nil
+}
+
+func (n *simNode) start() error {
+	if err := n.srv.Start(); err != nil {
+		return err
+	}
+	n.sub = n.svc.EthAPI.SubscribeNewBlockEvent(n.blocks)
+	n.wg.Add(1)
+	go n.trackBlocks()
+
+	return n.svc.Start(n.srv)
+}
+
+func (n *simNode) stop() {
+	if err := n.svc.Stop(); err != nil {
+		n.Log.Error("Failed to stop service", "err", err)
+	}
+	n.srv.Stop()
+	n.sub.Unsubscribe()
+	close(n.done)
+	n.wg.Wait()
+	_ = n.am.Close()
+}
+
+// trackBlocks measures the finality time of the events, which are confirmed by the decided blocks.
+// Note that only the events with transactions are included into blocks,
+// so the confirmed events are found as the Atropos ancestors.
+func (n *simNode) trackBlocks() {
+	defer n.wg.Done()
+
+	var (
+		epoch     idx.Epoch
+		confirmed = hash.EventsSet{}
+	)
+	for {
+		select {
+		case head := <-n.blocks:
+			now := inter.Timestamp(time.Now().UnixNano())
+			num := idx.Block(head.Block.Number.Uint64())
+			atropos := hash.Event(head.Block.Hash)
+			if atropos.Epoch() != epoch {
+				// parents are always of the same epoch
+				epoch = atropos.Epoch()
+				confirmed = hash.EventsSet{}
+			}
+
+			var finality []time.Duration
+			stack := hash.Events{atropos}
+			for len(stack) > 0 {
+				id := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if confirmed.Contains(id) {
+					continue
+				}
+				e := n.store.GetEvent(id)
+				if e == nil {
+					continue
+				}
+				confirmed.Add(id)
+				finality = append(finality, time.Duration(now-e.ClaimedTime))
+				stack = append(stack, e.Parents...)
+			}
+
+			n.mu.Lock()
+			if num > n.height {
+				n.height = num
+			}
+			n.finality = append(n.finality, finality...)
+			n.mu.Unlock()
+		case <-n.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package simulator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+type (
+	// Report of the simulation.
+	Report struct {
+		Nodes    int           `json:"nodes"`
+		Duration time.Duration `json:"duration"`
+
+		Blocks          idx.Block   `json:"blocks"`          // decided by all the nodes
+		NodeBlocks      []idx.Block `json:"nodeBlocks"`      // decided by each node
+		Epoch           idx.Epoch   `json:"epoch"`           // of the last block decided by all the nodes
+		BlocksPerSecond float64     `json:"blocksPerSecond"` // of the blocks decided by all the nodes
+
+		Finality   Finality   `json:"finality"`
+		Divergence Divergence `json:"divergence"`
+	}
+
+	// Finality is a distribution of time from event creation until it's confirmed by a block on a node.
+	Finality struct {
+		Events int           `json:"events"` // measured on all the nodes
+		Mean   time.Duration `json:"mean"`
+		Median time.Duration `json:"median"`
+		P95    time.Duration `json:"p95"`
+		Max    time.Duration `json:"max"`
+	}
+
+	// Divergence of the nodes.
+	Divergence struct {
+		Blocks     int       `json:"blocks"`     // with different Atropos on different nodes, must be 0
+		FirstBlock idx.Block `json:"firstBlock"` // first of them, 0 if none
+		Lag        idx.Block `json:"lag"`        // difference between the most and the least decided blocks of the nodes
+	}
+)
+
+// Report collects the measurements of the nodes.
+func (s *Simulator) Report() *Report {
+	end := s.stopped
+	if end.IsZero() {
+		end = time.Now()
+	}
+	r := &Report{
+		Nodes:      len(s.nodes),
+		Duration:   end.Sub(s.started),
+		NodeBlocks: make([]idx.Block, len(s.nodes)),
+	}
+
+	var (
+		finality []time.Duration
+		maxBlock idx.Block
+	)
+	for i, n := range s.nodes {
+		n.mu.Lock()
+		r.NodeBlocks[i] = n.height
+		finality = append(finality, n.finality...)
+		n.mu.Unlock()
+
+		if i == 0 || n.height < r.Blocks {
+			r.Blocks = n.height
+		}
+		if n.height > maxBlock {
+			maxBlock = n.height
+		}
+	}
+	r.Divergence.Lag = maxBlock - r.Blocks
+	if r.Duration > 0 {
+		r.BlocksPerSecond = float64(r.Blocks) / r.Duration.Seconds()
+	}
+
+	for num := idx.Block(1); num <= maxBlock; num++ {
+		var atropos hash.Event
+		for _, n := range s.nodes {
+			block := n.store.GetBlock(num)
+			if block == nil {
+				continue
+			}
+			if atropos.IsZero() {
+				atropos = block.Atropos
+			} else if atropos != block.Atropos {
+				r.Divergence.Blocks++
+				if r.Divergence.FirstBlock == 0 {
+					r.Divergence.FirstBlock = num
+				}
+				break
+			}
+		}
+		if num == r.Blocks {
+			r.Epoch = atropos.Epoch()
+		}
+	}
+
+	r.Finality = finalityOf(finality)
+	return r
+}
+
+func finalityOf(samples []time.Duration) Finality {
+	f := Finality{
+		Events: len(samples),
+	}
+	if len(samples) == 0 {
+		return f
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i] < samples[j]
+	})
+
+	var sum time.Duration
+	for _, d := range samples {
+		sum += d
+	}
+	f.Mean = sum / time.Duration(len(samples))
+	f.Median = samples[len(samples)/2]
+	f.P95 = samples[len(samples)*95/100]
+	f.Max = samples[len(samples)-1]
+	return f
+}
+
+// String returns the report as a human-readable text.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "nodes:             %d\n", r.Nodes)
+	fmt.Fprintf(&b, "duration:          %s\n", r.Duration.Round(time.Millisecond))
+	fmt.Fprintf(&b, "blocks:            %d, by nodes %v\n", r.Blocks, r.NodeBlocks)
+	fmt.Fprintf(&b, "epoch:             %d\n", r.Epoch)
+	fmt.Fprintf(&b, "blocks per second: %.3f\n", r.BlocksPerSecond)
+	fmt.Fprintf(&b, "time-to-finality:  mean %s, median %s, p95 %s, max %s (%d events)\n",
+		r.Finality.Mean.Round(time.Millisecond), r.Finality.Median.Round(time.Millisecond),
+		r.Finality.P95.Round(time.Millisecond), r.Finality.Max.Round(time.Millisecond), r.Finality.Events)
+	fmt.Fprintf(&b, "divergence:        %d blocks (first %d), lag %d blocks\n",
+		r.Divergence.Blocks, r.Divergence.FirstBlock, r.Divergence.Lag)
+	return b.String()
+}
@@ -0,0 +1,190 @@
+package simulator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/Fantom-foundation/go-lachesis/gossip"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+)
+
+type (
+	// Scenario of the simulation.
+	// Dag and Emitter are decoded over the fakenet defaults, so only the changed fields may be specified.
+	Scenario struct {
+		Nodes    int      `json:"nodes"`    // validators with equal stakes, each node is a validator
+		Duration Duration `json:"duration"` // of the simulation
+		Seed     int64    `json:"seed"`     // of the links latency and loss
+
+		Dag     lachesis.DagConfig   `json:"dag"`
+		Emitter gossip.EmitterConfig `json:"emitter"`
+
+		Links      LinkConfig     `json:"links"`      // conditions of every link
+		LinksOf    []LinkOverride `json:"linksOf"`    // conditions of the specific links
+		Partitions []Partition    `json:"partitions"` // links cuts
+	}
+
+	// LinkConfig describes conditions of the link between two nodes, the same in both directions.
+	LinkConfig struct {
+		Latency Duration `json:"latency"`
+		Jitter  Duration `json:"jitter"` // random addition to the latency, messages order is kept
+		Loss    float64  `json:"loss"`   // probability of a message to be dropped, handshakes are never dropped
+	}
+
+	// LinkOverride replaces conditions of the link between nodes A and B.
+	LinkOverride struct {
+		A    int        `json:"a"`
+		B    int        `json:"b"`
+		Link LinkConfig `json:"link"`
+	}
+
+	// Partition cuts the links between the nodes of different groups from Start until End.
+	// The nodes which aren't listed in any group keep all their links.
+	Partition struct {
+		Start  Duration `json:"start"`
+		End    Duration `json:"end"`
+		Groups [][]int  `json:"groups"`
+	}
+
+	// Duration is time.Duration, which is decoded from JSON string (e.g. "1.5s") or number of nanoseconds.
+	Duration time.Duration
+)
+
+// DefaultScenario returns the scenario of 5 nodes connected without delays.
+func DefaultScenario() *Scenario {
+	emitter := gossip.FakeEmitterConfig()
+	emitter.EmitIntervals.Max = time.Second
+	emitter.EmitIntervals.SelfForkProtection = 0 // nodes don't restart
+
+	return &Scenario{
+		Nodes:    5,
+		Duration: Duration(time.Minute),
+		Seed:     1,
+		Dag:      lachesis.FakeNetDagConfig(),
+		Emitter:  emitter,
+	}
+}
+
+// LoadScenario reads the JSON scenario file.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sc := DefaultScenario()
+	err = json.Unmarshal(data, sc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode scenario %s: %v", path, err)
+	}
+	return sc, sc.Validate()
+}
+
+// Validate checks that the scenario is consistent.
+func (sc *Scenario) Validate() error {
+	if sc.Nodes < 1 {
+		return errors.New("at least 1 node is required")
+	}
+	if sc.Duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+	if err := sc.Links.validate(); err != nil {
+		return err
+	}
+	for _, o := range sc.LinksOf {
+		if !sc.isNode(o.A) || !sc.isNode(o.B) || o.A == o.B {
+			return fmt.Errorf("link %d-%d doesn't exist", o.A, o.B)
+		}
+		if err := o.Link.validate(); err != nil {
+			return err
+		}
+	}
+	for i, p := range sc.Partitions {
+		if p.Start < 0 || p.End <= p.Start {
+			return fmt.Errorf("partition %d has wrong period", i)
+		}
+		for _, group := range p.Groups {
+			for _, n := range group {
+				if !sc.isNode(n) {
+					return fmt.Errorf("partition %d has unknown node %d", i, n)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (sc *Scenario) isNode(n int) bool {
+	return n >= 0 && n < sc.Nodes
+}
+
+// link returns conditions of the link between nodes a and b.
+func (sc *Scenario) link(a, b int) LinkConfig {
+	for _, o := range sc.LinksOf {
+		if (o.A == a && o.B == b) || (o.A == b && o.B == a) {
+			return o.Link
+		}
+	}
+	return sc.Links
+}
+
+// isCut returns true if the link between nodes a and b is cut at the moment since the start.
+func (sc *Scenario) isCut(a, b int, since time.Duration) bool {
+	for _, p := range sc.Partitions {
+		if since < time.Duration(p.Start) || since >= time.Duration(p.End) {
+			continue
+		}
+		groupA, groupB := p.group(a), p.group(b)
+		if groupA >= 0 && groupB >= 0 && groupA != groupB {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Partition) group(n int) int {
+	for i, group := range p.Groups {
+		for _, m := range group {
+			if m == n {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func (c *LinkConfig) validate() error {
+	if c.Latency < 0 || c.Jitter < 0 {
+		return errors.New("link latency must be non-negative")
+	}
+	if c.Loss < 0 || c.Loss >= 1 {
+		return errors.New("link loss must be in range [0, 1)")
+	}
+	return nil
+}
+
+// MarshalJSON encodes duration as string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes duration from string or number of nanoseconds.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		var ns int64
+		if err := json.Unmarshal(b, &ns); err != nil {
+			return err
+		}
+		*d = Duration(ns)
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
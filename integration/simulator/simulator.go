@@ -0,0 +1,221 @@
+// Package simulator runs many validator nodes in-process, connected by in-memory p2p pipes
+// with configurable latency, loss and partitions, to evaluate consensus and emitter configs.
+package simulator
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+// linksCheckPeriod is a period of connecting the nodes and cutting the links by partitions.
+const linksCheckPeriod = 100 * time.Millisecond
+
+var errUnknownPeer = errors.New("peer isn't a simulated node")
+
+// Simulator of the nodes network.
+type Simulator struct {
+	scenario *Scenario
+	net      lachesis.Config
+	dir      string // for the keystores
+
+	nodes []*simNode
+	links map[[2]int]*link
+	byID  map[enode.ID]int
+
+	started time.Time
+	stopped time.Time
+	done    chan struct{}
+	wg      sync.WaitGroup
+
+	logger.Instance
+}
+
+// New creates the nodes of the scenario.
+func New(sc *Scenario) (*Simulator, error) {
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+
+	net := lachesis.FakeNetConfig(genesis.FakeValidators(sc.Nodes, big.NewInt(0), pos.StakeToBalance(1)))
+	net.Dag = sc.Dag
+	if err := net.CheckElection(); err != nil {
+		return nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "lachesis-sim")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Simulator{
+		scenario: sc,
+		net:      net,
+		dir:      dir,
+		links:    make(map[[2]int]*link),
+		byID:     make(map[enode.ID]int, sc.Nodes),
+		done:     make(chan struct{}),
+
+		Instance: logger.MakeInstance(),
+	}
+
+	for a := 0; a < sc.Nodes; a++ {
+		for b := a + 1; b < sc.Nodes; b++ {
+			s.links[[2]int{a, b}] = newLink(a, b, sc.link(a, b), sc.Seed+int64(a*sc.Nodes+b))
+		}
+	}
+
+	for i := 0; i < sc.Nodes; i++ {
+		n, err := s.newNode(i)
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return nil, err
+		}
+		s.nodes = append(s.nodes, n)
+		s.byID[n.id] = i
+	}
+
+	return s, nil
+}
+
+// Run simulates the scenario and returns the report.
+func Run(sc *Scenario) (*Report, error) {
+	s, err := New(sc)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Start(); err != nil {
+		s.Stop()
+		return nil, err
+	}
+	time.Sleep(time.Duration(sc.Duration))
+	s.Stop()
+
+	return s.Report(), nil
+}
+
+// Start starts the nodes and connects them.
+func (s *Simulator) Start() error {
+	for _, n := range s.nodes {
+		if err := n.start(); err != nil {
+			return err
+		}
+	}
+	s.started = time.Now()
+
+	for _, l := range s.links {
+		s.wg.Add(1)
+		go s.maintainLink(l)
+	}
+	return nil
+}
+
+// Stop disconnects and stops the nodes.
+func (s *Simulator) Stop() {
+	s.stopped = time.Now()
+	close(s.done)
+	s.wg.Wait()
+
+	for _, n := range s.nodes {
+		if n.sub != nil {
+			n.stop()
+		}
+	}
+	_ = os.RemoveAll(s.dir)
+}
+
+// linkProtocols applies the links conditions to the node's protocols.
+func (s *Simulator) linkProtocols(self int, protos []p2p.Protocol) []p2p.Protocol {
+	res := make([]p2p.Protocol, len(protos))
+	for i, proto := range protos {
+		run := proto.Run
+		res[i] = proto
+		res[i].Run = func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+			peer, ok := s.byID[p.ID()]
+			if !ok {
+				return errUnknownPeer
+			}
+			closed := make(chan struct{})
+			defer close(closed)
+
+			return run(p, s.link(self, peer).wrap(rw, closed))
+		}
+	}
+	return res
+}
+
+func (s *Simulator) link(a, b int) *link {
+	if a > b {
+		a, b = b, a
+	}
+	return s.links[[2]int{a, b}]
+}
+
+// maintainLink connects the nodes of the link, and cuts it during the partitions.
+func (s *Simulator) maintainLink(l *link) {
+	defer s.wg.Done()
+	defer l.disconnect()
+
+	a, b := s.nodes[l.a], s.nodes[l.b]
+	ticker := time.NewTicker(linksCheckPeriod)
+	defer ticker.Stop()
+	for {
+		cut := s.scenario.isCut(l.a, l.b, time.Since(s.started))
+		switch {
+		case cut && l.conn != nil:
+			s.Log.Debug("Cut link", "a", l.a, "b", l.b)
+			l.disconnect()
+		case !cut && l.conn != nil && !isConnected(a.srv, b.id):
+			// dropped by the peers, reconnect on next tick
+			l.disconnect()
+		case !cut && l.conn == nil:
+			l.connect(a.srv, b.srv)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (l *link) connect(a, b *p2p.Server) {
+	fdA, fdB := net.Pipe()
+	go func() {
+		_ = b.SetupConn(fdB, 0, nil)
+	}()
+	if err := a.SetupConn(fdA, 0, b.Self()); err != nil {
+		_ = fdA.Close()
+		return
+	}
+	l.conn = fdA
+}
+
+func (l *link) disconnect() {
+	if l.conn != nil {
+		_ = l.conn.Close()
+		l.conn = nil
+	}
+}
+
+func isConnected(srv *p2p.Server, id enode.ID) bool {
+	for _, p := range srv.Peers() {
+		if p.ID() == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScenario(t *testing.T) {
+	assertar := assert.New(t)
+
+	sc, err := LoadScenario("testdata/partition.json")
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.Equal(4, sc.Nodes)
+	assertar.Equal(Duration(15*time.Second), sc.Duration)
+	assertar.Equal(200*time.Millisecond, sc.Emitter.EmitIntervals.Max)
+	assertar.Equal(DefaultScenario().Emitter.EmitIntervals.Min, sc.Emitter.EmitIntervals.Min, "default is kept")
+	assertar.Equal(Duration(100*time.Millisecond), sc.link(3, 0).Latency)
+	assertar.Equal(Duration(20*time.Millisecond), sc.link(1, 2).Latency)
+
+	assertar.False(sc.isCut(0, 2, 3*time.Second))
+	assertar.True(sc.isCut(0, 2, 5*time.Second))
+	assertar.False(sc.isCut(0, 1, 5*time.Second))
+	assertar.False(sc.isCut(0, 2, 7*time.Second))
+
+	sc.Partitions[0].Groups[0][0] = 4
+	assertar.Error(sc.Validate())
+}
+
+func TestSimulation(t *testing.T) {
+	assertar := assert.New(t)
+
+	sc, err := LoadScenario("testdata/partition.json")
+	if !assertar.NoError(err) {
+		return
+	}
+	if testing.Short() {
+		sc.Partitions = nil
+		sc.Duration = Duration(5 * time.Second)
+	}
+
+	r, err := Run(sc)
+	if !assertar.NoError(err) {
+		return
+	}
+	t.Log("\n" + r.String())
+
+	assertar.Equal(4, r.Nodes)
+	assertar.NotZero(r.Blocks)
+	assertar.NotZero(r.Finality.Events)
+	assertar.Zero(r.Divergence.Blocks)
+	if !testing.Short() {
+		assertar.True(r.Epoch > 1, "multiple epochs")
+	}
+}
@@ -60,12 +60,19 @@ func ASCIIschemeForEach(
 				refs[col] = 1
 				nLinks = append(nLinks, refs)
 			case "║╚", "╚": // start new link array with prev
-				refs := make([]int, col+1)
-				if ref, ok := prevFarRefs[col]; ok {
-					refs[col] = ref
-				} else {
-					refs[col] = 2
+				ref, ok := prevFarRefs[col]
+				if !ok {
+					ref = 2
+				}
+				if symbol == "╚" && len(nLinks) > len(nNames) {
+					// self-ref of fork, link array is already started by left refs
+					last := len(nLinks) - 1
+					nLinks[last] = append(nLinks[last], make([]int, col+1-len(nLinks[last]))...)
+					nLinks[last][col] = ref
+					break
 				}
+				refs := make([]int, col+1)
+				refs[col] = ref
 				nLinks = append(nLinks, refs)
 			case "╣", "╣║", "╫╣", "╬": // append current to last link array
 				last := len(nLinks) - 1
@@ -147,8 +154,12 @@ func ASCIIschemeForEach(
 				}
 				other := nodes[i]
 				last := len(events[other]) - ref
-				// fork first event -> Don't add any parents.
 				if last < 0 {
+					// fork of the first event hasn't self-parent
+					if other == creator {
+						continue
+					}
+					// fork first event -> Don't add any parents.
 					break
 				}
 				parent := events[other][last]
@@ -227,20 +238,11 @@ func DAGtoASCIIscheme(events Events) (string, error) {
 
 		eventIndex       = make(map[idx.StakerID]map[hash.Event]int)
 		creatorLastIndex = make(map[idx.StakerID]int)
-
-		seqCount = make(map[idx.StakerID]map[idx.Event]int)
 	)
 	for _, e := range events {
-		// if count of unique seq > 1 -> fork
-		if _, exist := seqCount[e.Creator]; !exist {
-			seqCount[e.Creator] = map[idx.Event]int{}
+		if _, exist := eventIndex[e.Creator]; !exist {
 			eventIndex[e.Creator] = map[hash.Event]int{}
 		}
-		if _, exist := seqCount[e.Creator][e.Seq]; !exist {
-			seqCount[e.Creator][e.Seq] = 1
-		} else {
-			seqCount[e.Creator][e.Seq]++
-		}
 
 		if _, exist := creatorLastIndex[e.Creator]; !exist {
 			creatorLastIndex[e.Creator] = 0
@@ -278,8 +280,8 @@ func DAGtoASCIIscheme(events Events) (string, error) {
 			if parent.Creator == e.Creator {
 				selfRefs++
 
-				// if more then 1 -> fork. Don't skip refs filling.
-				if seqCount[e.Creator][e.Seq] == 1 {
+				// if self-parent isn't the last creator's event -> fork. Don't skip refs filling.
+				if eventIndex[e.Creator][p] == creatorLastIndex[e.Creator]-1 {
 					continue
 				}
 			}
@@ -296,6 +298,10 @@ func DAGtoASCIIscheme(events Events) (string, error) {
 		if (e.Seq <= 1 && selfRefs != 0) || (e.Seq > 1 && selfRefs != 1) {
 			return "", fmt.Errorf("self-parents count of %s is %d", ehash, selfRefs)
 		}
+		if selfRefs == 0 && creatorLastIndex[e.Creator] > 0 {
+			// fork of the first event -> ref before the first creator's event
+			r.Refs[r.Self] = creatorLastIndex[e.Creator] + 1
+		}
 
 		// first and last refs
 		r.First = len(r.Refs)
@@ -396,6 +402,10 @@ func (rr *rows) Optimize() {
 			if ref < 3 {
 				continue REFS
 			}
+			// self-ref of fork isn't swappable
+			if iRef == row.Self {
+				continue REFS
+			}
 
 			// find prev event for swap
 			prev := curr - 1
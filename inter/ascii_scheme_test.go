@@ -1,6 +1,7 @@
 package inter
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -104,6 +105,43 @@ func TestDAGtoASCIIschemeRand(t *testing.T) {
 	}
 }
 
+func TestDAGtoASCIIschemeRandForks(t *testing.T) {
+	assertar := assert.New(t)
+
+	for seed := int64(0); seed < 10; seed++ {
+		nodes := GenNodes(5)
+		var src Events
+		ForEachRandFork(nodes, nodes[:2], 10, 3, 5, rand.New(rand.NewSource(seed)), ForEachEvent{
+			Process: func(e *Event, name string) {
+				src = append(src, e)
+			},
+			Build: func(e *Event, name string) *Event {
+				e.Extra = []byte(name) // forks may be the same otherwise
+				return e
+			},
+		})
+
+		scheme, err := DAGtoASCIIscheme(src)
+		if !assertar.NoError(err) {
+			return
+		}
+		_, _, names := ASCIIschemeToDAG(scheme)
+
+		if !assertar.Equal(len(src), len(names), "event count") {
+			return
+		}
+		for _, e0 := range src {
+			n := e0.Hash().String()
+			e1 := names[n]
+			if !assertar.Equal(e0.Seq, e1.Seq, "seq of "+n) ||
+				!assertar.EqualValues(edges2text(e0), edges2text(e1), "at event "+n) {
+				t.Log(scheme)
+				return
+			}
+		}
+	}
+}
+
 func TestDAGtoASCIIschemeOptimisation(t *testing.T) {
 
 	t.Run("Simple", func(t *testing.T) {
@@ -0,0 +1,181 @@
+package inter
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// Typed Extra is a versioned TLV encoding of the event's Extra field:
+//   magic (1 byte) | schema version (1 byte) | records...
+//   record = type (1 byte) | value length (1 byte) | value
+// Records are ordered by type, each type occurs at most once.
+// Layout of the records is the same for all the schema versions, a newer version may only add types,
+// so nodes decode the records of unknown types as is.
+// Extra isn't validated by consensus, so Extra which starts with the magic byte but isn't decodable
+// (e.g. legacy opaque Extra) is valid, and is treated as opaque.
+
+const (
+	// ExtraMagic is the first byte of typed Extra. Legacy Extra is opaque, it may start with the byte too.
+	ExtraMagic = 0xfe
+	// ExtraSchemaVersion is the current schema version of typed Extra.
+	ExtraSchemaVersion = 1
+)
+
+// ExtraType is a type of the typed Extra record.
+type ExtraType uint8
+
+const (
+	// ExtraNodeVersion is the node version string.
+	ExtraNodeVersion ExtraType = 1
+	// ExtraGasPriceHint is the minimum gas price accepted by the validator, big-endian unsigned integer.
+	ExtraGasPriceHint ExtraType = 2
+	// ExtraValidatorAnnounce is an opaque announcement of the validator, e.g. its enode URL.
+	ExtraValidatorAnnounce ExtraType = 3
+
+	// ExtraAppFirst is the first type of the app-defined payloads. Types below are reserved by the schema.
+	ExtraAppFirst ExtraType = 0x80
+)
+
+// legacyVersionPrefix is a prefix of legacy Extra, which is a published node version.
+var legacyVersionPrefix = []byte("v-")
+
+var (
+	// ErrNotTypedExtra is returned if Extra is opaque.
+	ErrNotTypedExtra = errors.New("extra isn't typed")
+	// ErrMalformedExtra is returned if typed Extra is malformed.
+	ErrMalformedExtra = errors.New("typed extra is malformed")
+	// ErrExtraRecordTooLarge is returned if the record value doesn't fit into the record.
+	ErrExtraRecordTooLarge = errors.New("typed extra record is too large")
+)
+
+// ExtraFields are the decoded typed Extra.
+type ExtraFields struct {
+	SchemaVersion uint8
+
+	NodeVersion       string
+	GasPriceHint      *big.Int
+	ValidatorAnnounce []byte
+
+	// App-defined payloads, by types from ExtraAppFirst.
+	App map[ExtraType][]byte
+	// Records of the reserved types, which are unknown to the current schema version.
+	Unknown map[ExtraType][]byte
+}
+
+// IsTypedExtra returns true if Extra starts as typed. It's typed only if DecodeExtra succeeds.
+func IsTypedExtra(raw []byte) bool {
+	return len(raw) > 0 && raw[0] == ExtraMagic
+}
+
+// DecodeExtra decodes typed Extra.
+func DecodeExtra(raw []byte) (*ExtraFields, error) {
+	if !IsTypedExtra(raw) {
+		return nil, ErrNotTypedExtra
+	}
+	if len(raw) < 2 || raw[1] == 0 {
+		return nil, ErrMalformedExtra
+	}
+
+	x := &ExtraFields{
+		SchemaVersion: raw[1],
+	}
+	var prev ExtraType
+	for pos := 2; pos < len(raw); {
+		if pos+2 > len(raw) {
+			return nil, ErrMalformedExtra
+		}
+		t := ExtraType(raw[pos])
+		size := int(raw[pos+1])
+		pos += 2
+		if pos+size > len(raw) || t == 0 || t <= prev {
+			return nil, ErrMalformedExtra
+		}
+		value := raw[pos : pos+size : pos+size]
+		pos += size
+		prev = t
+
+		switch {
+		case t == ExtraNodeVersion:
+			x.NodeVersion = string(value)
+		case t == ExtraGasPriceHint:
+			if len(value) > 0 && value[0] == 0 {
+				return nil, ErrMalformedExtra // non-canonical
+			}
+			x.GasPriceHint = new(big.Int).SetBytes(value)
+		case t == ExtraValidatorAnnounce:
+			x.ValidatorAnnounce = value
+		case t >= ExtraAppFirst:
+			if x.App == nil {
+				x.App = make(map[ExtraType][]byte)
+			}
+			x.App[t] = value
+		default:
+			if x.Unknown == nil {
+				x.Unknown = make(map[ExtraType][]byte)
+			}
+			x.Unknown[t] = value
+		}
+	}
+	return x, nil
+}
+
+// Encode returns typed Extra of the current schema version. Empty fields are omitted.
+func (x *ExtraFields) Encode() ([]byte, error) {
+	records := make(map[ExtraType][]byte, 3+len(x.App)+len(x.Unknown))
+	if len(x.NodeVersion) != 0 {
+		records[ExtraNodeVersion] = []byte(x.NodeVersion)
+	}
+	if x.GasPriceHint != nil {
+		if x.GasPriceHint.Sign() < 0 {
+			return nil, ErrMalformedExtra
+		}
+		records[ExtraGasPriceHint] = x.GasPriceHint.Bytes()
+	}
+	if len(x.ValidatorAnnounce) != 0 {
+		records[ExtraValidatorAnnounce] = x.ValidatorAnnounce
+	}
+	for t, value := range x.Unknown {
+		if t == 0 || t >= ExtraAppFirst {
+			return nil, ErrMalformedExtra
+		}
+		records[t] = value
+	}
+	for t, value := range x.App {
+		if t < ExtraAppFirst {
+			return nil, ErrMalformedExtra
+		}
+		records[t] = value
+	}
+
+	types := make([]int, 0, len(records))
+	for t := range records {
+		types = append(types, int(t))
+	}
+	sort.Ints(types)
+
+	buf := bytes.NewBuffer([]byte{ExtraMagic, ExtraSchemaVersion})
+	for _, t := range types {
+		value := records[ExtraType(t)]
+		if len(value) > 0xff {
+			return nil, ErrExtraRecordTooLarge
+		}
+		buf.WriteByte(byte(t))
+		buf.WriteByte(byte(len(value)))
+		buf.Write(value)
+	}
+	return buf.Bytes(), nil
+}
+
+// ExtraNodeVersionOf returns the node version published in Extra, either typed or legacy.
+func ExtraNodeVersionOf(raw []byte) (string, bool) {
+	if bytes.HasPrefix(raw, legacyVersionPrefix) {
+		return string(raw[len(legacyVersionPrefix):]), true
+	}
+	x, err := DecodeExtra(raw)
+	if err != nil || len(x.NodeVersion) == 0 {
+		return "", false
+	}
+	return x.NodeVersion, true
+}
@@ -0,0 +1,135 @@
+package inter
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtraEncoding(t *testing.T) {
+	assertar := assert.New(t)
+
+	x0 := &ExtraFields{
+		NodeVersion:       "0.7.0-rc.1",
+		GasPriceHint:      big.NewInt(1e9),
+		ValidatorAnnounce: []byte("enode://"),
+		App: map[ExtraType][]byte{
+			ExtraAppFirst + 1: {1, 2, 3},
+			ExtraAppFirst:     {},
+		},
+		Unknown: map[ExtraType][]byte{
+			0x10: {0xff},
+		},
+	}
+	raw, err := x0.Encode()
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.True(IsTypedExtra(raw))
+	assertar.Equal([]byte{ExtraMagic, ExtraSchemaVersion, byte(ExtraNodeVersion)}, raw[:3])
+
+	x1, err := DecodeExtra(raw)
+	if !assertar.NoError(err) {
+		return
+	}
+	x0.SchemaVersion = ExtraSchemaVersion
+	assertar.Equal(x0, x1)
+
+	// deterministic
+	again, _ := x1.Encode()
+	assertar.Equal(raw, again)
+
+	// empty
+	raw, err = (&ExtraFields{}).Encode()
+	assertar.NoError(err)
+	assertar.Equal([]byte{ExtraMagic, ExtraSchemaVersion}, raw)
+	x1, err = DecodeExtra(raw)
+	assertar.NoError(err)
+	assertar.Equal(&ExtraFields{SchemaVersion: ExtraSchemaVersion}, x1)
+
+	// zero gas price
+	raw, err = (&ExtraFields{GasPriceHint: new(big.Int)}).Encode()
+	assertar.NoError(err)
+	x1, err = DecodeExtra(raw)
+	assertar.NoError(err)
+	assertar.Equal(0, x1.GasPriceHint.Sign())
+}
+
+func TestExtraEncodingErrors(t *testing.T) {
+	assertar := assert.New(t)
+
+	for _, x := range []*ExtraFields{
+		{GasPriceHint: big.NewInt(-1)},
+		{App: map[ExtraType][]byte{ExtraAppFirst - 1: {}}},
+		{Unknown: map[ExtraType][]byte{0: {}}},
+		{Unknown: map[ExtraType][]byte{ExtraAppFirst: {}}},
+	} {
+		_, err := x.Encode()
+		assertar.Equal(ErrMalformedExtra, err)
+	}
+
+	_, err := (&ExtraFields{ValidatorAnnounce: bytes.Repeat([]byte{1}, 0x100)}).Encode()
+	assertar.Equal(ErrExtraRecordTooLarge, err)
+}
+
+func TestExtraDecodingErrors(t *testing.T) {
+	assertar := assert.New(t)
+
+	for _, raw := range [][]byte{
+		nil,
+		{},
+		[]byte("v-0.7.0"),
+		{0xff, ExtraSchemaVersion},
+	} {
+		_, err := DecodeExtra(raw)
+		assertar.Equal(ErrNotTypedExtra, err, raw)
+	}
+
+	for _, raw := range [][]byte{
+		{ExtraMagic},
+		{ExtraMagic, 0},
+		{ExtraMagic, ExtraSchemaVersion, 1},
+		{ExtraMagic, ExtraSchemaVersion, 1, 2, 'v'},
+		{ExtraMagic, ExtraSchemaVersion, 0, 0},
+		{ExtraMagic, ExtraSchemaVersion, 2, 1, 1, 1, 1, 'v'},
+		{ExtraMagic, ExtraSchemaVersion, 1, 1, 'v', 1, 1, 'v'},
+		{ExtraMagic, ExtraSchemaVersion, 2, 2, 0, 1},
+	} {
+		_, err := DecodeExtra(raw)
+		assertar.Equal(ErrMalformedExtra, err, raw)
+	}
+
+	// a newer schema version is decoded by the known layout
+	x, err := DecodeExtra([]byte{ExtraMagic, ExtraSchemaVersion + 1, 1, 1, 'v', 0x7f, 1, 0xaa})
+	if assertar.NoError(err) {
+		assertar.Equal(uint8(ExtraSchemaVersion+1), x.SchemaVersion)
+		assertar.Equal("v", x.NodeVersion)
+		assertar.Equal(map[ExtraType][]byte{0x7f: {0xaa}}, x.Unknown)
+	}
+}
+
+func TestExtraNodeVersionOf(t *testing.T) {
+	assertar := assert.New(t)
+
+	v, ok := ExtraNodeVersionOf([]byte("v-0.7.0"))
+	assertar.True(ok)
+	assertar.Equal("0.7.0", v)
+
+	raw, _ := (&ExtraFields{NodeVersion: "0.8.0"}).Encode()
+	v, ok = ExtraNodeVersionOf(raw)
+	assertar.True(ok)
+	assertar.Equal("0.8.0", v)
+
+	raw, _ = (&ExtraFields{GasPriceHint: big.NewInt(1)}).Encode()
+	_, ok = ExtraNodeVersionOf(raw)
+	assertar.False(ok)
+
+	_, ok = ExtraNodeVersionOf([]byte("opaque"))
+	assertar.False(ok)
+
+	// opaque Extra, which starts with the magic byte
+	_, ok = ExtraNodeVersionOf([]byte{ExtraMagic, 0, 'v'})
+	assertar.False(ok)
+}
@@ -0,0 +1,63 @@
+package inter
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+)
+
+var (
+	// ErrNotFork indicates that the evidence events aren't a fork.
+	ErrNotFork = errors.New("evidence events aren't a fork")
+	// ErrEvidenceSig indicates that the evidence event isn't signed by the cheater.
+	ErrEvidenceSig = errors.New("evidence event isn't signed by the cheater")
+)
+
+// ForkEvidence is a pair of signed events of the same creator, which have the same Seq.
+// Such events cannot be on the same chain of self-parents, so the creator is a cheater.
+type ForkEvidence struct {
+	A, B EventHeader // ordered by ID
+}
+
+// NewForkEvidence makes the evidence from the conflicting events.
+func NewForkEvidence(a, b *EventHeader) *ForkEvidence {
+	if bytes.Compare(a.Hash().Bytes(), b.Hash().Bytes()) > 0 {
+		a, b = b, a
+	}
+	return &ForkEvidence{
+		A: *a,
+		B: *b,
+	}
+}
+
+// Hash returns hash of the RLP-encoded evidence.
+func (ev *ForkEvidence) Hash() common.Hash {
+	buf, err := rlp.EncodeToBytes(ev)
+	if err != nil {
+		panic(err)
+	}
+	return hash.Of(buf)
+}
+
+// Verify checks that the events are a fork of the cheater, which is authenticated by the address.
+func (ev *ForkEvidence) Verify(cheater common.Address) error {
+	// don't trust the cached hashes
+	a, b := &ev.A, &ev.B
+	a.RecacheHash()
+	b.RecacheHash()
+
+	if a.Hash() == b.Hash() ||
+		a.Creator != b.Creator ||
+		a.Epoch != b.Epoch ||
+		a.Seq != b.Seq {
+		return ErrNotFork
+	}
+	if !a.VerifySignature(cheater) || !b.VerifySignature(cheater) {
+		return ErrEvidenceSig
+	}
+	return nil
+}
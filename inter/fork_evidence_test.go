@@ -0,0 +1,53 @@
+package inter
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+func TestForkEvidence(t *testing.T) {
+	assertar := assert.New(t)
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	another, _ := crypto.GenerateKey()
+
+	signed := func(lamport idx.Lamport, extra string) *Event {
+		e := NewEvent()
+		e.Epoch = 2
+		e.Seq = 3
+		e.Creator = 1
+		e.Lamport = lamport
+		e.Parents = hash.Events{hash.FakeEvent()}
+		e.Extra = []byte(extra)
+		if !assertar.NoError(e.SignBy(key)) {
+			t.FailNow()
+		}
+		return e
+	}
+	a := signed(5, "a")
+	b := signed(6, "b")
+
+	ev := NewForkEvidence(&a.EventHeader, &b.EventHeader)
+	assertar.NoError(ev.Verify(addr))
+	assertar.Equal(ErrEvidenceSig, ev.Verify(crypto.PubkeyToAddress(another.PublicKey)))
+	// the same evidence regardless of the order
+	assertar.Equal(ev.Hash(), NewForkEvidence(&b.EventHeader, &a.EventHeader).Hash())
+
+	// not a fork
+	assertar.Equal(ErrNotFork, NewForkEvidence(&a.EventHeader, &a.EventHeader).Verify(addr))
+	c := signed(7, "c")
+	c.Seq = 4
+	assertar.NoError(c.SignBy(key))
+	assertar.Equal(ErrNotFork, NewForkEvidence(&a.EventHeader, &c.EventHeader).Verify(addr))
+
+	// tampered
+	ev = NewForkEvidence(&a.EventHeader, &b.EventHeader)
+	ev.B.Extra = []byte("tampered")
+	assertar.Equal(ErrEvidenceSig, ev.Verify(addr))
+}
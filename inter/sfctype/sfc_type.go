@@ -92,9 +92,10 @@ type EpochStats struct {
 	End      inter.Timestamp
 	TotalFee *big.Int
 
-	Epoch                 idx.Epoch `rlp:"-"` // API-only field
-	TotalBaseRewardWeight *big.Int  `rlp:"-"` // API-only field
-	TotalTxRewardWeight   *big.Int  `rlp:"-"` // API-only field
+	Epoch                 idx.Epoch     `rlp:"-"` // API-only field
+	TotalBaseRewardWeight *big.Int      `rlp:"-"` // API-only field
+	TotalTxRewardWeight   *big.Int      `rlp:"-"` // API-only field
+	ForkEvidences         []common.Hash `rlp:"-"` // API-only field
 }
 
 // Duration returns epoch duration
@@ -0,0 +1,463 @@
+// Package badgerdb implements the key-value database layer based on BadgerDB.
+package badgerdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dgraph-io/badger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// gcDiscardRatio is the ratio of stale data in a value log file
+	// to rewrite it during Compact.
+	gcDiscardRatio = 0.5
+)
+
+var (
+	errClosed = errors.New("database closed")
+)
+
+// Database is a persistent key-value store. Apart from basic data storage
+// functionality it also supports batch writes and iterating over the keyspace in
+// binary-alphabetical order.
+type Database struct {
+	fn string     // filename for reporting
+	db *badger.DB // BadgerDB instance
+
+	lock sync.RWMutex // protects db from closing during operations
+
+	log log.Logger // Contextual logger tracking the database path
+
+	onClose func() error
+	onDrop  func()
+}
+
+// New returns a wrapped BadgerDB object.
+func New(path string, close func() error, drop func()) (*Database, error) {
+	return open(path, false, close, drop)
+}
+
+// NewReadOnly returns a wrapped BadgerDB object, opened in read-only mode.
+// The value log isn't truncated on open, so the db must be closed properly before.
+func NewReadOnly(path string, close func() error, drop func()) (*Database, error) {
+	return open(path, true, close, drop)
+}
+
+func open(path string, readonly bool, close func() error, drop func()) (*Database, error) {
+	logger := log.New("database", path)
+
+	opts := badger.DefaultOptions(path).
+		WithLogger(&badgerLogger{logger}).
+		WithTruncate(!readonly).
+		WithReadOnly(readonly)
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Database{
+		fn:      path,
+		db:      db,
+		log:     logger,
+		onClose: close,
+		onDrop:  drop,
+	}, nil
+}
+
+// Close flushes any pending data to disk and closes
+// all io accesses to the underlying key-value store.
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		panic("already closed")
+	}
+
+	bdb := db.db
+	db.db = nil
+
+	if db.onClose != nil {
+		if err := db.onClose(); err != nil {
+			return err
+		}
+		db.onClose = nil
+	}
+	return bdb.Close()
+}
+
+// Drop whole database.
+func (db *Database) Drop() {
+	if db.db != nil {
+		panic("Close database first!")
+	}
+	if db.onDrop != nil {
+		db.onDrop()
+	}
+}
+
+// Has retrieves if a key is present in the key-value store.
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return false, errClosed
+	}
+
+	err := db.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(key)
+		return err
+	})
+	if err == badger.ErrKeyNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Get retrieves the given key if it's present in the key-value store.
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, errClosed
+	}
+
+	var val []byte
+	err := db.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err != nil {
+			return err
+		}
+		val, err = item.ValueCopy(nil)
+		return err
+	})
+	if err == badger.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// empty value is not the same as missing key
+	if val == nil {
+		val = []byte{}
+	}
+	return val, nil
+}
+
+// Put inserts the given value into the key-value store.
+func (db *Database) Put(key []byte, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return errClosed
+	}
+
+	return db.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(common.CopyBytes(key), common.CopyBytes(value))
+	})
+}
+
+// Delete removes the key from the key-value store.
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return errClosed
+	}
+
+	return db.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(common.CopyBytes(key))
+	})
+}
+
+// NewBatch creates a write-only key-value store that buffers changes to its host
+// database until a final write is called.
+func (db *Database) NewBatch() ethdb.Batch {
+	return &batch{
+		db: db,
+	}
+}
+
+// NewIterator creates a binary-alphabetical iterator over the entire keyspace
+// contained within the badger database.
+func (db *Database) NewIterator() ethdb.Iterator {
+	return db.newIterator(nil, nil)
+}
+
+// NewIteratorWithStart creates a binary-alphabetical iterator over a subset of
+// database content starting at a particular initial key (or after, if it does
+// not exist).
+func (db *Database) NewIteratorWithStart(start []byte) ethdb.Iterator {
+	return db.newIterator(start, nil)
+}
+
+// NewIteratorWithPrefix creates a binary-alphabetical iterator over a subset
+// of database content with a particular key prefix.
+func (db *Database) NewIteratorWithPrefix(prefix []byte) ethdb.Iterator {
+	return db.newIterator(prefix, prefix)
+}
+
+func (db *Database) newIterator(start, prefix []byte) ethdb.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return &iterator{err: errClosed}
+	}
+
+	txn := db.db.NewTransaction(false)
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = common.CopyBytes(prefix)
+
+	return &iterator{
+		txn:    txn,
+		it:     txn.NewIterator(opts),
+		start:  common.CopyBytes(start),
+		prefix: opts.Prefix,
+	}
+}
+
+// Stat returns a particular internal stat of the database.
+func (db *Database) Stat(property string) (string, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return "", errClosed
+	}
+
+	switch property {
+	case "badger.size":
+		lsm, vlog := db.db.Size()
+		return fmt.Sprintf("LSM(bytes):%d ValueLog(bytes):%d", lsm, vlog), nil
+	case "badger.tables":
+		var buf bytes.Buffer
+		for _, t := range db.db.Tables(true) {
+			fmt.Fprintf(&buf, "Level:%d ID:%d Keys:%d\n", t.Level, t.ID, t.KeyCount)
+		}
+		return buf.String(), nil
+	default:
+		return "", errors.New("unknown property")
+	}
+}
+
+// Compact flattens the LSM tree into a single level and discards stale
+// value log data. BadgerDB doesn't support range compaction, so start and
+// limit are ignored and the entire data store is compacted.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return errClosed
+	}
+
+	if err := db.db.Flatten(1); err != nil {
+		return err
+	}
+	for {
+		err := db.db.RunValueLogGC(gcDiscardRatio)
+		if err == badger.ErrNoRewrite {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Path returns the path to the database directory.
+func (db *Database) Path() string {
+	return db.fn
+}
+
+// iterator is a read-only snapshot iterator over badger keyspace.
+type iterator struct {
+	txn    *badger.Txn
+	it     *badger.Iterator
+	start  []byte
+	prefix []byte
+
+	started bool
+	err     error
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *iterator) Next() bool {
+	if it.it == nil {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		it.it.Seek(it.start)
+	} else {
+		it.it.Next()
+	}
+	return it.it.ValidForPrefix(it.prefix)
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (it *iterator) Error() error {
+	return it.err
+}
+
+// Key returns the key of the current key/value pair, or nil if done. The caller
+// should not modify the contents of the returned slice, and its contents may
+// change on the next call to Next.
+func (it *iterator) Key() []byte {
+	if !it.valid() {
+		return nil
+	}
+	return it.it.Item().Key()
+}
+
+// Value returns the value of the current key/value pair, or nil if done. The
+// caller should not modify the contents of the returned slice, and its contents
+// may change on the next call to Next.
+func (it *iterator) Value() []byte {
+	if !it.valid() {
+		return nil
+	}
+	val, err := it.it.Item().ValueCopy(nil)
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	if val == nil {
+		val = []byte{}
+	}
+	return val
+}
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (it *iterator) Release() {
+	if it.it != nil {
+		it.it.Close()
+		it.txn.Discard()
+	}
+	it.it = nil
+	it.txn = nil
+}
+
+func (it *iterator) valid() bool {
+	return it.it != nil && it.started && it.it.ValidForPrefix(it.prefix)
+}
+
+// keyvalue is a key-value tuple tagged with a deletion field to allow creating
+// badger write batches.
+type keyvalue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch is a write-only badger batch that commits changes to its host database
+// when Write is called. A batch cannot be used concurrently.
+type batch struct {
+	db     *Database
+	writes []keyvalue
+	size   int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyvalue{common.CopyBytes(key), common.CopyBytes(value), false})
+	b.size += len(value)
+	return nil
+}
+
+// Delete inserts the a key removal into the batch for later committing.
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyvalue{common.CopyBytes(key), nil, true})
+	b.size++
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.db == nil {
+		return errClosed
+	}
+
+	wb := b.db.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	for _, kv := range b.writes {
+		var err error
+		if kv.delete {
+			err = wb.Delete(kv.key)
+		} else {
+			err = wb.Set(kv.key, kv.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return wb.Flush()
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w ethdb.KeyValueWriter) error {
+	for _, kv := range b.writes {
+		if kv.delete {
+			if err := w.Delete(kv.key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.Put(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// badgerLogger redirects BadgerDB logs into contextual logger.
+type badgerLogger struct {
+	log log.Logger
+}
+
+func (l *badgerLogger) Errorf(format string, args ...interface{}) {
+	l.log.Error(strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
+
+func (l *badgerLogger) Warningf(format string, args ...interface{}) {
+	l.log.Warn(strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
+
+func (l *badgerLogger) Infof(format string, args ...interface{}) {
+	l.log.Debug(strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
+
+func (l *badgerLogger) Debugf(format string, args ...interface{}) {
+	l.log.Trace(strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
@@ -0,0 +1,189 @@
+package badgerdb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tempDir(name string) string {
+	dir, err := ioutil.TempDir("", name)
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+// Tests that key-value iteration on top of a badger database works.
+func TestBadgerDBIterator(t *testing.T) {
+	tests := []struct {
+		content map[string]string
+		prefix  string
+		order   []string
+	}{
+		// Empty databases should be iterable
+		{map[string]string{}, "", nil},
+		{map[string]string{}, "non-existent-prefix", nil},
+
+		// Single-item databases should be iterable
+		{map[string]string{"key": "val"}, "", []string{"key"}},
+		{map[string]string{"key": "val"}, "k", []string{"key"}},
+		{map[string]string{"key": "val"}, "l", nil},
+
+		// Multi-item databases should be fully iterable
+		{
+			map[string]string{"k1": "v1", "k5": "v5", "k2": "v2", "k4": "v4", "k3": "v3"},
+			"",
+			[]string{"k1", "k2", "k3", "k4", "k5"},
+		},
+		{
+			map[string]string{"k1": "v1", "k5": "v5", "k2": "v2", "k4": "v4", "k3": "v3"},
+			"k",
+			[]string{"k1", "k2", "k3", "k4", "k5"},
+		},
+		{
+			map[string]string{"k1": "v1", "k5": "v5", "k2": "v2", "k4": "v4", "k3": "v3"},
+			"l",
+			nil,
+		},
+		// Multi-item databases should be prefix-iterable
+		{
+			map[string]string{
+				"ka1": "va1", "ka5": "va5", "ka2": "va2", "ka4": "va4", "ka3": "va3",
+				"kb1": "vb1", "kb5": "vb5", "kb2": "vb2", "kb4": "vb4", "kb3": "vb3",
+			},
+			"ka",
+			[]string{"ka1", "ka2", "ka3", "ka4", "ka5"},
+		},
+		{
+			map[string]string{
+				"ka1": "va1", "ka5": "va5", "ka2": "va2", "ka4": "va4", "ka3": "va3",
+				"kb1": "vb1", "kb5": "vb5", "kb2": "vb2", "kb4": "vb4", "kb3": "vb3",
+			},
+			"kc",
+			nil,
+		},
+	}
+	for i, tt := range tests {
+		// Create the key-value data store
+		dir := tempDir("TestBadgerDBIterator")
+		db, err := New(dir, nil, func() { os.RemoveAll(dir) })
+		if err != nil {
+			t.Fatalf("test %d: failed to open database: %v", i, err)
+		}
+		for key, val := range tt.content {
+			if err := db.Put([]byte(key), []byte(val)); err != nil {
+				t.Fatalf("test %d: failed to insert item %s:%s into database: %v", i, key, val, err)
+			}
+		}
+		// Iterate over the database with the given configs and verify the results
+		it, idx := db.NewIteratorWithPrefix([]byte(tt.prefix)), 0
+		for it.Next() {
+			if !bytes.Equal(it.Key(), []byte(tt.order[idx])) {
+				t.Errorf("test %d: item %d: key mismatch: have %s, want %s", i, idx, string(it.Key()), tt.order[idx])
+			}
+			if !bytes.Equal(it.Value(), []byte(tt.content[tt.order[idx]])) {
+				t.Errorf("test %d: item %d: value mismatch: have %s, want %s", i, idx, string(it.Value()), tt.content[tt.order[idx]])
+			}
+			idx++
+		}
+		if err := it.Error(); err != nil {
+			t.Errorf("test %d: iteration failed: %v", i, err)
+		}
+		if idx != len(tt.order) {
+			t.Errorf("test %d: iteration terminated prematurely: have %d, want %d", i, idx, len(tt.order))
+		}
+		it.Release()
+
+		db.Close()
+		db.Drop()
+	}
+}
+
+func TestBadgerDBEmptyValue(t *testing.T) {
+	assertar := assert.New(t)
+
+	dir := tempDir("TestBadgerDBEmptyValue")
+	db, err := New(dir, nil, func() { os.RemoveAll(dir) })
+	if !assertar.NoError(err) {
+		return
+	}
+	defer db.Drop()
+	defer db.Close()
+
+	key := []byte("key")
+
+	val, err := db.Get(key)
+	assertar.NoError(err)
+	assertar.Nil(val)
+
+	assertar.NoError(db.Put(key, []byte{}))
+
+	ok, err := db.Has(key)
+	assertar.NoError(err)
+	assertar.True(ok)
+
+	val, err = db.Get(key)
+	assertar.NoError(err)
+	assertar.NotNil(val)
+	assertar.Equal(0, len(val))
+}
+
+func TestBadgerDBProducer(t *testing.T) {
+	assertar := assert.New(t)
+
+	dir := tempDir("TestBadgerDBProducer")
+	defer os.RemoveAll(dir)
+
+	p := NewProducer(dir)
+	assertar.Empty(p.Names())
+
+	db1 := p.OpenDb("db1")
+	db2 := p.OpenDb("db2")
+	assertar.ElementsMatch([]string{"db1", "db2"}, p.Names())
+
+	assertar.NoError(db1.Put([]byte("key"), []byte("val")))
+	assertar.NoError(db1.Close())
+
+	// reopen
+	db1 = p.OpenDb("db1")
+	val, err := db1.Get([]byte("key"))
+	assertar.NoError(err)
+	assertar.Equal([]byte("val"), val)
+
+	// drop
+	assertar.NoError(db1.Close())
+	db1.Drop()
+	assertar.Equal([]string{"db2"}, p.Names())
+
+	assertar.NoError(db2.Close())
+	db2.Drop()
+	assertar.Empty(p.Names())
+}
+
+func TestBadgerDBReadOnlyProducer(t *testing.T) {
+	assertar := assert.New(t)
+
+	dir := tempDir("TestBadgerDBReadOnlyProducer")
+	defer os.RemoveAll(dir)
+
+	db := NewProducer(dir).OpenDb("db")
+	assertar.NoError(db.Put([]byte("key"), []byte("val")))
+	assertar.NoError(db.Close())
+
+	db = NewReadOnlyProducer(dir).OpenDb("db")
+	defer db.Close()
+
+	val, err := db.Get([]byte("key"))
+	assertar.NoError(err)
+	assertar.Equal([]byte("val"), val)
+	assertar.Error(db.Put([]byte("key"), []byte("new")))
+
+	assertar.Panics(func() {
+		NewReadOnlyProducer(dir).OpenDb("missing")
+	})
+	assertar.Equal([]string{"db"}, NewProducer(dir).Names())
+}
@@ -0,0 +1,86 @@
+package badgerdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+)
+
+// dirSuffix is a suffix of badger db directories.
+const dirSuffix = "-bdb"
+
+type producer struct {
+	datadir  string
+	readonly bool
+}
+
+// NewProducer of badger db.
+func NewProducer(datadir string) kvdb.DbProducer {
+	return &producer{
+		datadir: datadir,
+	}
+}
+
+// NewReadOnlyProducer of badger db. It opens only existing dbs, in read-only mode.
+func NewReadOnlyProducer(datadir string) kvdb.DbProducer {
+	return &producer{
+		datadir:  datadir,
+		readonly: true,
+	}
+}
+
+// Names of existing databases.
+func (p *producer) Names() []string {
+	var names []string
+
+	files, err := ioutil.ReadDir(p.datadir)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+		dirname := f.Name()
+		if strings.HasSuffix(dirname, dirSuffix) {
+			name := strings.TrimSuffix(dirname, dirSuffix)
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// OpenDb or create db with name.
+func (p *producer) OpenDb(name string) kvdb.KeyValueStore {
+	dir := name + dirSuffix
+	path := filepath.Join(p.datadir, dir)
+
+	if !p.readonly {
+		err := os.MkdirAll(path, 0700)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	onDrop := func() {
+		err := os.RemoveAll(path)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	newDb := New
+	if p.readonly {
+		newDb = NewReadOnly
+	}
+	db, err := newDb(path, nil, onDrop)
+	if err != nil {
+		panic(err)
+	}
+
+	return db
+}
@@ -0,0 +1,62 @@
+// Package dbengine maps on-disk database engine names to db producers.
+package dbengine
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/badgerdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/leveldb"
+)
+
+const (
+	// LevelDb is a default on-disk db engine.
+	LevelDb = "leveldb"
+	// BadgerDb is an alternative on-disk db engine.
+	BadgerDb = "badger"
+)
+
+// Engines is a list of all the supported engines.
+var Engines = []string{LevelDb, BadgerDb}
+
+// NewProducer of the engine databases in datadir. Empty engine means default one.
+func NewProducer(engine, datadir string) (kvdb.DbProducer, error) {
+	switch engine {
+	case "", LevelDb:
+		return leveldb.NewProducer(datadir), nil
+	case BadgerDb:
+		return badgerdb.NewProducer(datadir), nil
+	default:
+		return nil, fmt.Errorf("unknown database engine %q", engine)
+	}
+}
+
+// NewReadOnlyProducer of the engine databases in datadir, which opens only existing dbs in read-only mode.
+// Empty engine means default one.
+func NewReadOnlyProducer(engine, datadir string) (kvdb.DbProducer, error) {
+	switch engine {
+	case "", LevelDb:
+		return leveldb.NewReadOnlyProducer(datadir), nil
+	case BadgerDb:
+		return badgerdb.NewReadOnlyProducer(datadir), nil
+	default:
+		return nil, fmt.Errorf("unknown database engine %q", engine)
+	}
+}
+
+// Detect returns engines which have databases in datadir.
+func Detect(datadir string) []string {
+	if _, err := os.Stat(datadir); os.IsNotExist(err) {
+		return nil
+	}
+
+	var found []string
+	for _, engine := range Engines {
+		producer, _ := NewProducer(engine, datadir)
+		if len(producer.Names()) > 0 {
+			found = append(found, engine)
+		}
+	}
+	return found
+}
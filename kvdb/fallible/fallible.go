@@ -69,13 +69,19 @@ func (f *Fallible) Put(key []byte, value []byte) error {
 
 // Delete removes the key from the key-value data store.
 func (f *Fallible) Delete(key []byte) error {
+	if !f.count() {
+		panic(errWriteLimit)
+	}
 	return f.Underlying.Delete(key)
 }
 
 // NewBatch creates a write-only database that buffers changes to its host db
 // until a final write is called.
 func (f *Fallible) NewBatch() ethdb.Batch {
-	return f.Underlying.NewBatch()
+	return &batch{
+		Batch: f.Underlying.NewBatch(),
+		db:    f,
+	}
 }
 
 // NewIterator creates a binary-alphabetical iterator over the entire keyspace
@@ -130,3 +136,17 @@ func (f *Fallible) Drop() {
 
 	f.Underlying.Drop()
 }
+
+// batch counts a batch write as a single write.
+type batch struct {
+	ethdb.Batch
+	db *Fallible
+}
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	if !b.db.count() {
+		panic(errWriteLimit)
+	}
+	return b.Batch.Write()
+}
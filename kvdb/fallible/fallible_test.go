@@ -38,4 +38,23 @@ func TestFallible(t *testing.T) {
 	assertar.Panics(func() {
 		err = db.Put(key, val)
 	})
+
+	assertar.Panics(func() {
+		db.Delete(key)
+	})
+
+	w.SetWriteCount(1)
+
+	batch := db.NewBatch()
+	assertar.NoError(batch.Delete(key))
+	assertar.NoError(batch.Put(key, val))
+	assertar.NoError(batch.Write())
+
+	assertar.Panics(func() {
+		batch.Write()
+	})
+
+	got, err := db.Get(key)
+	assertar.NoError(err)
+	assertar.Equal(val, got)
 }
@@ -26,6 +26,9 @@ type Flushable struct {
 	modified       *rbt.Tree // modified, comparing to parent, pairs. deleted values are nil
 	sizeEstimation *int
 
+	undo         *rbt.Tree // previous values of the modified keys, while undo is recorded. not existing values are nil
+	undoPrefixes [][]byte  // prefixes of the recorded keys, all the keys are recorded if empty
+
 	lock *sync.Mutex // we have no guarantees that rbt.Tree works with concurrent reads, so we can't use MutexRW
 }
 
@@ -73,6 +76,9 @@ func (w *Flushable) put(key []byte, value []byte) error {
 	if w.modified == nil {
 		return errClosed
 	}
+	if err := w.recordUndo(key); err != nil {
+		return err
+	}
 
 	w.modified.Put(string(key), common.CopyBytes(value))
 	*w.sizeEstimation += len(key) + len(value)
@@ -124,6 +130,9 @@ func (w *Flushable) Delete(key []byte) error {
 }
 
 func (w *Flushable) delete(key []byte) error {
+	if err := w.recordUndo(key); err != nil {
+		return err
+	}
 	w.modified.Put(string(key), nil)
 	*w.sizeEstimation += len(key) // it should be (len(key) - len(old value)), but we'd need to read old value
 	return nil
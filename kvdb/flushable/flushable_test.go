@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/badgerdb"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/leveldb"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/table"
@@ -37,19 +38,32 @@ func TestFlushable(t *testing.T) {
 	defer leveldb2.Drop()
 	defer leveldb2.Close()
 
+	bdisk := badgerProducer("TestFlushable")
+
+	badgerdb1 := bdisk.OpenDb("1")
+	defer badgerdb1.Drop()
+	defer badgerdb1.Close()
+
+	badgerdb2 := bdisk.OpenDb("2")
+	defer badgerdb2.Drop()
+	defer badgerdb2.Close()
+
 	// create wrappers
 	dbs := map[string]kvdb.KeyValueStore{
 		"leveldb": leveldb1,
 		"memory":  memorydb.New(),
+		"badger":  badgerdb1,
 	}
 
 	flushableDbs := map[string]*Flushable{
 		"cache-over-leveldb": Wrap(leveldb2),
 		"cache-over-memory":  Wrap(memorydb.New()),
+		"cache-over-badger":  Wrap(badgerdb2),
 	}
 
 	baseLdb := table.New(dbs["leveldb"], []byte{})
 	baseMem := table.New(dbs["memory"], []byte{})
+	baseBdb := table.New(dbs["badger"], []byte{})
 
 	dbsTables := [][]ethdb.KeyValueStore{
 		{
@@ -62,10 +76,16 @@ func TestFlushable(t *testing.T) {
 			baseMem.NewTable([]byte{0, 1}),
 			baseMem.NewTable([]byte{0}).NewTable(common.Hex2Bytes("ffffffffffffffffffffffffffffffffffff")),
 		},
+		{
+			dbs["badger"],
+			baseBdb.NewTable([]byte{0, 1}),
+			baseBdb.NewTable([]byte{0}).NewTable(common.Hex2Bytes("ffffffffffffffffffffffffffffffffffff")),
+		},
 	}
 
 	baseLdb = table.New(flushableDbs["cache-over-leveldb"], []byte{})
 	baseMem = table.New(flushableDbs["cache-over-memory"], []byte{})
+	baseBdb = table.New(flushableDbs["cache-over-badger"], []byte{})
 	flushableDbsTables := [][]kvdb.KeyValueStore{
 		{
 			flushableDbs["cache-over-leveldb"],
@@ -77,6 +97,11 @@ func TestFlushable(t *testing.T) {
 			baseMem.NewTable([]byte{0, 1}),
 			baseMem.NewTable([]byte{0}).NewTable(common.Hex2Bytes("ffffffffffffffffffffffffffffffffffff")),
 		},
+		{
+			flushableDbs["cache-over-badger"],
+			baseBdb.NewTable([]byte{0, 1}),
+			baseBdb.NewTable([]byte{0}).NewTable(common.Hex2Bytes("ffffffffffffffffffffffffffffffffffff")),
+		},
 	}
 
 	assertar.Equal(len(dbsTables), len(flushableDbsTables))
@@ -386,3 +411,11 @@ func dbProducer(name string) kvdb.DbProducer {
 	}
 	return leveldb.NewProducer(dir)
 }
+
+func badgerProducer(name string) kvdb.DbProducer {
+	dir, err := ioutil.TempDir("", name)
+	if err != nil {
+		panic(err)
+	}
+	return badgerdb.NewProducer(dir)
+}
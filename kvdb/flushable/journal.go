@@ -0,0 +1,151 @@
+package flushable
+
+import (
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/status-im/keycard-go/hexutils"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+)
+
+const (
+	// journalName is a reserved name of the db which stores the pending flush record.
+	journalName = "journal"
+)
+
+var (
+	journalKey = []byte("flush")
+)
+
+type (
+	// journalPair is a not flushed key-value pair. Del is true for deleted keys.
+	journalPair struct {
+		Key []byte
+		Val []byte
+		Del bool
+	}
+
+	// journalDb is a list of not flushed pairs of a db.
+	journalDb struct {
+		Name  string
+		Pairs []journalPair
+	}
+
+	// journalRecord is a write-ahead record of SyncedPool flush.
+	// It's written atomically before any db is touched and erased after all the dbs are flushed,
+	// so an interrupted flush may be rolled forward on startup.
+	journalRecord struct {
+		ID    []byte
+		Drops []string
+		Dbs   []journalDb
+	}
+)
+
+// notFlushed returns all the not flushed pairs. Caller must hold the lock.
+func (w *Flushable) notFlushed() []journalPair {
+	pairs := make([]journalPair, 0, w.modified.Size())
+	for it := w.modified.Iterator(); it.Next(); {
+		pair := journalPair{
+			Key: []byte(it.Key().(string)),
+		}
+		if it.Value() == nil {
+			pair.Del = true
+		} else {
+			pair.Val = it.Value().([]byte)
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// getJournal opens journal db lazily.
+func (p *SyncedPool) getJournal() kvdb.KeyValueStore {
+	if p.journal == nil {
+		p.journal = p.producer.OpenDb(journalName)
+	}
+	return p.journal
+}
+
+// writeJournal saves flush record atomically.
+func (p *SyncedPool) writeJournal(rec *journalRecord) error {
+	data, err := rlp.EncodeToBytes(rec)
+	if err != nil {
+		return err
+	}
+	return p.getJournal().Put(journalKey, data)
+}
+
+// eraseJournal marks flush as completed.
+func (p *SyncedPool) eraseJournal() error {
+	return p.getJournal().Delete(journalKey)
+}
+
+// replayJournal rolls forward the flush, which was interrupted by a crash.
+// Replay is idempotent, so it's safe to be interrupted as well.
+func (p *SyncedPool) replayJournal() error {
+	data, err := p.getJournal().Get(journalKey)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+
+	rec := &journalRecord{}
+	err = rlp.DecodeBytes(data, rec)
+	if err != nil {
+		return err
+	}
+	log.Warn("Rolling forward interrupted flush", "id", hexutils.BytesToHex(rec.ID))
+
+	existing := make(map[string]bool)
+	for _, name := range p.producer.Names() {
+		existing[name] = true
+	}
+
+	// drop old DBs, if not dropped yet
+	for _, name := range rec.Drops {
+		delete(p.wrappers, name)
+		if !existing[name] {
+			continue
+		}
+		db := p.producer.OpenDb(name)
+		err = db.Close()
+		if err != nil {
+			return err
+		}
+		db.Drop()
+	}
+
+	// write data
+	for _, jdb := range rec.Dbs {
+		db := p.getWrapper(jdb.Name).InitUnderlyingDb()
+
+		batch := db.NewBatch()
+		for _, pair := range jdb.Pairs {
+			if pair.Del {
+				err = batch.Delete(pair.Key)
+			} else {
+				err = batch.Put(pair.Key, pair.Val)
+			}
+			if err != nil {
+				return err
+			}
+
+			if batch.ValueSize() > ethdb.IdealBatchSize {
+				err = batch.Write()
+				if err != nil {
+					return err
+				}
+				batch.Reset()
+			}
+		}
+		err = batch.Write()
+		if err != nil {
+			return err
+		}
+	}
+
+	return p.eraseJournal()
+}
@@ -3,28 +3,48 @@ package flushable
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/status-im/keycard-go/hexutils"
 
 	"github.com/Fantom-foundation/go-lachesis/kvdb"
 )
 
+var (
+	flagKey = []byte("flag")
+)
+
 type SyncedPool struct {
 	producer kvdb.DbProducer
 
 	wrappers    map[string]*LazyFlushable
 	queuedDrops map[string]struct{}
 
+	journal kvdb.KeyValueStore
+
+	undo UndoScope // recorded tables, nil if undo isn't recorded
+
 	prevFlushTime time.Time
 
 	sync.Mutex
 }
 
 func NewSyncedPool(producer kvdb.DbProducer) *SyncedPool {
+	return newSyncedPool(producer, false)
+}
+
+// NewReadOnlySyncedPool is NewSyncedPool of the read-only dbs.
+// An interrupted flush isn't rolled forward, so the dbs are accepted only if they're synced.
+func NewReadOnlySyncedPool(producer kvdb.DbProducer) *SyncedPool {
+	return newSyncedPool(producer, true)
+}
+
+func newSyncedPool(producer kvdb.DbProducer, readonly bool) *SyncedPool {
 	if producer == nil {
 		panic("nil producer")
 	}
@@ -35,11 +55,22 @@ func NewSyncedPool(producer kvdb.DbProducer) *SyncedPool {
 		queuedDrops: make(map[string]struct{}),
 	}
 
+	hasJournal := false
 	for _, name := range producer.Names() {
+		if name == journalName {
+			hasJournal = true
+			continue
+		}
 		open, drop := p.callbacks(name)
 		p.wrappers[name] = NewLazy(open, drop)
 	}
 
+	if hasJournal && !readonly {
+		if err := p.replayJournal(); err != nil {
+			log.Crit("Failed to roll forward interrupted flush.", "err", err)
+		}
+	}
+
 	if err := p.checkDbsSynced(); err != nil {
 		log.Crit("Databases are corrupted, which is possible after a crash or disk failure.", "err", err)
 	}
@@ -77,6 +108,14 @@ func (p *SyncedPool) GetDb(name string) kvdb.KeyValueStore {
 }
 
 func (p *SyncedPool) getDb(name string) kvdb.KeyValueStore {
+	return p.getWrapper(name)
+}
+
+func (p *SyncedPool) getWrapper(name string) *LazyFlushable {
+	if name == journalName {
+		panic("reserved db name " + journalName)
+	}
+
 	wrapper := p.wrappers[name]
 	if wrapper != nil {
 		return wrapper
@@ -84,6 +123,9 @@ func (p *SyncedPool) getDb(name string) kvdb.KeyValueStore {
 
 	open, drop := p.callbacks(name)
 	wrapper = NewLazy(open, drop)
+	if prefixes, ok := p.undo[name]; ok {
+		wrapper.startUndo(prefixes)
+	}
 
 	p.wrappers[name] = wrapper
 
@@ -97,11 +139,60 @@ func (p *SyncedPool) Flush(id []byte) error {
 	return p.flush(id)
 }
 
+// flush writes all the not flushed data of all the dbs atomically:
+// the data is written into journal first, and only then into the dbs.
 func (p *SyncedPool) flush(id []byte) error {
-	key := []byte("flag")
 
-	// drop old DBs
+	// lock all the dbs to get a consistent state
+	locked := make([]*LazyFlushable, 0, len(p.wrappers))
+	for _, w := range p.wrappers {
+		w.lock.Lock()
+		locked = append(locked, w)
+	}
+	defer func() {
+		for _, w := range locked {
+			w.lock.Unlock()
+		}
+	}()
+
+	// prepare journal record
+	rec := &journalRecord{
+		ID: id,
+	}
 	for name := range p.queuedDrops {
+		rec.Drops = append(rec.Drops, name)
+	}
+	sort.Strings(rec.Drops)
+
+	names := make([]string, 0, len(p.wrappers))
+	for name := range p.wrappers {
+		if _, ok := p.queuedDrops[name]; ok {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		w := p.wrappers[name]
+		// mark db with flush id
+		err := w.put(flagKey, id)
+		if err != nil {
+			return err
+		}
+		rec.Dbs = append(rec.Dbs, journalDb{
+			Name:  name,
+			Pairs: w.notFlushed(),
+		})
+	}
+
+	err := p.writeJournal(rec)
+	if err != nil {
+		return err
+	}
+
+	// drop old DBs
+	for _, name := range rec.Drops {
 		w := p.wrappers[name]
 		delete(p.wrappers, name)
 		if w == nil {
@@ -116,47 +207,79 @@ func (p *SyncedPool) flush(id []byte) error {
 	}
 	p.queuedDrops = make(map[string]struct{})
 
-	// write dirty flags
-	for _, w := range p.wrappers {
-		db := w.InitUnderlyingDb()
-
-		prev, err := db.Get(key)
+	// flush data
+	for _, name := range names {
+		w := p.wrappers[name]
+		w.initUnderlyingDb()
+		err := w.flush()
 		if err != nil {
 			return err
 		}
-		if prev == nil {
-			prev = []byte("initial")
-		}
+	}
 
-		marker := bytes.NewBuffer(nil)
-		marker.Write([]byte("dirty"))
-		marker.Write(prev)
-		marker.Write(id)
-		err = db.Put(key, marker.Bytes())
-		if err != nil {
-			return err
-		}
+	err = p.eraseJournal()
+	if err != nil {
+		return err
 	}
 
-	// flush data
-	for _, wrapper := range p.wrappers {
-		err := wrapper.Flush()
-		if err != nil {
-			return err
+	p.prevFlushTime = time.Now()
+	return nil
+}
+
+// CloseAll closes all the opened dbs of the pool, including the journal.
+// Not flushed data is lost, so the pool should be flushed first.
+func (p *SyncedPool) CloseAll() error {
+	p.Lock()
+	defer p.Unlock()
+
+	var firstErr error
+	for _, w := range p.wrappers {
+		w.lock.Lock()
+		closed := w.modified == nil
+		w.lock.Unlock()
+		if closed {
+			continue
 		}
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if p.journal != nil {
+		if err := p.journal.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		p.journal = nil
 	}
+	return firstErr
+}
 
-	// write clean flags
-	for _, w := range p.wrappers {
-		db := w.InitUnderlyingDb()
-		err := db.Put(key, id)
-		if err != nil {
-			return err
+// Pause stops flushing until resume is called, and returns all the flushed dbs.
+// The dbs are in a consistent state until resume, so it's safe to copy them.
+func (p *SyncedPool) Pause() (dbs map[string]kvdb.KeyValueStore, resume func()) {
+	p.Lock()
+
+	onDisk := make(map[string]bool)
+	for _, name := range p.producer.Names() {
+		onDisk[name] = true
+	}
+
+	dbs = make(map[string]kvdb.KeyValueStore)
+	for name, w := range p.wrappers {
+		if _, ok := p.queuedDrops[name]; ok {
+			continue
+		}
+		if !onDisk[name] {
+			continue
 		}
+		dbs[name] = w.InitUnderlyingDb()
 	}
 
-	p.prevFlushTime = time.Now()
-	return nil
+	return dbs, p.Unlock
+}
+
+// GetFlushID returns ID of the last flush, written into the db.
+func GetFlushID(db ethdb.KeyValueReader) ([]byte, error) {
+	return db.Get(flagKey)
 }
 
 // IsFlushNeeded returns true if it's recommended to flush data to disk
@@ -184,7 +307,6 @@ func (p *SyncedPool) checkDbsSynced() error {
 	defer p.Unlock()
 
 	var (
-		key    = []byte("flag")
 		prevID *[]byte
 		descrs []string
 		list   = func() string {
@@ -194,7 +316,7 @@ func (p *SyncedPool) checkDbsSynced() error {
 	for name, w := range p.wrappers {
 		db := w.InitUnderlyingDb()
 
-		mark, err := db.Get(key)
+		mark, err := GetFlushID(db)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,230 @@
+package flushable
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/fallible"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/readonly"
+)
+
+const enough = 1000000000
+
+// diskLikeDb keeps data on Close, so it survives a simulated restart like a db on disk.
+type diskLikeDb struct {
+	kvdb.KeyValueStore
+}
+
+func (db *diskLikeDb) Close() error {
+	return nil
+}
+
+func (db *diskLikeDb) Drop() {
+	_ = db.KeyValueStore.Close()
+	db.KeyValueStore.Drop()
+}
+
+// fallibleProducer wraps db with the name into fallible.Fallible with the write limit.
+type fallibleProducer struct {
+	kvdb.DbProducer
+
+	name   string
+	writes int
+}
+
+func (p *fallibleProducer) OpenDb(name string) kvdb.KeyValueStore {
+	db := fallible.Wrap(&diskLikeDb{p.DbProducer.OpenDb(name)})
+	if name == p.name {
+		db.SetWriteCount(p.writes)
+	} else {
+		db.SetWriteCount(enough)
+	}
+	return db
+}
+
+func TestSyncedPoolJournalIsHidden(t *testing.T) {
+	assertar := assert.New(t)
+
+	mems := memorydb.NewProducer("")
+	pool := NewSyncedPool(mems)
+	assertar.NoError(pool.GetDb("a").Put([]byte("key"), []byte("val")))
+	assertar.NoError(pool.Flush([]byte("1")))
+	assertar.Contains(mems.Names(), journalName)
+
+	pool = NewSyncedPool(mems)
+	assertar.Equal(1, len(pool.wrappers))
+	assertar.Panics(func() {
+		pool.GetDb(journalName)
+	})
+}
+
+func TestSyncedPoolCloseAll(t *testing.T) {
+	assertar := assert.New(t)
+
+	producer := &fallibleProducer{
+		DbProducer: memorydb.NewProducer(""),
+		writes:     enough,
+	}
+	pool := NewSyncedPool(producer)
+	a := pool.GetDb("a")
+	b := pool.GetDb("b")
+	assertar.NoError(a.Put([]byte("key"), []byte("val")))
+	assertar.NoError(b.Put([]byte("key"), []byte("val")))
+	assertar.NoError(pool.Flush([]byte("1")))
+	assertar.NoError(b.Close())
+
+	assertar.NoError(pool.CloseAll())
+	assertar.Nil(pool.journal)
+	_, err := a.Get([]byte("key"))
+	assertar.Equal(errClosed, err)
+
+	pool = NewSyncedPool(producer)
+	val, err := pool.GetDb("a").Get([]byte("key"))
+	assertar.NoError(err)
+	assertar.Equal([]byte("val"), val)
+}
+
+func TestReadOnlySyncedPoolSkipsJournal(t *testing.T) {
+	assertar := assert.New(t)
+
+	producer := &fallibleProducer{
+		DbProducer: memorydb.NewProducer(""),
+		writes:     enough,
+	}
+	pool := NewSyncedPool(producer)
+	assertar.NoError(pool.GetDb("a").Put([]byte("key"), []byte("val")))
+	assertar.NoError(pool.Flush([]byte("1")))
+
+	// interrupted flush, before any db is touched
+	assertar.NoError(pool.writeJournal(&journalRecord{
+		ID: []byte("2"),
+		Dbs: []journalDb{{
+			Name:  "a",
+			Pairs: []journalPair{{Key: []byte("key"), Val: []byte("new")}},
+		}},
+	}))
+
+	pool = NewReadOnlySyncedPool(readonly.WrapProducer(producer))
+	val, err := pool.GetDb("a").Get([]byte("key"))
+	assertar.NoError(err)
+	assertar.Equal([]byte("val"), val)
+
+	// rolled forward in read-write mode
+	pool = NewSyncedPool(producer)
+	val, err = pool.GetDb("a").Get([]byte("key"))
+	assertar.NoError(err)
+	assertar.Equal([]byte("new"), val)
+}
+
+func TestSyncedPoolFlushInterrupted(t *testing.T) {
+	for _, name := range []string{"a", "b", "c", "d", journalName} {
+		for writes := 0; ; writes++ {
+			if !testSyncedPoolFlushInterrupted(t, name, writes) {
+				break
+			}
+			if t.Failed() {
+				return
+			}
+		}
+	}
+}
+
+// testSyncedPoolFlushInterrupted checks that all the dbs are in the same state after
+// the flush is interrupted by n-th write into db with the name.
+func testSyncedPoolFlushInterrupted(t *testing.T, name string, writes int) (interrupted bool) {
+	assertar := assert.New(t)
+	descr := fmt.Sprintf("interrupted at %d write into %s", writes, name)
+
+	namespace := fmt.Sprintf("flushable.TestSyncedPoolFlushInterrupted-%s-%d-%d", name, writes, rand.Int())
+	producer := &fallibleProducer{
+		DbProducer: memorydb.NewProducer(namespace),
+		writes:     enough,
+	}
+
+	var (
+		key = []byte("key")
+		del = []byte("del")
+	)
+
+	// state 1
+	pool := NewSyncedPool(producer)
+	assertar.NoError(pool.GetDb("a").Put(key, []byte("a1")))
+	assertar.NoError(pool.GetDb("a").Put(del, []byte("a1")))
+	assertar.NoError(pool.GetDb("b").Put(key, []byte("b1")))
+	assertar.NoError(pool.GetDb("d").Put(key, []byte("d1")))
+	assertar.NoError(pool.Flush([]byte("1")))
+
+	// state 2
+	assertar.NoError(pool.GetDb("a").Put(key, []byte("a2")))
+	assertar.NoError(pool.GetDb("a").Delete(del))
+	assertar.NoError(pool.GetDb("b").Put(key, []byte("b2")))
+	assertar.NoError(pool.GetDb("c").Put(key, []byte("c2")))
+	d := pool.GetDb("d")
+	assertar.NoError(d.Close())
+	d.Drop()
+
+	// arm fault for the opened and not opened yet dbs
+	producer.name, producer.writes = name, writes
+	for n, w := range pool.wrappers {
+		if n == name && w.underlying != devnull {
+			w.underlying.(*fallible.Fallible).SetWriteCount(writes)
+		}
+	}
+	if name == journalName {
+		pool.journal.(*fallible.Fallible).SetWriteCount(writes)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				interrupted = true
+			}
+		}()
+		assertar.NoError(pool.Flush([]byte("2")), descr)
+	}()
+
+	// restart
+	pool = NewSyncedPool(&fallibleProducer{
+		DbProducer: memorydb.NewProducer(namespace),
+		writes:     enough,
+	})
+
+	get := func(db, key string) string {
+		val, err := pool.GetDb(db).Get([]byte(key))
+		assertar.NoError(err, descr)
+		return string(val)
+	}
+	names := make(map[string]bool)
+	for _, n := range producer.Names() {
+		names[n] = true
+	}
+
+	switch get("a", "flag") {
+	case "1":
+		assertar.True(interrupted, descr)
+		assertar.Equal("a1", get("a", "key"), descr)
+		assertar.Equal("a1", get("a", "del"), descr)
+		assertar.Equal("1", get("b", "flag"), descr)
+		assertar.Equal("b1", get("b", "key"), descr)
+		assertar.Equal("", get("c", "key"), descr)
+		assertar.True(names["d"], descr)
+		assertar.Equal("d1", get("d", "key"), descr)
+	case "2":
+		assertar.Equal("a2", get("a", "key"), descr)
+		assertar.Equal("", get("a", "del"), descr)
+		assertar.Equal("2", get("b", "flag"), descr)
+		assertar.Equal("b2", get("b", "key"), descr)
+		assertar.Equal("2", get("c", "flag"), descr)
+		assertar.Equal("c2", get("c", "key"), descr)
+		assertar.False(names["d"], descr)
+	default:
+		assertar.Fail("unexpected flush id", descr)
+	}
+
+	return
+}
@@ -0,0 +1,185 @@
+package flushable
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	rbt "github.com/emirpasic/gods/trees/redblacktree"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	errUndoRecording = errors.New("undo is being recorded")
+)
+
+// Undo is a record of the previous values of the keys, which were modified in the dbs
+// between SyncedPool.StartUndo and SyncedPool.StopUndo. Applying it reverts the modifications.
+type Undo struct {
+	Dbs []journalDb
+}
+
+// Empty returns true if nothing was modified.
+func (u *Undo) Empty() bool {
+	return len(u.Dbs) == 0
+}
+
+// UndoScope is the set of the recorded tables, the key prefixes by db names.
+// A db without prefixes is recorded entirely, the dbs out of the scope aren't recorded.
+type UndoScope map[string][][]byte
+
+// Add adds the tables of the db into the scope. Without prefixes, the whole db is added.
+func (s UndoScope) Add(db string, prefixes ...[]byte) {
+	s[db] = append(s[db], prefixes...)
+}
+
+// startUndo begins recording of the previous values of the keys with the prefixes,
+// or of all the keys if there are no prefixes. Caller must hold the lock.
+func (w *Flushable) startUndo(prefixes [][]byte) {
+	w.undo = rbt.NewWithStringComparator()
+	w.undoPrefixes = prefixes
+}
+
+// stopUndo stops recording and returns the previous values of the modified keys. Caller must hold the lock.
+func (w *Flushable) stopUndo() []journalPair {
+	if w.undo == nil {
+		return nil
+	}
+
+	pairs := make([]journalPair, 0, w.undo.Size())
+	for it := w.undo.Iterator(); it.Next(); {
+		pair := journalPair{
+			Key: []byte(it.Key().(string)),
+		}
+		if it.Value() == nil {
+			pair.Del = true
+		} else {
+			pair.Val = it.Value().([]byte)
+		}
+		pairs = append(pairs, pair)
+	}
+	w.undo = nil
+	w.undoPrefixes = nil
+	return pairs
+}
+
+// isUndoRecorded returns true if the key is in the recorded tables. Caller must hold the lock.
+func (w *Flushable) isUndoRecorded(key []byte) bool {
+	if len(w.undoPrefixes) == 0 {
+		return true
+	}
+	for _, prefix := range w.undoPrefixes {
+		if bytes.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordUndo saves the previous value of the key, if it isn't saved yet. Caller must hold the lock.
+func (w *Flushable) recordUndo(key []byte) error {
+	if w.undo == nil || !w.isUndoRecorded(key) {
+		return nil
+	}
+	if _, ok := w.undo.Get(string(key)); ok {
+		return nil
+	}
+
+	var prev []byte
+	if entry, ok := w.modified.Get(string(key)); ok {
+		if entry != nil {
+			prev = common.CopyBytes(entry.([]byte))
+		}
+	} else {
+		var err error
+		prev, err = w.underlying.Get(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	if prev == nil {
+		w.undo.Put(string(key), nil)
+	} else {
+		w.undo.Put(string(key), prev)
+	}
+	return nil
+}
+
+// StartUndo begins recording of the previous values of the keys, which are modified in the scope,
+// including the dbs opened after the call. Writes out of the scope (e.g. concurrent ones) aren't recorded.
+func (p *SyncedPool) StartUndo(scope UndoScope) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.undo = scope
+	for name, w := range p.wrappers {
+		if prefixes, ok := scope[name]; ok {
+			w.lock.Lock()
+			w.startUndo(prefixes)
+			w.lock.Unlock()
+		}
+	}
+}
+
+// StopUndo stops recording and returns the record, which reverts all the modifications since StartUndo.
+func (p *SyncedPool) StopUndo() *Undo {
+	p.Lock()
+	defer p.Unlock()
+
+	p.undo = nil
+
+	names := make([]string, 0, len(p.wrappers))
+	for name := range p.wrappers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	u := &Undo{}
+	for _, name := range names {
+		w := p.wrappers[name]
+		w.lock.Lock()
+		pairs := w.stopUndo()
+		w.lock.Unlock()
+
+		if len(pairs) == 0 {
+			continue
+		}
+		u.Dbs = append(u.Dbs, journalDb{
+			Name:  name,
+			Pairs: pairs,
+		})
+	}
+	return u
+}
+
+// ApplyUndo reverts the modifications recorded by the undo record.
+// Records must be applied in the reversed order of their recording.
+// As any other write, it isn't written on disk until flush.
+func (p *SyncedPool) ApplyUndo(u *Undo) error {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.undo != nil {
+		return errUndoRecording
+	}
+
+	for _, jdb := range u.Dbs {
+		w := p.getWrapper(jdb.Name)
+		w.lock.Lock()
+		for _, pair := range jdb.Pairs {
+			var err error
+			if pair.Del {
+				err = w.delete(pair.Key)
+			} else {
+				err = w.put(pair.Key, pair.Val)
+			}
+			if err != nil {
+				w.lock.Unlock()
+				return err
+			}
+		}
+		w.lock.Unlock()
+	}
+	return nil
+}
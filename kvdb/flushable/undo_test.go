@@ -0,0 +1,98 @@
+package flushable
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+)
+
+func TestSyncedPoolUndo(t *testing.T) {
+	assertar := assert.New(t)
+
+	pool := NewSyncedPool(memorydb.NewProducer(""))
+	a := pool.GetDb("a")
+	// flushed and not flushed keys
+	assertar.NoError(a.Put([]byte("flushed"), []byte("1")))
+	assertar.NoError(a.Put([]byte("deleted"), []byte("1")))
+	assertar.NoError(pool.Flush([]byte("1")))
+	assertar.NoError(a.Put([]byte("cached"), []byte("1")))
+
+	type kv map[string]string
+	dump := func() map[string]kv {
+		res := make(map[string]kv)
+		for _, name := range []string{"a", "b"} {
+			res[name] = kv{}
+			it := pool.GetDb(name).NewIterator()
+			for it.Next() {
+				if string(it.Key()) == string(flagKey) {
+					continue
+				}
+				res[name][string(it.Key())] = string(it.Value())
+			}
+			it.Release()
+		}
+		return res
+	}
+	before := dump()
+
+	// db "b" is recorded partially, db "d" isn't recorded
+	scope := UndoScope{}
+	scope.Add("a")
+	scope.Add("b", []byte("ne"), []byte("x"))
+	scope.Add("c")
+
+	var undos []*Undo
+	for i := 0; i < 3; i++ {
+		pool.StartUndo(scope)
+		assertar.NoError(a.Put([]byte("flushed"), []byte{byte('2' + i)}))
+		assertar.NoError(a.Put([]byte("cached"), []byte{byte('2' + i)}))
+		assertar.NoError(a.Put([]byte("new"), []byte{byte('2' + i)}))
+		assertar.NoError(a.Delete([]byte("deleted")))
+		assertar.NoError(pool.GetDb("b").Put([]byte("new"), []byte{byte('2' + i)}))
+		assertar.NoError(pool.GetDb("b").Put([]byte("out"), []byte{byte('2' + i)}))
+		assertar.NoError(pool.GetDb("d").Put([]byte("out"), []byte{byte('2' + i)}))
+		// db opened while recording
+		assertar.NoError(pool.GetDb("c").Put([]byte("new"), []byte{byte('2' + i)}))
+		batch := a.NewBatch()
+		assertar.NoError(batch.Put([]byte("batched"), []byte{byte('2' + i)}))
+		assertar.NoError(batch.Write())
+		undos = append(undos, pool.StopUndo())
+
+		if i == 1 {
+			assertar.NoError(pool.Flush([]byte("2")))
+		}
+	}
+	assertar.NotEqual(before, dump())
+
+	// not modified
+	pool.StartUndo(scope)
+	assertar.True(pool.StopUndo().Empty())
+
+	for i := len(undos) - 1; i >= 0; i-- {
+		// undo is stored as RLP
+		buf, err := rlp.EncodeToBytes(undos[i])
+		assertar.NoError(err)
+		u := &Undo{}
+		assertar.NoError(rlp.DecodeBytes(buf, u))
+
+		assertar.NoError(pool.ApplyUndo(u))
+	}
+	after := dump()
+	assertar.Equal(kv{"out": "4"}, after["b"])
+	delete(after["b"], "out")
+	assertar.Equal(before, after)
+	val, err := pool.GetDb("c").Get([]byte("new"))
+	assertar.NoError(err)
+	assertar.Nil(val)
+	val, err = pool.GetDb("d").Get([]byte("out"))
+	assertar.NoError(err)
+	assertar.Equal([]byte("4"), val)
+
+	// not applied while recording
+	pool.StartUndo(scope)
+	assertar.Equal(errUndoRecording, pool.ApplyUndo(undos[0]))
+	pool.StopUndo()
+}
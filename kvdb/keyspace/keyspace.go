@@ -0,0 +1,148 @@
+// Package keyspace reflects over layouts of db tables and reports key-space usage of databases.
+package keyspace
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"reflect"
+)
+
+type (
+	// Table is a key range of db, declared with `table:"prefix"` struct tag.
+	Table struct {
+		Name   string
+		Prefix string
+		Tables []*Table // nested tables, prefixes are relative
+	}
+
+	// Db is a layout of the dbs which names match the pattern (see path.Match).
+	Db struct {
+		Pattern string
+		Tables  []*Table
+	}
+
+	// Schema is a layout of all the node dbs.
+	Schema []Db
+)
+
+// Tables reflects over the struct fields with `table:"prefix"` tag (see table.MigrateTables).
+// Struct may be passed by value, by pointer or as reflect.Type.
+func Tables(s interface{}) []*Table {
+	t, ok := s.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(s)
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var tables []*Table
+	for i := 0; i < t.NumField(); i++ {
+		if prefix := t.Field(i).Tag.Get("table"); prefix != "" && prefix != "-" {
+			tables = append(tables, &Table{
+				Name:   t.Field(i).Name,
+				Prefix: prefix,
+			})
+		}
+	}
+	return tables
+}
+
+// FieldTables reflects over the tables struct, which is the field of the object.
+// The field may be unexported, so obj may be just a typed nil pointer.
+func FieldTables(obj interface{}, field string) []*Table {
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	f, ok := t.FieldByName(field)
+	if !ok {
+		panic(fmt.Sprintf("%s has no field %s", t.String(), field))
+	}
+	return Tables(f.Type)
+}
+
+// Nest sets sub-tables of the table with the prefix. Returns the same tables.
+func Nest(tables []*Table, prefix string, sub []*Table) []*Table {
+	for _, t := range tables {
+		if t.Prefix == prefix {
+			t.Tables = sub
+			return tables
+		}
+	}
+	panic("no table with prefix " + prefix)
+}
+
+// Flatten returns the tables together with all the nested ones.
+// Nested tables are named like "Parent/Child" and have full prefixes.
+func Flatten(tables []*Table) []*Table {
+	return flatten("", "", tables)
+}
+
+func flatten(prefix, name string, tables []*Table) []*Table {
+	var res []*Table
+	for _, t := range tables {
+		flat := &Table{
+			Name:   name + t.Name,
+			Prefix: prefix + t.Prefix,
+		}
+		res = append(res, flat)
+		res = append(res, flatten(flat.Prefix, flat.Name+"/", t.Tables)...)
+	}
+	return res
+}
+
+// Find returns tables of the db with the name, or nil if the db is unknown.
+func (s Schema) Find(name string) []*Table {
+	db := s.FindDb(name)
+	if db == nil {
+		return nil
+	}
+	return db.Tables
+}
+
+// FindDb returns layout of the db with the name, or nil if the db is unknown.
+func (s Schema) FindDb(name string) *Db {
+	for i, db := range s {
+		if ok, _ := path.Match(db.Pattern, name); ok {
+			return &s[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks that tables of every db don't share key-space.
+func (s Schema) Validate() error {
+	for _, db := range s {
+		err := validate(db.Tables)
+		if err != nil {
+			return fmt.Errorf("db %s: %v", db.Pattern, err)
+		}
+	}
+	return nil
+}
+
+// validate tables the same way as table.MigrateTables does: prefixes are compared by the min length.
+func validate(tables []*Table) error {
+	minLen := 0
+	for i, t := range tables {
+		if i == 0 || len(t.Prefix) < minLen {
+			minLen = len(t.Prefix)
+		}
+	}
+
+	for i, a := range tables {
+		for _, b := range tables[i+1:] {
+			if bytes.Equal([]byte(a.Prefix)[:minLen], []byte(b.Prefix)[:minLen]) {
+				return fmt.Errorf("prefixes of %s '%s' and %s '%s' are the same", a.Name, a.Prefix, b.Name, b.Prefix)
+			}
+		}
+		err := validate(a.Tables)
+		if err != nil {
+			return fmt.Errorf("%s: %v", a.Name, err)
+		}
+	}
+	return nil
+}
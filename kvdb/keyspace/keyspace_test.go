@@ -0,0 +1,131 @@
+package keyspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/table"
+)
+
+type testStore struct {
+	table struct {
+		A kvdb.KeyValueStore `table:"a"`
+		B kvdb.KeyValueStore `table:"b"`
+		C kvdb.KeyValueStore `table:"-"`
+		D kvdb.KeyValueStore
+	}
+}
+
+type testSubStore struct {
+	table struct {
+		X kvdb.KeyValueStore `table:"x"`
+		Y kvdb.KeyValueStore `table:"yy"`
+	}
+}
+
+func testSchema() Schema {
+	return Schema{
+		{
+			Pattern: "main",
+			Tables: Nest(FieldTables((*testStore)(nil), "table"),
+				"b", FieldTables((*testSubStore)(nil), "table")),
+		},
+	}
+}
+
+func TestSchema(t *testing.T) {
+	assertar := assert.New(t)
+
+	s := testSchema()
+	assertar.Equal([]*Table{
+		{Name: "A", Prefix: "a"},
+		{Name: "B", Prefix: "b", Tables: []*Table{
+			{Name: "X", Prefix: "x"},
+			{Name: "Y", Prefix: "yy"},
+		}},
+	}, s.Find("main"))
+	assertar.Nil(s.Find("unknown"))
+	assertar.NoError(s.Validate())
+
+	s = append(s, Db{
+		Pattern: "epoch-*",
+		Tables: []*Table{
+			{Name: "A", Prefix: "a"},
+			{Name: "AB", Prefix: "ab"},
+		},
+	})
+	assertar.Equal("AB", s.Find("epoch-1")[1].Name)
+	assertar.Error(s.Validate())
+
+	assertar.Panics(func() {
+		FieldTables((*testStore)(nil), "unknown")
+	})
+}
+
+func TestCollect(t *testing.T) {
+	assertar := assert.New(t)
+
+	s := &testStore{}
+	sub := &testSubStore{}
+	db := memorydb.New()
+	table.MigrateTables(&s.table, db)
+	table.MigrateTables(&sub.table, s.table.B)
+
+	put := func(t kvdb.KeyValueStore, key, val string) {
+		assertar.NoError(t.Put([]byte(key), []byte(val)))
+	}
+	put(s.table.A, "1", "1")
+	put(s.table.A, "2", "22")
+	put(s.table.A, "3", "333")
+	put(s.table.B, "z", "")
+	put(sub.table.Y, "1", "1")
+	put(db, "c", "1")
+
+	stats, err := Collect("main", db, testSchema().Find("main"), 2)
+	assertar.NoError(err)
+	assertar.Equal("main", stats.Db)
+
+	byName := make(map[string]*TableStats)
+	for _, t := range stats.Tables {
+		byName[t.Table] = t
+	}
+	assertar.Equal(5, len(byName))
+
+	a := byName["A"]
+	assertar.Equal(stats.Tables[0], a)
+	assertar.Equal(uint64(3), a.Keys)
+	assertar.Equal(uint64(6), a.KeyBytes)
+	assertar.Equal(uint64(6), a.ValueBytes)
+	assertar.Equal([]PairStat{
+		{Key: []byte("a3"), Size: 5},
+		{Key: []byte("a2"), Size: 4},
+	}, a.Largest)
+
+	assertar.Equal(uint64(1), byName["B"].Keys)
+	assertar.Equal(uint64(0), byName["B/X"].Keys)
+	assertar.Equal(uint64(1), byName["B/Y"].Keys)
+	assertar.Equal([]byte("byy"), []byte(byName["B/Y"].Prefix))
+	assertar.Equal(uint64(1), byName[""].Keys)
+}
+
+func TestCollectIterator(t *testing.T) {
+	assertar := assert.New(t)
+
+	s := &testStore{}
+	db := memorydb.New()
+	table.MigrateTables(&s.table, db)
+	assertar.NoError(s.table.A.Put([]byte("1"), []byte("1")))
+
+	// the pairs written after the iterator is taken aren't counted
+	it := db.NewIterator()
+	defer it.Release()
+	assertar.NoError(s.table.A.Put([]byte("2"), []byte("2")))
+
+	stats, err := CollectIterator("main", it, testSchema().Find("main"), 0)
+	assertar.NoError(err)
+	assertar.Equal("A", stats.Tables[0].Table)
+	assertar.Equal(uint64(1), stats.Tables[0].Keys)
+}
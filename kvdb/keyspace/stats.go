@@ -0,0 +1,109 @@
+package keyspace
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+type (
+	// PairStat is a size of the key-value pair.
+	PairStat struct {
+		Key  hexutil.Bytes `json:"key"`
+		Size uint64        `json:"size"`
+	}
+
+	// TableStats is a key-space usage of the table.
+	TableStats struct {
+		Table      string        `json:"table"` // full name, like "EvmLogs/Topic", or "" for keys out of the tables
+		Prefix     hexutil.Bytes `json:"prefix"`
+		Keys       uint64        `json:"keys"`
+		KeyBytes   uint64        `json:"keyBytes"`
+		ValueBytes uint64        `json:"valueBytes"`
+		Largest    []PairStat    `json:"largest"` // the largest pairs, by size desc
+	}
+
+	// DbStats is a key-space usage of the db by tables.
+	DbStats struct {
+		Db     string        `json:"db"`
+		Tables []*TableStats `json:"tables"` // by size desc
+	}
+)
+
+// Size of the table keys and values.
+func (t *TableStats) Size() uint64 {
+	return t.KeyBytes + t.ValueBytes
+}
+
+// Collect walks the db and counts key-value pairs by the tables. Each pair is counted by the
+// table with the longest prefix. top is a number of the largest pairs to report for each table.
+func Collect(name string, db ethdb.Iteratee, tables []*Table, top int) (*DbStats, error) {
+	it := db.NewIterator()
+	defer it.Release()
+	return CollectIterator(name, it, tables, top)
+}
+
+// CollectIterator is Collect over the iterator of the whole db. The iterator isn't released.
+func CollectIterator(name string, it ethdb.Iterator, tables []*Table, top int) (*DbStats, error) {
+	stats := &DbStats{
+		Db: name,
+	}
+	for _, t := range Flatten(tables) {
+		stats.Tables = append(stats.Tables, &TableStats{
+			Table:  t.Name,
+			Prefix: []byte(t.Prefix),
+		})
+	}
+	unknown := &TableStats{}
+	stats.Tables = append(stats.Tables, unknown)
+
+	// longer prefixes first, so the first match is the longest one
+	byPrefix := make([]*TableStats, len(stats.Tables))
+	copy(byPrefix, stats.Tables)
+	sort.SliceStable(byPrefix, func(i, j int) bool {
+		return len(byPrefix[i].Prefix) > len(byPrefix[j].Prefix)
+	})
+
+	for it.Next() {
+		key, val := it.Key(), it.Value()
+		for _, t := range byPrefix {
+			if bytes.HasPrefix(key, t.Prefix) {
+				t.add(key, val, top)
+				break
+			}
+		}
+	}
+	if it.Error() != nil {
+		return nil, it.Error()
+	}
+
+	sort.SliceStable(stats.Tables, func(i, j int) bool {
+		return stats.Tables[i].Size() > stats.Tables[j].Size()
+	})
+	return stats, nil
+}
+
+func (t *TableStats) add(key, val []byte, top int) {
+	t.Keys++
+	t.KeyBytes += uint64(len(key))
+	t.ValueBytes += uint64(len(val))
+
+	size := uint64(len(key) + len(val))
+	if top <= 0 || (len(t.Largest) >= top && t.Largest[len(t.Largest)-1].Size >= size) {
+		return
+	}
+	pos := sort.Search(len(t.Largest), func(i int) bool {
+		return t.Largest[i].Size < size
+	})
+	t.Largest = append(t.Largest, PairStat{})
+	copy(t.Largest[pos+1:], t.Largest[pos:])
+	t.Largest[pos] = PairStat{
+		Key:  append([]byte{}, key...),
+		Size: size,
+	}
+	if len(t.Largest) > top {
+		t.Largest = t.Largest[:top]
+	}
+}
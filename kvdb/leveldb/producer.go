@@ -10,7 +10,8 @@ import (
 )
 
 type producer struct {
-	datadir string
+	datadir  string
+	readonly bool
 }
 
 // NewProducer of level db.
@@ -20,6 +21,14 @@ func NewProducer(datadir string) kvdb.DbProducer {
 	}
 }
 
+// NewReadOnlyProducer of level db. It opens only existing dbs, in read-only mode.
+func NewReadOnlyProducer(datadir string) kvdb.DbProducer {
+	return &producer{
+		datadir:  datadir,
+		readonly: true,
+	}
+}
+
 // Names of existing databases.
 func (p *producer) Names() []string {
 	var names []string
@@ -47,9 +56,11 @@ func (p *producer) OpenDb(name string) kvdb.KeyValueStore {
 	dir := name + "-ldb"
 	path := filepath.Join(p.datadir, dir)
 
-	err := os.MkdirAll(path, 0700)
-	if err != nil {
-		panic(err)
+	if !p.readonly {
+		err := os.MkdirAll(path, 0700)
+		if err != nil {
+			panic(err)
+		}
 	}
 
 	var stopWatcher func()
@@ -67,7 +78,11 @@ func (p *producer) OpenDb(name string) kvdb.KeyValueStore {
 
 	}
 
-	db, err := New(path, 64, 0, "", onClose, onDrop)
+	newDb := New
+	if p.readonly {
+		newDb = NewReadOnly
+	}
+	db, err := newDb(path, 64, 0, "", onClose, onDrop)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,218 @@
+// Package metered provides kvdb wrappers which record read/write metrics of the db tables.
+package metered
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/keyspace"
+)
+
+// otherTable is a name of metrics of the keys out of the known tables.
+const otherTable = "other"
+
+type (
+	// tableMetrics of the table. Metrics of the batch writes are reported only by the write meter.
+	tableMetrics struct {
+		get       metrics.Timer // Get and Has calls
+		put       metrics.Timer
+		delete    metrics.Timer
+		iterators metrics.Meter // number of the opened iterators
+		iterated  metrics.Meter // number of the iterated pairs
+		read      metrics.Meter // bytes of read keys and values
+		write     metrics.Meter // bytes of written keys and values
+	}
+
+	tablePrefix struct {
+		prefix  []byte
+		metrics *tableMetrics
+	}
+
+	// classifier finds table of the key by the longest prefix.
+	classifier struct {
+		byFirstByte [256][]tablePrefix // by prefix length desc
+		other       *tableMetrics
+	}
+)
+
+func newTableMetrics(name string) *tableMetrics {
+	return &tableMetrics{
+		get:       metrics.GetOrRegisterTimer(name+"/get", nil),
+		put:       metrics.GetOrRegisterTimer(name+"/put", nil),
+		delete:    metrics.GetOrRegisterTimer(name+"/delete", nil),
+		iterators: metrics.GetOrRegisterMeter(name+"/iterators", nil),
+		iterated:  metrics.GetOrRegisterMeter(name+"/iterated", nil),
+		read:      metrics.GetOrRegisterMeter(name+"/read", nil),
+		write:     metrics.GetOrRegisterMeter(name+"/write", nil),
+	}
+}
+
+func newClassifier(namespace string, tables []*keyspace.Table) *classifier {
+	c := &classifier{
+		other: newTableMetrics(namespace + otherTable),
+	}
+	for _, t := range keyspace.Flatten(tables) {
+		if len(t.Prefix) == 0 {
+			continue
+		}
+		first := t.Prefix[0]
+		c.byFirstByte[first] = append(c.byFirstByte[first], tablePrefix{
+			prefix:  []byte(t.Prefix),
+			metrics: newTableMetrics(namespace + t.Name),
+		})
+	}
+	for _, pp := range c.byFirstByte {
+		sort.SliceStable(pp, func(i, j int) bool {
+			return len(pp[i].prefix) > len(pp[j].prefix)
+		})
+	}
+	return c
+}
+
+func (c *classifier) table(key []byte) *tableMetrics {
+	if len(key) == 0 {
+		return c.other
+	}
+	for _, t := range c.byFirstByte[key[0]] {
+		if bytes.HasPrefix(key, t.prefix) {
+			return t.metrics
+		}
+	}
+	return c.other
+}
+
+// Store is a kvdb.KeyValueStore wrapper around any kvdb.KeyValueStore.
+// It records counts, bytes and latencies of the db operations, by the tables.
+type Store struct {
+	kvdb.KeyValueStore
+
+	tables *classifier
+	batch  metrics.Timer
+}
+
+// Wrap returns a metered kvdb.KeyValueStore. Metrics are named like "kvdb/{name}/{table}/{metric}",
+// keys out of the tables are reported as "other" table.
+func Wrap(db kvdb.KeyValueStore, name string, tables []*keyspace.Table) *Store {
+	namespace := "kvdb/" + metricName(name) + "/"
+	return &Store{
+		KeyValueStore: db,
+		tables:        newClassifier(namespace, tables),
+		batch:         metrics.GetOrRegisterTimer(namespace+"batch", nil),
+	}
+}
+
+// metricName makes db name (or names pattern, like "poset-epoch-*") suitable for metrics.
+func metricName(name string) string {
+	name = strings.Replace(name, "*", "", -1)
+	name = strings.Replace(name, "-", "_", -1)
+	return strings.Trim(name, "_")
+}
+
+// Has retrieves if a key is present in the key-value data store.
+func (s *Store) Has(key []byte) (bool, error) {
+	start := time.Now()
+	has, err := s.KeyValueStore.Has(key)
+	s.tables.table(key).get.UpdateSince(start)
+	return has, err
+}
+
+// Get retrieves the given key if it's present in the key-value data store.
+func (s *Store) Get(key []byte) ([]byte, error) {
+	start := time.Now()
+	val, err := s.KeyValueStore.Get(key)
+	m := s.tables.table(key)
+	m.get.UpdateSince(start)
+	m.read.Mark(int64(len(key) + len(val)))
+	return val, err
+}
+
+// Put inserts the given value into the key-value data store.
+func (s *Store) Put(key []byte, value []byte) error {
+	start := time.Now()
+	err := s.KeyValueStore.Put(key, value)
+	m := s.tables.table(key)
+	m.put.UpdateSince(start)
+	m.write.Mark(int64(len(key) + len(value)))
+	return err
+}
+
+// Delete removes the key from the key-value data store.
+func (s *Store) Delete(key []byte) error {
+	start := time.Now()
+	err := s.KeyValueStore.Delete(key)
+	s.tables.table(key).delete.UpdateSince(start)
+	return err
+}
+
+// NewBatch creates a write-only database that buffers changes to its host db
+// until a final write is called.
+func (s *Store) NewBatch() ethdb.Batch {
+	return &batch{
+		Batch: s.KeyValueStore.NewBatch(),
+		store: s,
+	}
+}
+
+// NewIterator creates a binary-alphabetical iterator over the entire keyspace
+// contained within the key-value database.
+func (s *Store) NewIterator() ethdb.Iterator {
+	s.tables.other.iterators.Mark(1)
+	return &iterator{s.KeyValueStore.NewIterator(), s}
+}
+
+// NewIteratorWithStart creates a binary-alphabetical iterator over a subset of
+// database content starting at a particular initial key (or after, if it does
+// not exist).
+func (s *Store) NewIteratorWithStart(start []byte) ethdb.Iterator {
+	s.tables.table(start).iterators.Mark(1)
+	return &iterator{s.KeyValueStore.NewIteratorWithStart(start), s}
+}
+
+// NewIteratorWithPrefix creates a binary-alphabetical iterator over a subset
+// of database content with a particular key prefix.
+func (s *Store) NewIteratorWithPrefix(prefix []byte) ethdb.Iterator {
+	s.tables.table(prefix).iterators.Mark(1)
+	return &iterator{s.KeyValueStore.NewIteratorWithPrefix(prefix), s}
+}
+
+type batch struct {
+	ethdb.Batch
+	store *Store
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *batch) Put(key, value []byte) error {
+	b.store.tables.table(key).write.Mark(int64(len(key) + len(value)))
+	return b.Batch.Put(key, value)
+}
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	start := time.Now()
+	err := b.Batch.Write()
+	b.store.batch.UpdateSince(start)
+	return err
+}
+
+type iterator struct {
+	ethdb.Iterator
+	store *Store
+}
+
+// Next moves the iterator to the next key/value pair.
+func (it *iterator) Next() bool {
+	if !it.Iterator.Next() {
+		return false
+	}
+	key := it.Iterator.Key()
+	m := it.store.tables.table(key)
+	m.iterated.Mark(1)
+	m.read.Mark(int64(len(key) + len(it.Iterator.Value())))
+	return true
+}
@@ -0,0 +1,76 @@
+package metered
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb/keyspace"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+)
+
+func TestStore(t *testing.T) {
+	assertar := assert.New(t)
+
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() {
+		metrics.Enabled = enabled
+	}()
+
+	tables := []*keyspace.Table{
+		{Name: "A", Prefix: "a"},
+		{Name: "B", Prefix: "b", Tables: []*keyspace.Table{
+			{Name: "X", Prefix: "x"},
+		}},
+	}
+	schema := keyspace.Schema{{Pattern: "test-epoch-*", Tables: tables}}
+	producer := WrapProducer(memorydb.NewProducer(""), schema)
+	db := producer.OpenDb("test-epoch-1")
+
+	timer := func(name string) int64 {
+		return metrics.DefaultRegistry.Get("kvdb/test_epoch/" + name).(metrics.Timer).Count()
+	}
+	meter := func(name string) int64 {
+		return metrics.DefaultRegistry.Get("kvdb/test_epoch/" + name).(metrics.Meter).Count()
+	}
+
+	assertar.NoError(db.Put([]byte("a1"), []byte("111")))
+	assertar.NoError(db.Put([]byte("bx1"), []byte("1")))
+	assertar.NoError(db.Put([]byte("b1"), []byte("1")))
+	assertar.NoError(db.Put([]byte("c"), []byte("1")))
+	assertar.Equal(int64(1), timer("A/put"))
+	assertar.Equal(int64(5), meter("A/write"))
+	assertar.Equal(int64(1), timer("B/put"))
+	assertar.Equal(int64(1), timer("B/X/put"))
+	assertar.Equal(int64(1), timer("other/put"))
+
+	_, err := db.Get([]byte("a1"))
+	assertar.NoError(err)
+	_, err = db.Has([]byte("a2"))
+	assertar.NoError(err)
+	assertar.Equal(int64(2), timer("A/get"))
+	assertar.Equal(int64(5), meter("A/read"))
+
+	assertar.NoError(db.Delete([]byte("bx1")))
+	assertar.Equal(int64(1), timer("B/X/delete"))
+
+	batch := db.NewBatch()
+	assertar.NoError(batch.Put([]byte("a2"), []byte("2")))
+	assertar.NoError(batch.Write())
+	assertar.Equal(int64(5+3), meter("A/write"))
+	assertar.Equal(int64(1), timer("batch"))
+
+	it := db.NewIteratorWithPrefix([]byte("a"))
+	for it.Next() {
+	}
+	it.Release()
+	assertar.Equal(int64(1), meter("A/iterators"))
+	assertar.Equal(int64(2), meter("A/iterated"))
+
+	// reopened db of the same layout shares metrics
+	db = producer.OpenDb("test-epoch-2")
+	assertar.NoError(db.Put([]byte("a1"), []byte("1")))
+	assertar.Equal(int64(2), timer("A/put"))
+}
@@ -0,0 +1,38 @@
+package metered
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/keyspace"
+)
+
+// producer is a kvdb.DbProducer wrapper around any kvdb.DbProducer.
+// It opens metered dbs.
+type producer struct {
+	underlying kvdb.DbProducer
+	schema     keyspace.Schema
+}
+
+// WrapProducer returns a producer of metered dbs. Tables of the dbs are found in the schema.
+// Dbs of the same layout (like "poset-epoch-*") share the metrics.
+func WrapProducer(p kvdb.DbProducer, schema keyspace.Schema) kvdb.DbProducer {
+	return &producer{
+		underlying: p,
+		schema:     schema,
+	}
+}
+
+// Names of existing databases.
+func (p *producer) Names() []string {
+	return p.underlying.Names()
+}
+
+// OpenDb or create db with name.
+func (p *producer) OpenDb(name string) kvdb.KeyValueStore {
+	db := p.underlying.OpenDb(name)
+
+	layout := p.schema.FindDb(name)
+	if layout == nil {
+		return Wrap(db, name, nil)
+	}
+	return Wrap(db, layout.Pattern, layout.Tables)
+}
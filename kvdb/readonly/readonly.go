@@ -0,0 +1,115 @@
+// Package readonly provides kvdb wrappers which reject any data changes.
+package readonly
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+)
+
+// ErrReadOnly is returned on attempt to change read-only db.
+var ErrReadOnly = errors.New("database is read-only")
+
+// wrapper is a kvdb.KeyValueStore wrapper around any kvdb.KeyValueStore.
+// It passes reads to the underlying store and rejects writes.
+type wrapper struct {
+	kvdb.KeyValueStore
+}
+
+// Wrap returns a read-only kvdb.KeyValueStore.
+func Wrap(db kvdb.KeyValueStore) kvdb.KeyValueStore {
+	return &wrapper{db}
+}
+
+// Put is rejected.
+func (w *wrapper) Put(key []byte, value []byte) error {
+	return ErrReadOnly
+}
+
+// Delete is rejected.
+func (w *wrapper) Delete(key []byte) error {
+	return ErrReadOnly
+}
+
+// NewBatch returns a batch which rejects changes.
+func (w *wrapper) NewBatch() ethdb.Batch {
+	return &batch{}
+}
+
+// Compact is rejected, because it changes the db files.
+func (w *wrapper) Compact(start []byte, limit []byte) error {
+	return ErrReadOnly
+}
+
+// Drop is rejected, it panics because it can't return error.
+func (w *wrapper) Drop() {
+	panic(ErrReadOnly)
+}
+
+// batch rejects all the changes.
+type batch struct{}
+
+// Put is rejected.
+func (b *batch) Put(key, value []byte) error {
+	return ErrReadOnly
+}
+
+// Delete is rejected.
+func (b *batch) Delete(key []byte) error {
+	return ErrReadOnly
+}
+
+// ValueSize of the batch is always 0.
+func (b *batch) ValueSize() int {
+	return 0
+}
+
+// Write is rejected.
+func (b *batch) Write() error {
+	return ErrReadOnly
+}
+
+// Reset does nothing.
+func (b *batch) Reset() {}
+
+// Replay does nothing, batch is always empty.
+func (b *batch) Replay(w ethdb.KeyValueWriter) error {
+	return nil
+}
+
+// producer is a kvdb.DbProducer wrapper around any kvdb.DbProducer.
+// It opens read-only dbs.
+type producer struct {
+	underlying kvdb.DbProducer
+	existing   map[string]bool
+}
+
+// WrapProducer returns a producer of read-only dbs.
+// Databases which don't exist are opened as empty ones, to not create them.
+func WrapProducer(p kvdb.DbProducer) kvdb.DbProducer {
+	existing := make(map[string]bool)
+	for _, name := range p.Names() {
+		existing[name] = true
+	}
+
+	return &producer{
+		underlying: p,
+		existing:   existing,
+	}
+}
+
+// Names of existing databases.
+func (p *producer) Names() []string {
+	return p.underlying.Names()
+}
+
+// OpenDb opens existing db or an empty one, in read-only mode.
+func (p *producer) OpenDb(name string) kvdb.KeyValueStore {
+	if !p.existing[name] {
+		return Wrap(memorydb.New())
+	}
+	return Wrap(p.underlying.OpenDb(name))
+}
@@ -0,0 +1,53 @@
+package readonly
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+)
+
+func TestReadOnly(t *testing.T) {
+	assertar := assert.New(t)
+
+	var (
+		key = []byte("test-key")
+		val = []byte("test-value")
+	)
+
+	mems := memorydb.NewProducer("")
+	origin := mems.OpenDb("existing")
+	assertar.NoError(origin.Put(key, val))
+
+	producer := WrapProducer(mems)
+	db := producer.OpenDb("existing")
+
+	res, err := db.Get(key)
+	assertar.NoError(err)
+	assertar.Equal(val, res)
+
+	it := db.NewIterator()
+	assertar.True(it.Next())
+	assertar.Equal(key, it.Key())
+	it.Release()
+
+	assertar.Equal(ErrReadOnly, db.Put(key, []byte("new")))
+	assertar.Equal(ErrReadOnly, db.Delete(key))
+	batch := db.NewBatch()
+	assertar.Equal(ErrReadOnly, batch.Put(key, []byte("new")))
+	assertar.Equal(ErrReadOnly, batch.Write())
+	assertar.Panics(db.Drop)
+
+	res, err = origin.Get(key)
+	assertar.NoError(err)
+	assertar.Equal(val, res)
+
+	// not existing db isn't created
+	db = producer.OpenDb("new")
+	res, err = db.Get(key)
+	assertar.NoError(err)
+	assertar.Nil(res)
+	assertar.Equal(ErrReadOnly, db.Put(key, val))
+	assertar.Equal([]string{"existing"}, producer.Names())
+}
@@ -45,6 +45,15 @@ func (t Table) NewTable(prefix []byte) *Table {
 	return &Table{t.db, prefix}
 }
 
+// Prefixes returns the key prefixes of the tables in the underlying DB.
+func Prefixes(tables ...ethdb.KeyValueStore) [][]byte {
+	prefixes := make([][]byte, len(tables))
+	for i, t := range tables {
+		prefixes[i] = t.(*Table).prefix
+	}
+	return prefixes
+}
+
 func (t *Table) Close() error {
 	return nil
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/Fantom-foundation/go-lachesis/kvdb"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/badgerdb"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/leveldb"
 	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
@@ -36,6 +37,26 @@ func tempLevelDB(name string) *leveldb.Database {
 	return diskdb
 }
 
+func tempBadgerDB(name string) *badgerdb.Database {
+	dir, err := ioutil.TempDir("", "flushable-test"+name)
+	if err != nil {
+		panic(fmt.Sprintf("can't create temporary directory: %v", err))
+	}
+
+	drop := func() {
+		err := os.RemoveAll(dir)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	diskdb, err := badgerdb.New(dir, nil, drop)
+	if err != nil {
+		panic(fmt.Sprintf("can't create temporary database: %v", err))
+	}
+	return diskdb
+}
+
 func TestTable(t *testing.T) {
 	prefix0 := map[string][]byte{
 		"00": []byte{0},
@@ -57,10 +78,20 @@ func TestTable(t *testing.T) {
 	defer leveldb2.Drop()
 	defer leveldb2.Close()
 
+	badgerdb1 := tempBadgerDB("3")
+	defer badgerdb1.Drop()
+	defer badgerdb1.Close()
+
+	badgerdb2 := tempBadgerDB("4")
+	defer badgerdb2.Drop()
+	defer badgerdb2.Close()
+
 	for name, db := range map[string]kvdb.KeyValueStore{
 		"memory":                       memorydb.New(),
 		"leveldb":                      leveldb1,
 		"cache-over-leveldb":           flushable.Wrap(leveldb2),
+		"badger":                       badgerdb1,
+		"cache-over-badger":            flushable.Wrap(badgerdb2),
 		"cache-over-cache-over-memory": flushable.Wrap(memorydb.New()),
 	} {
 		t.Run(name, func(t *testing.T) {
@@ -1,6 +1,7 @@
 package lachesis
 
 import (
+	"fmt"
 	"math/big"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
 	"github.com/Fantom-foundation/go-lachesis/lachesis/params"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
 	"github.com/Fantom-foundation/go-lachesis/utils"
 	"github.com/Fantom-foundation/go-lachesis/vector"
 )
@@ -46,6 +48,25 @@ type DagConfig struct {
 	VectorClockConfig vector.IndexConfig `json:"vectorClockConfig"`
 
 	MaxValidatorEventsInBlock idx.Event `json:"maxValidatorEventsInBlock"`
+
+	Election ElectionConfig `json:"election"`
+}
+
+// ElectionConfig defines the frame-decision strategy, see election.NewStrategy.
+// Zero value is the default strategy, the others are allowed only on fakenet, for research.
+type ElectionConfig struct {
+	RootsOrder    string `json:"rootsOrder"`    // order of Atropos candidates, election.RootsOrderStake by default
+	QuorumPercent uint32 `json:"quorumPercent"` // votes to decide a root, in percents of total stake. 2/3W+1 by default
+}
+
+// IsDefault returns true if the config is of the default strategy.
+func (c ElectionConfig) IsDefault() bool {
+	return (c.RootsOrder == "" || c.RootsOrder == election.RootsOrderStake) && c.QuorumPercent == 0
+}
+
+// Strategy returns the frame-decision strategy.
+func (c ElectionConfig) Strategy() (election.Strategy, error) {
+	return election.NewStrategy(c.RootsOrder, c.QuorumPercent)
 }
 
 // BlocksMissed is information about missed blocks from a staker
@@ -96,6 +117,17 @@ func (c *Config) EvmChainConfig() *ethparams.ChainConfig {
 	return &cfg
 }
 
+// CheckElection returns error if the election strategy is invalid or isn't allowed on the network.
+func (c *Config) CheckElection() error {
+	if _, err := c.Dag.Election.Strategy(); err != nil {
+		return err
+	}
+	if !c.Dag.Election.IsDefault() && c.NetworkID != FakeNetworkID {
+		return fmt.Errorf("custom election strategy isn't allowed on network %d, only on fakenet", c.NetworkID)
+	}
+	return nil
+}
+
 func MainNetConfig() Config {
 	return Config{
 		Name:      "main",
@@ -2,6 +2,7 @@ package prometheus
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
@@ -11,6 +12,11 @@ import (
 
 var logger = log.New("module", "prometheus")
 
+// rescanInterval is a period of collecting metrics registered after start, like metrics of lazily opened dbs.
+const rescanInterval = 10 * time.Second
+
+var collected = make(map[string]bool)
+
 // ListenTo serves prometheus connections.
 func ListenTo(endpoint string, reg metrics.Registry) {
 	if reg == nil {
@@ -18,6 +24,12 @@ func ListenTo(endpoint string, reg metrics.Registry) {
 	}
 	reg.Each(collect)
 
+	go func() {
+		for range time.Tick(rescanInterval) {
+			reg.Each(collect)
+		}
+	}()
+
 	go func() {
 		logger.Info("metrics server starts", "endpoint", endpoint)
 		defer logger.Info("metrics server is stopped")
@@ -33,7 +45,12 @@ func ListenTo(endpoint string, reg metrics.Registry) {
 	}()
 }
 
+// collect is called from the only goroutine at time.
 func collect(name string, metric interface{}) {
+	if collected[name] {
+		return
+	}
+	collected[name] = true
 	logger.Info("metric to prometheus", "metric", name)
 
 	collector, ok := convertToPrometheusMetric(name, metric)
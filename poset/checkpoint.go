@@ -47,7 +47,11 @@ func (p *Poset) Bootstrap(callback inter.ConsensusCallbacks) {
 	p.vecClock = vector.NewIndex(p.dag.VectorClockConfig, p.Validators, p.store.epochTable.VectorIndex, func(id hash.Event) *inter.EventHeaderData {
 		return p.input.GetEventHeader(p.EpochN, id)
 	})
-	p.election = election.New(p.Validators, p.LastDecidedFrame+1, p.vecClock.ForklessCause, p.store.GetFrameRoots)
+	strategy, err := p.dag.Election.Strategy()
+	if err != nil {
+		p.Log.Crit("Invalid election config", "err", err)
+	}
+	p.election = election.New(p.Validators, p.LastDecidedFrame+1, p.vecClock.ForklessCause, p.store.GetFrameRoots, strategy)
 
 	// events reprocessing
 	p.handleElection(nil)
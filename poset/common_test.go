@@ -43,6 +43,19 @@ func (p *ExtendedPoset) EventsTillBlock(until idx.Block) hash.Events {
 
 // FakePoset creates empty poset with mem store and equal stakes of nodes in genesis.
 func FakePoset(namespace string, nodes []idx.StakerID, mods ...memorydb.Mod) (*ExtendedPoset, *Store, *EventStore) {
+	return fakePosetWithConfig(namespace, nodes, fakeDagConfig(), mods...)
+}
+
+func fakeDagConfig() lachesis.DagConfig {
+	config := lachesis.FakeNetDagConfig()
+	if config.MaxEpochBlocks > 100 {
+		// MaxEpochBlocks too big, test timeout is possible.
+		config.MaxEpochBlocks = 100
+	}
+	return config
+}
+
+func fakePosetWithConfig(namespace string, nodes []idx.StakerID, config lachesis.DagConfig, mods ...memorydb.Mod) (*ExtendedPoset, *Store, *EventStore) {
 	validators := make(pos.GValidators, 0, len(nodes))
 	for _, v := range nodes {
 		validators = append(validators, pos.GenesisValidator{
@@ -70,11 +83,6 @@ func FakePoset(namespace string, nodes []idx.StakerID, mods ...memorydb.Mod) (*E
 
 	input := NewEventStore(nil, 100)
 
-	config := lachesis.FakeNetDagConfig()
-	if config.MaxEpochBlocks > 100 {
-		// MaxEpochBlocks too big, test timeout is possible.
-		config.MaxEpochBlocks = 100
-	}
 	poset := New(config, store, input)
 
 	extended := &ExtendedPoset{
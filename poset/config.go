@@ -4,6 +4,12 @@ package poset
 type StoreConfig struct {
 	// Cache size for Roots.
 	Roots int
+
+	// Whether to record election traces of the decided frames or not.
+	ElectionTraces bool
+
+	// Number of the last blocks of the current epoch to keep checkpoints of, for rollback. 0 disables the history.
+	CheckpointHistory int
 }
 
 // DefaultStoreConfig for product.
@@ -14,10 +14,14 @@ type (
 		frameToDecide idx.Frame
 
 		validators *pos.Validators
+		strategy   Strategy
+		candidates []idx.StakerID // Atropos candidates order
+		quorum     pos.Stake      // votes to decide a root
 
 		// election state
 		decidedRoots map[idx.StakerID]voteValue // decided roots at "frameToDecide"
 		votes        map[voteID]voteValue
+		voterFrames  map[hash.Event]idx.Frame // for the election trace only
 
 		// external world
 		observe       ForklessCauseFn
@@ -67,8 +71,10 @@ func New(
 	frameToDecide idx.Frame,
 	forklessCauseFn ForklessCauseFn,
 	getFrameRoots GetFrameRootsFn,
+	strategy Strategy,
 ) *Election {
 	el := &Election{
+		strategy:      strategy,
 		observe:       forklessCauseFn,
 		getFrameRoots: getFrameRoots,
 
@@ -84,8 +90,11 @@ func New(
 func (el *Election) Reset(validators *pos.Validators, frameToDecide idx.Frame) {
 	el.validators = validators
 	el.frameToDecide = frameToDecide
+	el.candidates = el.strategy.AtroposCandidates(validators, frameToDecide)
+	el.quorum = el.strategy.Quorum(validators)
 	el.votes = make(map[voteID]voteValue)
 	el.decidedRoots = make(map[idx.StakerID]voteValue)
+	el.voterFrames = make(map[hash.Event]idx.Frame)
 }
 
 // return root slots which are not within el.decidedRoots
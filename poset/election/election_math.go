@@ -25,6 +25,7 @@ func (el *Election) ProcessRoot(newRoot RootAndSlot) (*Res, error) {
 	}
 
 	notDecidedRoots := el.notDecidedRoots()
+	el.voterFrames[newRoot.ID] = newRoot.Slot.Frame
 
 	var observedRoots []RootAndSlot
 	var observedRootsMap map[idx.StakerID]RootAndSlot
@@ -95,9 +96,9 @@ func (el *Election) ProcessRoot(newRoot RootAndSlot) (*Res, error) {
 				vote.observedRoot = *subjectHash
 			}
 
-			// If supermajority is observed, then the final decision may be made.
-			// It's guaranteed to be final and consistent unless more than 1/3W are Byzantine.
-			vote.decided = yesVotes.HasQuorum() || noVotes.HasQuorum()
+			// If supermajority (quorum of the strategy) is observed, then the final decision may be made.
+			// With the default strategy, it's guaranteed to be final and consistent unless more than 1/3W are Byzantine.
+			vote.decided = yesVotes.Sum() >= el.quorum || noVotes.Sum() >= el.quorum
 			if vote.decided {
 				el.decidedRoots[validatorSubject] = vote
 			}
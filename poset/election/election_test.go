@@ -263,7 +263,7 @@ func testProcessRoot(
 	}
 	ordered = unordered.ByParents()
 
-	election := New(validators, 0, forklessCauseFn, getFrameRootsFn)
+	election := New(validators, 0, forklessCauseFn, getFrameRootsFn, DefaultStrategy())
 
 	// processing:
 	var alreadyDecided bool
@@ -4,12 +4,12 @@ import (
 	"errors"
 )
 
-// Chooses the decided "yes" roots with the greatest stake amount.
+// Chooses the first decided "yes" root in the strategy order, by default with the greatest stake amount.
 // This root serves as a "checkpoint" within DAG, as it's guaranteed to be final and consistent unless more than 1/3W are Byzantine.
 // Other validators will come to the same Atropos not later than current highest frame + 2.
 func (el *Election) chooseAtropos() (*Res, error) {
 	// iterate until Yes root is met, which will be Atropos. I.e. not necessarily all the roots must be decided
-	for _, validator := range el.validators.SortedIDs() {
+	for _, validator := range el.candidates {
 		vote, ok := el.decidedRoots[validator]
 		if !ok {
 			return nil, nil // not decided
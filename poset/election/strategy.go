@@ -0,0 +1,94 @@
+package election
+
+import (
+	"fmt"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+)
+
+// Roots orders of the Atropos candidates.
+const (
+	// RootsOrderStake is the default order: by stake, then by ID.
+	RootsOrderStake = "stake"
+	// RootsOrderRotating is the default order, rotated by the frame number,
+	// so every validator is the first candidate in its turn.
+	RootsOrderRotating = "rotating"
+)
+
+// Strategy defines how the frame is decided: the order of Atropos candidates and the quorum of votes.
+// Only DefaultStrategy is safe for production networks, the others are for research.
+type Strategy interface {
+	// AtroposCandidates returns validators in the order their roots are tried to be the Atropos of the frame.
+	AtroposCandidates(validators *pos.Validators, frame idx.Frame) []idx.StakerID
+	// Quorum returns stake of "yes" or "no" votes which makes the decision.
+	Quorum(validators *pos.Validators) pos.Stake
+}
+
+type (
+	defaultStrategy struct{}
+
+	customStrategy struct {
+		rootsOrder    string
+		quorumPercent uint32
+	}
+)
+
+// DefaultStrategy chooses the decided "yes" root with the greatest stake, by 2/3W+1 of votes.
+func DefaultStrategy() Strategy {
+	return defaultStrategy{}
+}
+
+// NewStrategy makes the strategy with the roots order (RootsOrderStake if empty)
+// and the quorum in percents of total stake (2/3W+1 if zero).
+func NewStrategy(rootsOrder string, quorumPercent uint32) (Strategy, error) {
+	if rootsOrder == "" {
+		rootsOrder = RootsOrderStake
+	}
+	if rootsOrder != RootsOrderStake && rootsOrder != RootsOrderRotating {
+		return nil, fmt.Errorf("unknown roots order %q", rootsOrder)
+	}
+	// less than majority may decide both "yes" and "no"
+	if quorumPercent != 0 && (quorumPercent <= 50 || quorumPercent > 100) {
+		return nil, fmt.Errorf("quorum percent %d isn't in range (50, 100]", quorumPercent)
+	}
+
+	if rootsOrder == RootsOrderStake && quorumPercent == 0 {
+		return DefaultStrategy(), nil
+	}
+	return customStrategy{
+		rootsOrder:    rootsOrder,
+		quorumPercent: quorumPercent,
+	}, nil
+}
+
+func (defaultStrategy) AtroposCandidates(validators *pos.Validators, frame idx.Frame) []idx.StakerID {
+	return validators.SortedIDs()
+}
+
+func (defaultStrategy) Quorum(validators *pos.Validators) pos.Stake {
+	return validators.Quorum()
+}
+
+func (s customStrategy) AtroposCandidates(validators *pos.Validators, frame idx.Frame) []idx.StakerID {
+	sorted := validators.SortedIDs()
+	if s.rootsOrder != RootsOrderRotating || len(sorted) == 0 {
+		return sorted
+	}
+	first := int(frame) % len(sorted)
+	ids := make([]idx.StakerID, 0, len(sorted))
+	ids = append(ids, sorted[first:]...)
+	ids = append(ids, sorted[:first]...)
+	return ids
+}
+
+func (s customStrategy) Quorum(validators *pos.Validators) pos.Stake {
+	if s.quorumPercent == 0 {
+		return validators.Quorum()
+	}
+	quorum := validators.TotalStake()*pos.Stake(s.quorumPercent)/100 + 1
+	if quorum > validators.TotalStake() {
+		quorum = validators.TotalStake()
+	}
+	return quorum
+}
@@ -0,0 +1,48 @@
+package election
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+)
+
+func TestStrategies(t *testing.T) {
+	assertar := assert.New(t)
+
+	builder := pos.NewBuilder()
+	for id := idx.StakerID(1); id <= 4; id++ {
+		builder.Set(id, 25)
+	}
+	validators := builder.Build()
+
+	s, err := NewStrategy("", 0)
+	assertar.NoError(err)
+	assertar.Equal(DefaultStrategy(), s)
+	assertar.Equal(validators.SortedIDs(), s.AtroposCandidates(validators, 5))
+	assertar.Equal(validators.Quorum(), s.Quorum(validators))
+
+	s, err = NewStrategy(RootsOrderRotating, 0)
+	assertar.NoError(err)
+	assertar.Equal(validators.SortedIDs(), s.AtroposCandidates(validators, 4))
+	assertar.Equal([]idx.StakerID{2, 3, 4, 1}, s.AtroposCandidates(validators, 5))
+	assertar.Equal(validators.Quorum(), s.Quorum(validators))
+
+	s, err = NewStrategy(RootsOrderStake, 90)
+	assertar.NoError(err)
+	assertar.Equal(validators.SortedIDs(), s.AtroposCandidates(validators, 5))
+	assertar.Equal(pos.Stake(91), s.Quorum(validators))
+
+	s, err = NewStrategy(RootsOrderStake, 100)
+	assertar.NoError(err)
+	assertar.Equal(pos.Stake(100), s.Quorum(validators))
+
+	_, err = NewStrategy("unknown", 0)
+	assertar.Error(err)
+	_, err = NewStrategy(RootsOrderStake, 50)
+	assertar.Error(err)
+	_, err = NewStrategy(RootsOrderStake, 101)
+	assertar.Error(err)
+}
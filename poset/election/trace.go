@@ -0,0 +1,142 @@
+package election
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+type (
+	// Vote is a vote of the root for the validator's root at the deciding frame.
+	Vote struct {
+		FromRoot     hash.Event
+		FromFrame    idx.Frame // voting round is FromFrame - Trace.Frame
+		ForValidator idx.StakerID
+		Yes          bool
+		Decided      bool
+		ObservedRoot hash.Event // zero if vote isn't "yes"
+	}
+
+	// Trace is a record of the decided election, to diagnose how the Atropos was chosen.
+	Trace struct {
+		Frame   idx.Frame
+		Atropos hash.Event
+		Votes   []Vote // ordered by voter's frame, voter and subject
+	}
+)
+
+// Trace returns record of the current election, which is decided with the atropos.
+// It must be called before the election is reset.
+func (el *Election) Trace(atropos hash.Event) *Trace {
+	t := &Trace{
+		Frame:   el.frameToDecide,
+		Atropos: atropos,
+		Votes:   make([]Vote, 0, len(el.votes)),
+	}
+	for vid, vote := range el.votes {
+		t.Votes = append(t.Votes, Vote{
+			FromRoot:     vid.fromRoot,
+			FromFrame:    el.voterFrames[vid.fromRoot],
+			ForValidator: vid.forValidator,
+			Yes:          vote.yes,
+			Decided:      vote.decided,
+			ObservedRoot: vote.observedRoot,
+		})
+	}
+	sort.Slice(t.Votes, func(i, j int) bool {
+		a, b := t.Votes[i], t.Votes[j]
+		if a.FromFrame != b.FromFrame {
+			return a.FromFrame < b.FromFrame
+		}
+		if a.FromRoot != b.FromRoot {
+			return bytes.Compare(a.FromRoot.Bytes(), b.FromRoot.Bytes()) < 0
+		}
+		return a.ForValidator < b.ForValidator
+	})
+	return t
+}
+
+// Rounds returns number of the voting rounds it took to decide the frame.
+func (t *Trace) Rounds() idx.Frame {
+	var rounds idx.Frame
+	for _, v := range t.Votes {
+		if v.FromFrame-t.Frame > rounds {
+			rounds = v.FromFrame - t.Frame
+		}
+	}
+	return rounds
+}
+
+// DOT returns the trace as a graph in DOT format.
+// Voters are grouped by frames, each vote is an edge to the subject validator,
+// labeled as in Election.String: y is yes, n is no, upper case means 'decided'.
+func (t *Trace) DOT() string {
+	var (
+		subjects []idx.StakerID
+		known    = make(map[idx.StakerID]bool)
+		atropos  idx.StakerID
+		frames   = make(map[idx.Frame]hash.Events)
+		voters   = hash.EventsSet{}
+	)
+	for _, v := range t.Votes {
+		if !known[v.ForValidator] {
+			known[v.ForValidator] = true
+			subjects = append(subjects, v.ForValidator)
+		}
+		if v.Decided && v.Yes && v.ObservedRoot == t.Atropos {
+			atropos = v.ForValidator
+		}
+		if !voters.Contains(v.FromRoot) {
+			voters.Add(v.FromRoot)
+			frames[v.FromFrame] = append(frames[v.FromFrame], v.FromRoot)
+		}
+	}
+	sort.Slice(subjects, func(i, j int) bool {
+		return subjects[i] < subjects[j]
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph \"election of frame %d\" {\n", t.Frame)
+	fmt.Fprintf(&b, "\tlabel=\"frame %d, Atropos %s, %d rounds\";\n", t.Frame, t.Atropos.String(), t.Rounds())
+	b.WriteString("\trankdir=BT;\n")
+
+	b.WriteString("\tsubgraph cluster_subjects {\n")
+	fmt.Fprintf(&b, "\t\tlabel=\"frame %d\";\n", t.Frame)
+	for _, v := range subjects {
+		attrs := ""
+		if v == atropos && !t.Atropos.IsZero() {
+			attrs = fmt.Sprintf(", peripheries=2, xlabel=\"%s\"", t.Atropos.String())
+		}
+		fmt.Fprintf(&b, "\t\t\"validator %d\" [shape=box%s];\n", v, attrs)
+	}
+	b.WriteString("\t}\n")
+
+	for f := t.Frame + 1; f <= t.Frame+t.Rounds(); f++ {
+		fmt.Fprintf(&b, "\tsubgraph cluster_frame_%d {\n", f)
+		fmt.Fprintf(&b, "\t\tlabel=\"frame %d\";\n", f)
+		for _, root := range frames[f] {
+			fmt.Fprintf(&b, "\t\t\"%s\";\n", root.String())
+		}
+		b.WriteString("\t}\n")
+	}
+
+	for _, v := range t.Votes {
+		label := "n"
+		if v.Yes {
+			label = "y"
+		}
+		style := "dashed"
+		if v.Decided {
+			label = strings.ToUpper(label)
+			style = "bold"
+		}
+		fmt.Fprintf(&b, "\t\"%s\" -> \"validator %d\" [label=\"%s\", style=%s];\n", v.FromRoot.String(), v.ForValidator, label, style)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -95,6 +95,12 @@ func (p *Poset) confirmBlock(frame idx.Frame, atropos hash.Event) (block *inter.
 func (p *Poset) onFrameDecided(frame idx.Frame, atropos hash.Event) bool {
 	p.Log.Debug("consensus: event is atropos", "event", atropos.String())
 
+	// all the writes until the new checkpoint are recorded, to make it possible to roll back the block
+	p.store.startBlockUndo(p.EpochN)
+
+	if p.store.cfg.ElectionTraces {
+		p.store.SetElectionTrace(p.election.Trace(atropos))
+	}
 	p.election.Reset(p.Validators, frame+1)
 	p.Checkpoint.LastDecidedFrame = frame
 
@@ -110,6 +116,7 @@ func (p *Poset) onFrameDecided(frame idx.Frame, atropos hash.Event) bool {
 	}
 	p.Checkpoint.LastAtropos = atropos
 	p.saveCheckpoint()
+	p.store.saveCheckpointRecord(p.EpochN, p.Checkpoint)
 
 	if sealEpoch {
 		p.sealEpoch()
@@ -138,6 +145,7 @@ func (p *Poset) sealEpoch() {
 
 	// reset internal epoch DB
 	p.store.RecreateEpochDb(p.EpochN)
+	p.store.dropCheckpointHistory()
 
 	// reset election & vectorindex to new epoch db
 	p.vecClock.Reset(p.Validators, p.store.epochTable.VectorIndex, func(id hash.Event) *inter.EventHeaderData {
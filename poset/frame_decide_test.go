@@ -75,3 +75,73 @@ func TestConfirmBlockEvents(t *testing.T) {
 		}
 	}
 }
+
+func TestElectionTraces(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	nodes := inter.GenNodes(5)
+	poset, store, input := FakePoset("", nodes)
+	store.cfg.ElectionTraces = true
+
+	decided := make(map[idx.Frame]*inter.Block)
+	applyBlock := poset.callback.ApplyBlock
+	poset.callback.ApplyBlock = func(block *inter.Block, decidedFrame idx.Frame, cheaters inter.Cheaters) (common.Hash, bool) {
+		decided[decidedFrame] = block
+		return applyBlock(block, decidedFrame, cheaters)
+	}
+
+	var ordered inter.Events
+	_ = inter.ForEachRandEvent(nodes, 50, 3, nil, inter.ForEachEvent{
+		Process: func(e *inter.Event, name string) {
+			ordered = append(ordered, e)
+			input.SetEvent(e)
+			assertar.NoError(
+				poset.ProcessEvent(e))
+			assertar.NoError(
+				flushDb(poset, e.Hash()))
+		},
+		Build: func(e *inter.Event, name string) *inter.Event {
+			e.Epoch = idx.Epoch(1)
+			return poset.Prepare(e)
+		},
+	})
+	if !assertar.NotEmpty(decided) {
+		return
+	}
+
+	for frame, block := range decided {
+		trace := poset.GetElectionTrace(frame)
+		if !assertar.NotNil(trace, "frame %d", frame) {
+			continue
+		}
+		assertar.Equal(frame, trace.Frame)
+		assertar.Equal(block.Atropos, trace.Atropos)
+		assertar.NotZero(trace.Rounds())
+
+		atroposVotes := 0
+		for _, v := range trace.Votes {
+			assertar.True(v.FromFrame > frame && v.FromFrame <= frame+trace.Rounds())
+			if v.Decided && v.Yes && v.ObservedRoot == trace.Atropos {
+				atroposVotes++
+			}
+		}
+		assertar.NotZero(atroposVotes, "Atropos isn't decided by the votes")
+		assertar.Contains(trace.DOT(), trace.Atropos.String())
+	}
+	assertar.Nil(poset.GetElectionTrace(poset.LastDecidedFrame + 1))
+
+	// disabled by default
+	poset, _, input = FakePoset("", nodes)
+	for _, e := range ordered {
+		input.SetEvent(e)
+		assertar.NoError(
+			poset.ProcessEvent(e))
+		assertar.NoError(
+			flushDb(poset, e.Hash()))
+	}
+	assertar.NotZero(poset.LastDecidedFrame)
+	for frame := range decided {
+		assertar.Nil(poset.GetElectionTrace(frame))
+	}
+}
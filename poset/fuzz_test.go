@@ -0,0 +1,354 @@
+package poset
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+// fuzzRegressions are the DAGs dumped by TestConsensusFuzz.
+// Paste the dumped ASCII-scheme here to reproduce the failure.
+var fuzzRegressions = []string{}
+
+// fuzzConfig contains parameters of the random DAG.
+type fuzzConfig struct {
+	nodes      int
+	cheaters   int // forks creators
+	events     int // events to create
+	maxParents int
+	forkChance int // 1 of forkChance cheater's events is a fork
+	posets     int // instances to compare
+
+	election lachesis.ElectionConfig // frame-decision strategy
+}
+
+func randFuzzConfig(r *rand.Rand) fuzzConfig {
+	nodes := 4 + r.Intn(6)
+	return fuzzConfig{
+		nodes:      nodes,
+		cheaters:   r.Intn((nodes-1)/3 + 1), // less than 1/3 of equal stakes
+		events:     nodes * (15 + r.Intn(15)),
+		maxParents: 2 + r.Intn(nodes-1),
+		forkChance: 2 + r.Intn(4),
+		posets:     2 + r.Intn(3),
+	}
+}
+
+// TestConsensusFuzz checks that posets decide the same blocks on random DAGs with forks,
+// regardless of the events order. Failed DAG is dumped as ASCII-scheme for reproduction.
+func TestConsensusFuzz(t *testing.T) {
+	logger.SetTestMode(t)
+
+	seeds := int64(50)
+	if testing.Short() {
+		seeds = 5
+	}
+	for seed := int64(0); seed < seeds; seed++ {
+		seed := seed
+		t.Run(fmt.Sprintf("seed %d", seed), func(t *testing.T) {
+			testConsensusFuzz(t, seed)
+		})
+	}
+}
+
+// TestConsensusFuzzRegressions replays the dumped DAGs.
+func TestConsensusFuzzRegressions(t *testing.T) {
+	logger.SetTestMode(t)
+
+	for i, scheme := range fuzzRegressions {
+		scheme := scheme
+		t.Run(fmt.Sprintf("DAG %d", i), func(t *testing.T) {
+			testConsensusScheme(t, scheme)
+		})
+	}
+}
+
+// TestConsensusFuzzDump checks that dumped DAG reproduces the same consensus.
+func TestConsensusFuzzDump(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	r := rand.New(rand.NewSource(3)) // DAG with forks
+	cfg := randFuzzConfig(r)
+	gen := genFuzzDAG(r, cfg)
+
+	scheme, err := inter.DAGtoASCIIscheme(gen.events)
+	if !assertar.NoError(err) {
+		return
+	}
+	got := testConsensusScheme(t, scheme)
+	if got == nil {
+		return
+	}
+
+	assertar.Equal(len(gen.events), len(got.events), "events")
+	assertar.Equal(atroposSequence(gen.poset), atroposSequence(got.poset), "Atropos sequence")
+	for b, block := range gen.poset.blocks {
+		if !assertar.Equal(block.Events, got.poset.blocks[b].Events, "block %d", b) {
+			break
+		}
+	}
+}
+
+func testConsensusFuzz(t *testing.T, seed int64) {
+	r := rand.New(rand.NewSource(seed))
+	cfg := randFuzzConfig(r)
+	t.Logf("%+v", cfg)
+
+	gen := genFuzzDAG(r, cfg)
+	if !checkConsensusOrders(t, r, gen, cfg.posets) {
+		dumpFuzzDAG(t, gen.events)
+	}
+}
+
+// genFuzzDAG generates random DAG by the config.
+func genFuzzDAG(r *rand.Rand, cfg fuzzConfig) *fuzzGenerator {
+	nodes := genFuzzNodes(cfg.nodes)
+	cheaters := make(map[idx.StakerID]bool, cfg.cheaters)
+	for _, n := range r.Perm(cfg.nodes)[:cfg.cheaters] {
+		cheaters[nodes[n]] = true
+	}
+
+	gen := newFuzzGenerator(nodes, cfg.election)
+	// every validator has events, to be presented in ASCII-scheme
+	for self := range nodes {
+		gen.randEvent(r, self, cfg, cheaters)
+	}
+	for len(gen.events) < cfg.events {
+		gen.randEvent(r, r.Intn(cfg.nodes), cfg, cheaters)
+	}
+	return gen
+}
+
+// genFuzzNodes makes validators the same as ASCIIschemeForEach makes from the first events names,
+// so the dumped DAG has the same validators order.
+func genFuzzNodes(count int) []idx.StakerID {
+	nodes := make([]idx.StakerID, count)
+	for self := range nodes {
+		nodes[self] = idx.BytesToStakerID(hash.Of([]byte(fuzzEventName(self, 0))).Bytes()[:4])
+		hash.SetNodeName(nodes[self], "node"+string(rune('A'+self)))
+	}
+	return nodes
+}
+
+func fuzzEventName(self, n int) string {
+	return fmt.Sprintf("%s%03d", string(rune('a'+self)), n)
+}
+
+// testConsensusScheme generates events from ASCII-scheme and checks consensus on them.
+func testConsensusScheme(t *testing.T, scheme string) *fuzzGenerator {
+	assertar := assert.New(t)
+
+	nodes, _, _ := inter.ASCIIschemeToDAG(scheme)
+	gen := newFuzzGenerator(nodes, lachesis.ElectionConfig{})
+	inter.ASCIIschemeForEach(scheme, inter.ForEachEvent{
+		Process: func(e *inter.Event, name string) {
+			assertar.NoError(
+				gen.process(e), name)
+		},
+		Build: func(e *inter.Event, name string) *inter.Event {
+			return gen.build(e)
+		},
+	})
+	if t.Failed() {
+		return nil
+	}
+
+	r := rand.New(rand.NewSource(0))
+	if !checkConsensusOrders(t, r, gen, 3) {
+		return nil
+	}
+	return gen
+}
+
+// fuzzGenerator creates events on its own poset, so they have correct consensus fields.
+type fuzzGenerator struct {
+	nodes  []idx.StakerID
+	config lachesis.DagConfig
+	poset  *ExtendedPoset
+	input  *EventStore
+	events inter.Events // processed events, in creation order
+
+	byCreator map[idx.StakerID]inter.Events
+	created   map[idx.StakerID]int
+}
+
+func newFuzzGenerator(nodes []idx.StakerID, election lachesis.ElectionConfig) *fuzzGenerator {
+	config := fakeDagConfig()
+	config.Election = election
+	p, _, input := fakePosetWithConfig("", nodes, config)
+	return &fuzzGenerator{
+		nodes:     nodes,
+		config:    config,
+		poset:     p,
+		input:     input,
+		byCreator: make(map[idx.StakerID]inter.Events, len(nodes)),
+		created:   make(map[idx.StakerID]int, len(nodes)),
+	}
+}
+
+// randEvent creates event with random parents. Cheater may fork from any of its events.
+// Events which are rejected by poset (e.g. observing cheaters) are dropped.
+func (g *fuzzGenerator) randEvent(r *rand.Rand, self int, cfg fuzzConfig, cheaters map[idx.StakerID]bool) {
+	nodes := g.nodes
+	creator := nodes[self]
+
+	e := inter.NewEvent()
+	e.Creator = creator
+	e.Parents = hash.Events{}
+
+	// self-parent
+	var parent *inter.Event
+	if own := g.byCreator[creator]; len(own) > 0 {
+		parent = own[len(own)-1]
+		if cheaters[creator] && r.Intn(cfg.forkChance) == 0 {
+			// fork, including the first event
+			if i := r.Intn(len(own) + 1); i < len(own) {
+				parent = own[i]
+			} else {
+				parent = nil
+			}
+		}
+	}
+	if parent != nil {
+		e.Seq = parent.Seq + 1
+		e.Parents.Add(parent.Hash())
+		e.Lamport = parent.Lamport + 1
+	} else {
+		e.Seq = 1
+		e.Lamport = 1
+	}
+
+	// other parents are random recent events of random validators
+	others := r.Perm(len(nodes))
+	count := 1 + r.Intn(cfg.maxParents-1)
+	for _, other := range others {
+		if count == 0 {
+			break
+		}
+		ee := g.byCreator[nodes[other]]
+		if other == self || len(ee) == 0 {
+			continue
+		}
+		recent := 1 + r.Intn(3)
+		if recent > len(ee) {
+			recent = len(ee)
+		}
+		p := ee[len(ee)-recent+r.Intn(recent)]
+		e.Parents.Add(p.Hash())
+		if e.Lamport <= p.Lamport {
+			e.Lamport = p.Lamport + 1
+		}
+		count--
+	}
+
+	name := fuzzEventName(self, g.created[creator])
+	g.created[creator]++
+	// the same as ASCIIschemeForEach makes, to be reproducible
+	e.Extra = []byte(name)
+
+	e = g.build(e)
+	if e == nil {
+		return
+	}
+	e.RecacheHash()
+	e.RecacheSize()
+	hash.SetEventName(e.Hash(), name)
+	_ = g.process(e)
+}
+
+func (g *fuzzGenerator) build(e *inter.Event) *inter.Event {
+	// parents order affects the hash, so don't depend on the ASCII-scheme columns
+	others := e.Parents
+	if e.SelfParent() != nil {
+		others = others[1:]
+	}
+	sort.Slice(others, func(i, j int) bool {
+		return g.input.GetEvent(others[i]).Creator < g.input.GetEvent(others[j]).Creator
+	})
+
+	e.Epoch = 1
+	e.ClaimedTime = genesisTime + inter.Timestamp(e.Lamport)*inter.Timestamp(time.Second)
+	return g.poset.Prepare(e)
+}
+
+func (g *fuzzGenerator) process(e *inter.Event) error {
+	g.input.SetEvent(e)
+	if err := g.poset.ProcessEvent(e); err != nil {
+		return err
+	}
+	if err := flushDb(g.poset, e.Hash()); err != nil {
+		return err
+	}
+
+	g.events = append(g.events, e)
+	g.byCreator[e.Creator] = append(g.byCreator[e.Creator], e)
+	return nil
+}
+
+// checkConsensusOrders feeds the generated events into the new posets in random topological orders,
+// and checks that all of them decide the same Atropos sequence and blocks as the generator.
+func checkConsensusOrders(t *testing.T, r *rand.Rand, gen *fuzzGenerator, count int) bool {
+	assertar := assert.New(t)
+
+	posets := []*ExtendedPoset{gen.poset}
+	for i := 0; i < count; i++ {
+		p, _, input := fakePosetWithConfig("", gen.nodes, gen.config)
+		for _, e := range reorderRand(r, gen.events) {
+			input.SetEvent(e)
+			if !assertar.NoError(p.ProcessEvent(e), "poset%d, event %s", i+1, e.Hash().String()) {
+				return false
+			}
+			if !assertar.NoError(flushDb(p, e.Hash())) {
+				return false
+			}
+		}
+		posets = append(posets, p)
+	}
+
+	expect := atroposSequence(gen.poset)
+	t.Logf("%d events, %d blocks", len(gen.events), len(expect))
+	for i, p := range posets[1:] {
+		assertar.Equal(expect, atroposSequence(p), "Atropos sequence of poset%d", i+1)
+	}
+	compareResults(t, posets)
+
+	return !t.Failed()
+}
+
+// reorderRand is the same as reorder, but determined by r.
+func reorderRand(r *rand.Rand, events inter.Events) inter.Events {
+	unordered := make(inter.Events, len(events))
+	for i, j := range r.Perm(len(events)) {
+		unordered[j] = events[i]
+	}
+
+	return unordered.ByParents()
+}
+
+func atroposSequence(p *ExtendedPoset) hash.Events {
+	res := make(hash.Events, 0, len(p.blocks))
+	for b := idx.Block(1); p.blocks[b] != nil; b++ {
+		res = append(res, p.blocks[b].Atropos)
+	}
+	return res
+}
+
+func dumpFuzzDAG(t *testing.T, events inter.Events) {
+	scheme, err := inter.DAGtoASCIIscheme(events)
+	if err != nil {
+		t.Errorf("failed to dump DAG: %v", err)
+		return
+	}
+	t.Logf("DAG to reproduce (add it to fuzzRegressions):\n%s", scheme)
+}
@@ -60,6 +60,12 @@ func (p *Poset) GetVectorIndex() *vector.Index {
 	return p.vecClock
 }
 
+// GetElectionTrace returns election trace of the decided frame of the current epoch,
+// if election traces are enabled. Traces of the sealed epochs are dropped with the epoch DB.
+func (p *Poset) GetElectionTrace(f idx.Frame) *election.Trace {
+	return p.store.GetElectionTrace(f)
+}
+
 // LastBlock returns current block.
 func (p *Poset) LastBlock() (idx.Block, hash.Event) {
 	return p.LastBlockN, p.LastAtropos
@@ -0,0 +1,62 @@
+package poset
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+)
+
+var (
+	// ErrNotDecidedBlock indicates that the rollback target is ahead of the last decided block.
+	ErrNotDecidedBlock = errors.New("block isn't decided yet")
+)
+
+// HistoryRange returns the oldest block which may be rolled back to, and the last decided block.
+// Only the blocks of the current epoch, which have checkpoint records, may be rolled back.
+func (s *Store) HistoryRange() (oldest, last idx.Block) {
+	cp := s.GetCheckpoint()
+	if cp == nil {
+		return 0, 0
+	}
+	es := s.GetEpoch()
+
+	oldest = cp.LastBlockN
+	for oldest > 0 {
+		r := s.GetCheckpointRecord(oldest)
+		if r == nil || r.Epoch != es.EpochN || r.Checkpoint.LastBlockN != oldest {
+			break
+		}
+		oldest--
+	}
+	return oldest, cp.LastBlockN
+}
+
+// Rollback reverts the poset and the checkpointed tables of the other stores to the state right after the block,
+// by applying the undo records of the subsequent blocks in reverse order. Rolled back blocks are decided
+// and applied again by Poset.Bootstrap, because their events are kept.
+// The changes aren't flushed.
+func (s *Store) Rollback(to idx.Block) error {
+	oldest, last := s.HistoryRange()
+	if to > last {
+		return ErrNotDecidedBlock
+	}
+	if to < oldest {
+		return fmt.Errorf("block %d isn't in the checkpoint history of the current epoch, the oldest one is %d", to, oldest)
+	}
+
+	for n := last; n > to; n-- {
+		r := s.GetCheckpointRecord(n)
+		err := s.dbs.ApplyUndo(r.Undo)
+		if err != nil {
+			return err
+		}
+		s.DelCheckpointRecord(n)
+	}
+
+	// sanity check
+	if cp := s.GetCheckpoint(); cp.LastBlockN != to {
+		return fmt.Errorf("checkpoint of block %d is restored instead of %d", cp.LastBlockN, to)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package poset
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/hash"
+	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/inter/pos"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+)
+
+func TestRollback(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	const history = 10
+	nodes := inter.GenNodes(5)
+	namespace := fmt.Sprintf("poset.TestRollback-%d", rand.Int())
+	dag := fakeDagConfig()
+	input := NewEventStore(nil, 100)
+
+	// app state is a counter of blocks and a block index, in another db of the pool
+	blocks := make(map[idx.Block]*inter.Block)
+	open := func() (*Poset, *Store, *flushable.SyncedPool) {
+		dbs := flushable.NewSyncedPool(memorydb.NewProducer(namespace))
+		cfg := LiteStoreConfig()
+		cfg.CheckpointHistory = history
+		store := NewStore(dbs, cfg)
+		store.SetCheckpointedTables(flushable.UndoScope{"app": nil})
+
+		if store.GetCheckpoint() == nil {
+			validators := make(pos.GValidators, 0, len(nodes))
+			for _, v := range nodes {
+				validators = append(validators, pos.GenesisValidator{
+					ID:    v,
+					Stake: pos.StakeToBalance(1),
+				})
+			}
+			err := store.ApplyGenesis(&genesis.Genesis{
+				Time: genesisTime,
+				Alloc: genesis.VAccounts{
+					Validators: validators,
+				},
+			}, hash.ZeroEvent, common.Hash{})
+			assertar.NoError(err)
+			assertar.NoError(dbs.Flush(hash.ZeroEvent.Bytes()))
+		}
+
+		app := dbs.GetDb("app")
+		p := New(dag, store, input)
+		p.Bootstrap(inter.ConsensusCallbacks{
+			ApplyBlock: func(block *inter.Block, decidedFrame idx.Frame, cheaters inter.Cheaters) (common.Hash, bool) {
+				if prev := blocks[block.Index]; prev != nil {
+					assertar.Equal(prev.Atropos, block.Atropos, "re-applied block")
+				}
+				blocks[block.Index] = block
+
+				assertar.NoError(app.Put([]byte("last"), block.Index.Bytes()))
+				assertar.NoError(app.Put(block.Index.Bytes(), block.Atropos.Bytes()))
+				return common.Hash(block.Atropos), false
+			},
+		})
+		return p, store, dbs
+	}
+
+	p, store, dbs := open()
+	inter.ForEachRandEvent(nodes, int(dag.MaxEpochBlocks)-1, 3, rand.New(rand.NewSource(1)), inter.ForEachEvent{
+		Process: func(e *inter.Event, name string) {
+			input.SetEvent(e)
+			assertar.NoError(p.ProcessEvent(e))
+			assertar.NoError(dbs.Flush(e.Hash().Bytes()))
+		},
+		Build: func(e *inter.Event, name string) *inter.Event {
+			e.Epoch = 1
+			if e.Seq%2 != 0 {
+				e.Transactions = append(e.Transactions, &types.Transaction{})
+			}
+			e.TxHash = types.DeriveSha(e.Transactions)
+			return p.Prepare(e)
+		},
+	})
+	expected := *store.GetCheckpoint()
+	if !assertar.True(expected.LastBlockN > history, "not enough blocks") {
+		return
+	}
+
+	oldest, last := store.HistoryRange()
+	assertar.Equal(expected.LastBlockN-history, oldest)
+	assertar.Equal(expected.LastBlockN, last)
+	assertar.Equal(ErrNotDecidedBlock, store.Rollback(last+1))
+	assertar.Error(store.Rollback(oldest - 1))
+
+	// roll back
+	to := last - history/2
+	toCheckpoint := store.GetCheckpointRecord(to).Checkpoint
+	assertar.NoError(store.Rollback(to))
+	assertar.NoError(dbs.Flush([]byte("rollback")))
+
+	assertar.Equal(toCheckpoint, *store.GetCheckpoint())
+	app := dbs.GetDb("app")
+	val, err := app.Get([]byte("last"))
+	assertar.NoError(err)
+	assertar.Equal(to, idx.BytesToBlock(val))
+	for n := to + 1; n <= last; n++ {
+		val, err = app.Get(n.Bytes())
+		assertar.NoError(err)
+		assertar.Nil(val, n)
+		assertar.Nil(store.GetCheckpointRecord(n))
+	}
+	oldest, last = store.HistoryRange()
+	assertar.Equal(expected.LastBlockN-history, oldest)
+	assertar.Equal(to, last)
+
+	// re-execute on restart
+	_, store, _ = open()
+	assertar.Equal(expected, *store.GetCheckpoint())
+}
@@ -26,6 +26,9 @@ type Store struct {
 		Epochs         kvdb.KeyValueStore `table:"e"`
 		ConfirmedEvent kvdb.KeyValueStore `table:"C"`
 		FrameInfos     kvdb.KeyValueStore `table:"f"`
+		History        kvdb.KeyValueStore `table:"h"`
+
+		Version kvdb.KeyValueStore `table:"_"`
 	}
 
 	cache struct {
@@ -35,10 +38,14 @@ type Store struct {
 
 	epochDb    kvdb.KeyValueStore
 	epochTable struct {
-		Roots       kvdb.KeyValueStore `table:"r"`
-		VectorIndex kvdb.KeyValueStore `table:"v"`
+		Roots          kvdb.KeyValueStore `table:"r"`
+		VectorIndex    kvdb.KeyValueStore `table:"v"`
+		ElectionTraces kvdb.KeyValueStore `table:"t"`
 	}
 
+	// tables of the other stores, which are modified by the blocks applying
+	checkpointed flushable.UndoScope
+
 	logger.Instance
 }
 
@@ -0,0 +1,93 @@
+package poset
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/flushable"
+	"github.com/Fantom-foundation/go-lachesis/kvdb/table"
+)
+
+// CheckpointRecord is a Checkpoint of the applied block, with the undo record of the block applying.
+type CheckpointRecord struct {
+	Epoch      idx.Epoch
+	Checkpoint Checkpoint
+	Undo       *flushable.Undo
+}
+
+// SetCheckpointedTables sets the tables of the other stores of the same dbs pool, which are modified
+// by the blocks applying, so they are rolled back along with the poset.
+func (s *Store) SetCheckpointedTables(scope flushable.UndoScope) {
+	s.checkpointed = scope
+}
+
+// startBlockUndo begins recording of the block applying, if checkpoint history is enabled.
+// Only the poset tables and the checkpointed tables of the other stores are recorded.
+func (s *Store) startBlockUndo(e idx.Epoch) {
+	if s.cfg.CheckpointHistory <= 0 {
+		return
+	}
+
+	scope := flushable.UndoScope{}
+	for db, prefixes := range s.checkpointed {
+		scope.Add(db, prefixes...)
+	}
+	scope.Add("poset-main", table.Prefixes(s.table.Checkpoint, s.table.Epochs, s.table.ConfirmedEvent, s.table.FrameInfos)...)
+	scope.Add(name(e))
+	s.dbs.StartUndo(scope)
+}
+
+// saveCheckpointRecord stops recording of the block applying and stores the record,
+// the records older than CheckpointHistory blocks are erased.
+func (s *Store) saveCheckpointRecord(e idx.Epoch, cp *Checkpoint) {
+	if s.cfg.CheckpointHistory <= 0 {
+		return
+	}
+
+	s.SetCheckpointRecord(cp.LastBlockN, &CheckpointRecord{
+		Epoch:      e,
+		Checkpoint: *cp,
+		Undo:       s.dbs.StopUndo(),
+	})
+
+	if cp.LastBlockN > idx.Block(s.cfg.CheckpointHistory) {
+		s.DelCheckpointRecord(cp.LastBlockN - idx.Block(s.cfg.CheckpointHistory))
+	}
+}
+
+// SetCheckpointRecord stores checkpoint record of the block.
+func (s *Store) SetCheckpointRecord(n idx.Block, r *CheckpointRecord) {
+	s.set(s.table.History, n.Bytes(), r)
+}
+
+// GetCheckpointRecord returns stored checkpoint record of the block.
+func (s *Store) GetCheckpointRecord(n idx.Block) *CheckpointRecord {
+	r, _ := s.get(s.table.History, n.Bytes(), &CheckpointRecord{}).(*CheckpointRecord)
+	return r
+}
+
+// DelCheckpointRecord erases checkpoint record of the block.
+func (s *Store) DelCheckpointRecord(n idx.Block) {
+	err := s.table.History.Delete(n.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to erase checkpoint record", "err", err)
+	}
+}
+
+// dropCheckpointHistory erases all the checkpoint records.
+// Blocks of the sealed epochs can't be rolled back, because new epoch state isn't recorded.
+func (s *Store) dropCheckpointHistory() {
+	keys := make([][]byte, 0, s.cfg.CheckpointHistory)
+	it := s.table.History.NewIterator()
+	for it.Next() {
+		keys = append(keys, common.CopyBytes(it.Key()))
+	}
+	it.Release()
+
+	for _, key := range keys {
+		err := s.table.History.Delete(key)
+		if err != nil {
+			s.Log.Crit("Failed to erase checkpoint record", "err", err)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package poset
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
+)
+
+// SetElectionTrace stores election trace of the decided frame.
+func (s *Store) SetElectionTrace(t *election.Trace) {
+	s.set(s.epochTable.ElectionTraces, t.Frame.Bytes(), t)
+}
+
+// GetElectionTrace returns stored election trace of the decided frame of the current epoch.
+func (s *Store) GetElectionTrace(f idx.Frame) *election.Trace {
+	t, _ := s.get(s.epochTable.ElectionTraces, f.Bytes(), &election.Trace{}).(*election.Trace)
+	return t
+}
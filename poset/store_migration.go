@@ -0,0 +1,17 @@
+package poset
+
+import (
+	"github.com/Fantom-foundation/go-lachesis/utils/migration"
+)
+
+// Migrations returns migrations of the store data, in order of applying.
+// New migrations must be appended to the end only.
+func (s *Store) Migrations() *migration.Schema {
+	return &migration.Schema{
+		Name:    "poset",
+		Version: s.table.Version,
+		IsEmpty: func() bool {
+			return s.GetGenesis() == nil
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package poset
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/lachesis"
+	"github.com/Fantom-foundation/go-lachesis/lachesis/genesis"
+	"github.com/Fantom-foundation/go-lachesis/logger"
+	"github.com/Fantom-foundation/go-lachesis/poset/election"
+)
+
+var testElectionStrategies = map[string]lachesis.ElectionConfig{
+	"default":   {},
+	"rotating":  {RootsOrder: election.RootsOrderRotating},
+	"quorum 80": {QuorumPercent: 80},
+	"rotating, quorum 90": {
+		RootsOrder:    election.RootsOrderRotating,
+		QuorumPercent: 90,
+	},
+}
+
+// TestElectionStrategies simulates random DAGs with every strategy,
+// and checks that posets decide the same blocks regardless of the events order.
+func TestElectionStrategies(t *testing.T) {
+	logger.SetTestMode(t)
+
+	seeds := int64(10)
+	if testing.Short() {
+		seeds = 2
+	}
+	for name, strategy := range testElectionStrategies {
+		strategy := strategy
+		t.Run(name, func(t *testing.T) {
+			for seed := int64(0); seed < seeds; seed++ {
+				seed := seed
+				t.Run(fmt.Sprintf("seed %d", seed), func(t *testing.T) {
+					assertar := assert.New(t)
+
+					r := rand.New(rand.NewSource(seed))
+					cfg := randFuzzConfig(r)
+					cfg.election = strategy
+
+					gen := genFuzzDAG(r, cfg)
+					if strategy.QuorumPercent == 0 {
+						// greater quorum trades liveness, it may be not reached on the short DAG
+						assertar.NotEmpty(atroposSequence(gen.poset), "no blocks are decided")
+					}
+					if !checkConsensusOrders(t, r, gen, cfg.posets) {
+						dumpFuzzDAG(t, gen.events)
+					}
+				})
+			}
+		})
+	}
+}
+
+// TestElectionStrategiesDiffer checks that custom strategy affects the Atropos choice on the same DAG.
+func TestElectionStrategiesDiffer(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	r := rand.New(rand.NewSource(0))
+	cfg := randFuzzConfig(r)
+	cfg.cheaters = 0
+	gen := genFuzzDAG(r, cfg)
+
+	rotating := newFuzzGenerator(gen.nodes, testElectionStrategies["rotating"])
+	for _, e := range gen.events {
+		if !assertar.NoError(rotating.process(e)) {
+			return
+		}
+	}
+
+	expect := atroposSequence(gen.poset)
+	got := atroposSequence(rotating.poset)
+	assertar.NotEmpty(got)
+	assertar.NotEqual(expect, got)
+}
+
+// TestElectionStrategiesNetworks checks that the default strategy is the only one allowed on mainnet and testnet.
+func TestElectionStrategiesNetworks(t *testing.T) {
+	assertar := assert.New(t)
+
+	for _, net := range []lachesis.Config{
+		lachesis.MainNetConfig(),
+		lachesis.TestNetConfig(),
+		lachesis.FakeNetConfig(genesis.FakeValidators(1, big.NewInt(1), big.NewInt(1))),
+	} {
+		assertar.True(net.Dag.Election.IsDefault(), net.Name)
+		assertar.NoError(net.CheckElection(), net.Name)
+
+		for name, strategy := range testElectionStrategies {
+			net.Dag.Election = strategy
+			err := net.CheckElection()
+			if net.NetworkID == lachesis.FakeNetworkID || strategy.IsDefault() {
+				assertar.NoError(err, "%s: %s", net.Name, name)
+			} else {
+				assertar.Error(err, "%s: %s", net.Name, name)
+			}
+		}
+
+		net.Dag.Election = lachesis.ElectionConfig{RootsOrder: "unknown"}
+		assertar.Error(net.CheckElection(), net.Name)
+		net.Dag.Election = lachesis.ElectionConfig{QuorumPercent: 50}
+		assertar.Error(net.CheckElection(), net.Name)
+	}
+}
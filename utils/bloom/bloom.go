@@ -0,0 +1,87 @@
+// Package bloom implements bloom filter of hashes.
+package bloom
+
+import (
+	"errors"
+	"math"
+
+	"github.com/Fantom-foundation/go-lachesis/common/littleendian"
+)
+
+// KeySize is a min size of the keys. Keys are expected to be hashes,
+// so their last KeySize bytes are used as bits positions without extra hashing.
+const KeySize = 16
+
+var errInvalidFilter = errors.New("invalid bloom filter data")
+
+// Filter is a bloom filter of hashes. It isn't safe for concurrent use.
+type Filter struct {
+	k    uint8 // number of bits per key
+	bits []byte
+}
+
+// New makes filter for n keys with the false positive rate.
+func New(n int, fpRate float64) *Filter {
+	if n < 1 {
+		n = 1
+	}
+	m := math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(n) * math.Ln2)
+	if k < 1 {
+		k = 1
+	}
+
+	return &Filter{
+		k:    uint8(k),
+		bits: make([]byte, (uint64(m)+7)/8),
+	}
+}
+
+// FromBytes restores filter from Bytes().
+func FromBytes(b []byte) (*Filter, error) {
+	if len(b) < 2 || b[0] == 0 {
+		return nil, errInvalidFilter
+	}
+	return &Filter{
+		k:    b[0],
+		bits: append([]byte{}, b[1:]...),
+	}, nil
+}
+
+// Bytes serializes filter.
+func (f *Filter) Bytes() []byte {
+	return append([]byte{f.k}, f.bits...)
+}
+
+// Add key into filter.
+func (f *Filter) Add(key []byte) {
+	f.positions(key, func(i uint64) bool {
+		f.bits[i/8] |= 1 << (i % 8)
+		return true
+	})
+}
+
+// Has returns false if key is definitely not added, and true if it's probably added.
+func (f *Filter) Has(key []byte) bool {
+	has := true
+	f.positions(key, func(i uint64) bool {
+		has = f.bits[i/8]&(1<<(i%8)) != 0
+		return has
+	})
+	return has
+}
+
+// positions of the key bits, by double hashing.
+func (f *Filter) positions(key []byte, onPos func(uint64) bool) {
+	if len(key) < KeySize {
+		panic("key is too short")
+	}
+	h1 := littleendian.BytesToInt64(key[len(key)-8:])
+	h2 := littleendian.BytesToInt64(key[len(key)-16 : len(key)-8])
+	m := uint64(len(f.bits)) * 8
+	for i := uint64(0); i < uint64(f.k); i++ {
+		if !onPos((h1 + i*h2) % m) {
+			return
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package bloom
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	assertar := assert.New(t)
+
+	const (
+		n      = 1000
+		fpRate = 0.01
+	)
+	r := rand.New(rand.NewSource(0))
+	key := func() []byte {
+		b := make([]byte, 32)
+		r.Read(b)
+		return b
+	}
+
+	f := New(n, fpRate)
+	keys := make([][]byte, n)
+	for i := range keys {
+		keys[i] = key()
+		f.Add(keys[i])
+	}
+	for _, k := range keys {
+		assertar.True(f.Has(k))
+	}
+
+	restored, err := FromBytes(f.Bytes())
+	if !assertar.NoError(err) {
+		return
+	}
+	assertar.Equal(f, restored)
+
+	fp := 0
+	const tries = 100000
+	for i := 0; i < tries; i++ {
+		if restored.Has(key()) {
+			fp++
+		}
+	}
+	assertar.InDelta(fpRate, float64(fp)/tries, fpRate/2)
+
+	_, err = FromBytes(nil)
+	assertar.Error(err)
+}
@@ -0,0 +1,112 @@
+// Package migration applies ordered changes of the stores data layout.
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/Fantom-foundation/go-lachesis/common/bigendian"
+	"github.com/Fantom-foundation/go-lachesis/kvdb"
+)
+
+var versionKey = []byte("version")
+
+type (
+	// Migration is a named change of the store data.
+	Migration struct {
+		Name string
+		Exec func() error
+	}
+
+	// Schema is a list of the store migrations in order of applying.
+	// Version of the store data is a number of the applied migrations.
+	Schema struct {
+		Name string
+		// Version is a table to keep version of the store data in.
+		Version kvdb.KeyValueStore
+		// IsEmpty reports that the store has no data yet,
+		// so it's recorded with the last version and migrations aren't needed.
+		IsEmpty func() bool
+
+		Migrations []Migration
+	}
+)
+
+// GetVersion returns version of the store data, or false if version isn't recorded yet.
+func (s *Schema) GetVersion() (ver uint32, ok bool, err error) {
+	buf, err := s.Version.Get(versionKey)
+	if err != nil || buf == nil {
+		return 0, false, err
+	}
+	if len(buf) != 4 {
+		return 0, false, fmt.Errorf("%s: invalid version record %x", s.Name, buf)
+	}
+	return bigendian.BytesToInt32(buf), true, nil
+}
+
+func (s *Schema) setVersion(ver uint32) error {
+	return s.Version.Put(versionKey, bigendian.Int32ToBytes(ver))
+}
+
+// Pending returns migrations which aren't applied yet. Data isn't changed.
+func (s *Schema) Pending() ([]Migration, error) {
+	ver, ok, err := s.GetVersion()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if s.IsEmpty() {
+			return nil, nil
+		}
+		ver = 0 // data is older than versioning
+	}
+	if ver > uint32(len(s.Migrations)) {
+		return nil, fmt.Errorf("%s: data version %d is newer than supported %d, upgrade the node", s.Name, ver, len(s.Migrations))
+	}
+	return s.Migrations[ver:], nil
+}
+
+// Exec applies pending migrations one by one. Every migration is committed
+// together with the new version, so the interrupted one is applied again on the next start.
+func (s *Schema) Exec(commit func(flushID []byte) error) error {
+	pending, err := s.Pending()
+	if err != nil {
+		return err
+	}
+
+	last := uint32(len(s.Migrations))
+	ver, ok, _ := s.GetVersion()
+	if !ok && len(pending) == 0 {
+		// new store
+		return s.commit(last, commit)
+	}
+
+	for _, m := range pending {
+		ver++
+		log.Info("Applying DB migration", "store", s.Name, "migration", m.Name, "version", ver, "last", last)
+		start := time.Now()
+
+		err = m.Exec()
+		if err != nil {
+			return fmt.Errorf("%s: migration '%s' failed: %v", s.Name, m.Name, err)
+		}
+		err = s.commit(ver, commit)
+		if err != nil {
+			return err
+		}
+
+		log.Info("DB migration is applied", "store", s.Name, "migration", m.Name, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	return nil
+}
+
+func (s *Schema) commit(ver uint32, commit func(flushID []byte) error) error {
+	err := s.setVersion(ver)
+	if err != nil {
+		return err
+	}
+	return commit([]byte(fmt.Sprintf("migration-%s-%d", s.Name, ver)))
+}
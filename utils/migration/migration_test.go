@@ -0,0 +1,86 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+)
+
+func TestSchemaExec(t *testing.T) {
+	assertar := assert.New(t)
+
+	db := memorydb.New()
+	applied := make([]string, 0)
+	commits := 0
+	commit := func([]byte) error {
+		commits++
+		return nil
+	}
+	step := func(name string) Migration {
+		return Migration{
+			Name: name,
+			Exec: func() error {
+				applied = append(applied, name)
+				return nil
+			},
+		}
+	}
+
+	empty := true
+	s := &Schema{
+		Name:    "test",
+		Version: db,
+		IsEmpty: func() bool {
+			return empty
+		},
+		Migrations: []Migration{step("1"), step("2")},
+	}
+
+	// new store is marked with the last version
+	pending, err := s.Pending()
+	assertar.NoError(err)
+	assertar.Empty(pending)
+	assertar.NoError(s.Exec(commit))
+	assertar.Empty(applied)
+	assertar.Equal(1, commits)
+	ver, ok, err := s.GetVersion()
+	assertar.NoError(err)
+	assertar.True(ok)
+	assertar.Equal(uint32(2), ver)
+
+	// new migrations are applied
+	s.Migrations = append(s.Migrations, step("3"), step("4"))
+	pending, err = s.Pending()
+	assertar.NoError(err)
+	assertar.Equal(2, len(pending))
+	assertar.NoError(s.Exec(commit))
+	assertar.Equal([]string{"3", "4"}, applied)
+	assertar.Equal(3, commits)
+
+	// failed migration isn't recorded
+	s.Migrations = append(s.Migrations, Migration{
+		Name: "5",
+		Exec: func() error {
+			return errors.New("failed")
+		},
+	})
+	assertar.Error(s.Exec(commit))
+	assertar.Equal(3, commits)
+	ver, _, _ = s.GetVersion()
+	assertar.Equal(uint32(4), ver)
+
+	// data of newer node
+	s.Migrations = s.Migrations[:3]
+	_, err = s.Pending()
+	assertar.Error(err)
+
+	// not versioned store with data applies all the migrations
+	empty = false
+	applied = applied[:0]
+	s.Version = memorydb.New()
+	assertar.NoError(s.Exec(commit))
+	assertar.Equal([]string{"1", "2", "3"}, applied)
+}
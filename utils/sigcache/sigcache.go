@@ -0,0 +1,131 @@
+// Package sigcache is a size-bounded cache of the verified signatures,
+// so the same signature isn't recovered again by different checkers.
+package sigcache
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/hashicorp/golang-lru"
+)
+
+// Cache of the verified (hash, sig, signer) triples. It's safe for concurrent use.
+// Nil Cache is a valid disabled cache.
+type Cache struct {
+	lru *lru.Cache // hash+sig -> signer
+}
+
+// New cache of the size. It returns nil (disabled cache) if size isn't positive.
+func New(size int) *Cache {
+	if size <= 0 {
+		return nil
+	}
+	cache, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+	return &Cache{
+		lru: cache,
+	}
+}
+
+func key(hash common.Hash, sig []byte) string {
+	return string(hash.Bytes()) + string(sig)
+}
+
+// Get returns the signer of the verified signature of the hash.
+func (c *Cache) Get(hash common.Hash, sig []byte) (common.Address, bool) {
+	if c == nil {
+		return common.Address{}, false
+	}
+	signer, ok := c.lru.Get(key(hash, sig))
+	if !ok {
+		return common.Address{}, false
+	}
+	return signer.(common.Address), true
+}
+
+// Add the verified signature of the hash.
+func (c *Cache) Add(hash common.Hash, sig []byte, signer common.Address) {
+	if c == nil {
+		return
+	}
+	c.lru.Add(key(hash, sig), signer)
+}
+
+// Len returns the number of the cached signatures.
+func (c *Cache) Len() int {
+	if c == nil {
+		return 0
+	}
+	return c.lru.Len()
+}
+
+// Recover returns the signer of the [R || S || V] signature of the hash. The recovered signer is cached.
+func (c *Cache) Recover(hash common.Hash, sig []byte) (common.Address, error) {
+	if signer, ok := c.Get(hash, sig); ok {
+		return signer, nil
+	}
+	pk, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	signer := crypto.PubkeyToAddress(*pk)
+	c.Add(hash, sig, signer)
+	return signer, nil
+}
+
+// Signer is a types.Signer, which caches the tx senders.
+// The cache must be shared only by signers of the same rules and chain ID.
+type Signer struct {
+	types.Signer
+	cache *Cache
+}
+
+// NewSigner wraps the signer with the cache. It returns the signer as is if the cache is disabled.
+func NewSigner(signer types.Signer, cache *Cache) types.Signer {
+	if cache == nil {
+		return signer
+	}
+	return &Signer{
+		Signer: signer,
+		cache:  cache,
+	}
+}
+
+// Sender returns the sender address of the transaction.
+func (s *Signer) Sender(tx *types.Transaction) (common.Address, error) {
+	hash := s.Signer.Hash(tx)
+	sig := txSig(tx)
+	if sender, ok := s.cache.Get(hash, sig); ok {
+		return sender, nil
+	}
+	sender, err := s.Signer.Sender(tx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	s.cache.Add(hash, sig, sender)
+	return sender, nil
+}
+
+// Equal returns true if the given signer is the same as the wrapped one.
+// So the sender, cached in tx by the Signer, is used by the wrapped signer as well.
+func (s *Signer) Equal(s2 types.Signer) bool {
+	if cached, ok := s2.(*Signer); ok {
+		s2 = cached.Signer
+	}
+	return s.Signer.Equal(s2)
+}
+
+// txSig returns the raw signature values of the tx, encoded unambiguously.
+func txSig(tx *types.Transaction) []byte {
+	v, r, s := tx.RawSignatureValues()
+	sig, err := rlp.EncodeToBytes([]*big.Int{v, r, s})
+	if err != nil {
+		panic(err)
+	}
+	return sig
+}
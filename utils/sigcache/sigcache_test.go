@@ -0,0 +1,109 @@
+package sigcache
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheRecover(t *testing.T) {
+	assertar := assert.New(t)
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	hash := common.Hash{1}
+	sig, err := crypto.Sign(hash.Bytes(), key)
+	assertar.NoError(err)
+
+	var disabled *Cache
+	signer, err := disabled.Recover(hash, sig)
+	assertar.NoError(err)
+	assertar.Equal(addr, signer)
+	assertar.Equal(0, disabled.Len())
+
+	c := New(2)
+	signer, err = c.Recover(hash, sig)
+	assertar.NoError(err)
+	assertar.Equal(addr, signer)
+	assertar.Equal(1, c.Len())
+
+	signer, ok := c.Get(hash, sig)
+	assertar.True(ok)
+	assertar.Equal(addr, signer)
+
+	// other hash or sig
+	_, ok = c.Get(common.Hash{2}, sig)
+	assertar.False(ok)
+	_, ok = c.Get(hash, sig[:64])
+	assertar.False(ok)
+
+	// invalid sig isn't cached
+	_, err = c.Recover(hash, sig[:64])
+	assertar.Error(err)
+	assertar.Equal(1, c.Len())
+
+	// size is bounded
+	c.Add(common.Hash{2}, sig, addr)
+	c.Add(common.Hash{3}, sig, addr)
+	assertar.Equal(2, c.Len())
+	_, ok = c.Get(hash, sig)
+	assertar.False(ok)
+}
+
+func TestSigner(t *testing.T) {
+	assertar := assert.New(t)
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	plain := types.NewEIP155Signer(big.NewInt(1))
+	cached := NewSigner(plain, New(16))
+
+	assertar.Equal(plain, NewSigner(plain, nil))
+	assertar.True(cached.Equal(plain))
+	assertar.True(cached.Equal(NewSigner(plain, New(16))))
+	assertar.False(cached.Equal(types.NewEIP155Signer(big.NewInt(2))))
+
+	tx, err := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil), plain, key)
+	assertar.NoError(err)
+
+	// a fresh copy of the tx, as if it's received from another peer
+	fresh := func(tx *types.Transaction) *types.Transaction {
+		raw, err := rlp.EncodeToBytes(tx)
+		assertar.NoError(err)
+		cp := new(types.Transaction)
+		assertar.NoError(rlp.DecodeBytes(raw, cp))
+		return cp
+	}
+
+	from, err := types.Sender(cached, fresh(tx))
+	assertar.NoError(err)
+	assertar.Equal(addr, from)
+	assertar.Equal(1, cached.(*Signer).cache.Len())
+
+	// the sender cached in tx by the cached signer is used by the plain signer
+	cp := fresh(tx)
+	_, _ = types.Sender(cached, cp)
+	from, err = types.Sender(plain, cp)
+	assertar.NoError(err)
+	assertar.Equal(addr, from)
+
+	// tampered signature doesn't hit the cache
+	raw, err := rlp.EncodeToBytes(tx)
+	assertar.NoError(err)
+	var fields []rlp.RawValue
+	assertar.NoError(rlp.DecodeBytes(raw, &fields))
+	_, r, _ := tx.RawSignatureValues()
+	fields[7], err = rlp.EncodeToBytes(new(big.Int).Add(r, new(big.Int).Lsh(big.NewInt(1), 256)))
+	assertar.NoError(err)
+	raw, err = rlp.EncodeToBytes(fields)
+	assertar.NoError(err)
+	tampered := new(types.Transaction)
+	assertar.NoError(rlp.DecodeBytes(raw, tampered))
+	_, err = types.Sender(cached, tampered)
+	assertar.Error(err)
+}
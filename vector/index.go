@@ -280,16 +280,33 @@ func (vi *Index) fillEventVectors(e *inter.EventHeaderData) allVecs {
 	nextCreator:
 	}
 
+	// Events, which are observed by the self-parent, are observed by my branch already.
+	// Without forks, branch of an event is its creator, so the self-parent's HighestBefore
+	// is enough to stop the traversal on them, without reading their LowestAfter.
+	var selfParentBeforeSeq HighestBeforeSeq
+	if e.SelfParent() != nil && parentsBranchIDs[0] == meBranchID && !vi.atLeastOneFork() {
+		selfParentBeforeSeq = parentsVecs[0].beforeSeq
+	}
+	walked := hash.EventsSet{}
+
 	// graph traversal starting from e, but excluding e
 	onWalk := func(walk hash.Event) (godeeper bool) {
-		wLowestAfterSeq := vi.GetLowestAfterSeq(walk)
-
-		godeeper = wLowestAfterSeq.Get(meBranchID) == 0
+		if selfParentBeforeSeq != nil {
+			w := vi.getEvent(walk)
+			if w == nil {
+				vi.Log.Crit("Event not found", "event", walk.String())
+			}
+			godeeper = selfParentBeforeSeq.Get(vi.validatorIdxs[w.Creator]).Seq < w.Seq && !walked.Contains(walk)
+			walked.Add(walk)
+		} else {
+			godeeper = vi.GetLowestAfterSeq(walk).Get(meBranchID) == 0
+		}
 		if !godeeper {
 			return
 		}
 
 		// update LowestAfter vector of the old event, because newly-connected event observes it
+		wLowestAfterSeq := vi.GetLowestAfterSeq(walk)
 		wLowestAfterSeq.Set(meBranchID, e.Seq)
 		vi.SetLowestAfter(walk, wLowestAfterSeq)
 
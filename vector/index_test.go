@@ -1,13 +1,20 @@
 package vector
 
 import (
+	"bytes"
+	"fmt"
+	"math/rand"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Fantom-foundation/go-lachesis/common/bigendian"
 	"github.com/Fantom-foundation/go-lachesis/hash"
 	"github.com/Fantom-foundation/go-lachesis/inter"
+	"github.com/Fantom-foundation/go-lachesis/inter/idx"
 	"github.com/Fantom-foundation/go-lachesis/inter/pos"
-
 	"github.com/Fantom-foundation/go-lachesis/kvdb/memorydb"
+	"github.com/Fantom-foundation/go-lachesis/logger"
 )
 
 var (
@@ -66,3 +73,203 @@ func BenchmarkIndex_Add(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkIndex_AddValidators(b *testing.B) {
+	for _, validatorsNum := range []int{100, 500, 1000} {
+		validatorsNum := validatorsNum
+		b.Run(fmt.Sprintf("%d validators", validatorsNum), func(b *testing.B) {
+			benchmarkIndexAdd(b, validatorsNum)
+		})
+	}
+}
+
+// randIndexDAG generates the random DAG for the benchmarks, in the topological order.
+func randIndexDAG(validatorsNum int) (*pos.Validators, []*inter.EventHeaderData, func(hash.Event) *inter.EventHeaderData) {
+	nodes := make([]idx.StakerID, validatorsNum)
+	for i := range nodes {
+		nodes[i] = idx.StakerID(i + 1)
+	}
+	validators := pos.EqualStakeValidators(nodes, 1)
+
+	ordered := make([]*inter.EventHeaderData, 0, validatorsNum*10)
+	events := make(map[hash.Event]*inter.EventHeaderData)
+	getEvent := func(id hash.Event) *inter.EventHeaderData {
+		return events[id]
+	}
+	inter.ForEachRandEvent(nodes, 10, 5, rand.New(rand.NewSource(0)), inter.ForEachEvent{
+		Process: func(e *inter.Event, name string) {
+			events[e.Hash()] = &e.EventHeaderData
+			ordered = append(ordered, &e.EventHeaderData)
+		},
+	})
+	return validators, ordered, getEvent
+}
+
+// benchmarkIndexAdd measures the per-event cost on the random DAG.
+// The first half of the DAG is a warm-up, so the measured events have the filled vectors.
+func benchmarkIndexAdd(b *testing.B, validatorsNum int) {
+	validators, ordered, getEvent := randIndexDAG(validatorsNum)
+	warmUp, measured := ordered[:len(ordered)/2], ordered[len(ordered)/2:]
+
+	vecClock := NewIndex(DefaultIndexConfig(), validators, memorydb.New(), getEvent)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; {
+		b.StopTimer()
+		vecClock.Reset(validators, memorydb.New(), getEvent)
+		for _, e := range warmUp {
+			vecClock.Add(e)
+			vecClock.Flush()
+		}
+		b.StartTimer()
+		for _, e := range measured {
+			vecClock.Add(e)
+			vecClock.Flush()
+			i++
+			if i >= b.N {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkIndex_ForklessCauseValidators(b *testing.B) {
+	for _, validatorsNum := range []int{100, 500, 1000} {
+		validatorsNum := validatorsNum
+		b.Run(fmt.Sprintf("%d validators", validatorsNum), func(b *testing.B) {
+			benchmarkIndexQuery(b, validatorsNum, func(vi *Index, a, b *inter.EventHeaderData) {
+				vi.ForklessCause(a.Hash(), b.Hash())
+			})
+		})
+	}
+}
+
+func BenchmarkIndex_MedianTimeValidators(b *testing.B) {
+	for _, validatorsNum := range []int{100, 500, 1000} {
+		validatorsNum := validatorsNum
+		b.Run(fmt.Sprintf("%d validators", validatorsNum), func(b *testing.B) {
+			benchmarkIndexQuery(b, validatorsNum, func(vi *Index, a, _ *inter.EventHeaderData) {
+				vi.MedianTime(a.Hash(), 0)
+			})
+		})
+	}
+}
+
+// benchmarkIndexQuery measures the per-call cost of the query on the indexed random DAG.
+// The query is called for every event of the second half of the DAG and an event of the first half.
+// The caches are dropped before each pass, so the vectors are read from DB.
+func benchmarkIndexQuery(b *testing.B, validatorsNum int, query func(vi *Index, a, b *inter.EventHeaderData)) {
+	validators, ordered, getEvent := randIndexDAG(validatorsNum)
+	earlier, later := ordered[:len(ordered)/2], ordered[len(ordered)/2:]
+
+	vecClock := NewIndex(DefaultIndexConfig(), validators, memorydb.New(), getEvent)
+	for _, e := range ordered {
+		vecClock.Add(e)
+		vecClock.Flush()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; {
+		b.StopTimer()
+		vecClock.cache.ForklessCause.Purge()
+		vecClock.dropDependentCaches()
+		b.StartTimer()
+		for j, e := range later {
+			query(vecClock, e, earlier[j%len(earlier)])
+			i++
+			if i >= b.N {
+				break
+			}
+		}
+	}
+}
+
+// indexResultsDigests are digests of the Index results on the random DAGs, see indexResultsDigest.
+// Any change of the vectors representation must keep them.
+var indexResultsDigests = map[string]string{
+	"5 validators, 0 cheaters":  "0xc5b03ac75e2d7e79279c035adb8e6c9b7a6b349411d37f495e923b470b5e2b63",
+	"10 validators, 3 cheaters": "0x2a2ffba98f94e4dcbb5e4f28245aec3b95567dee800f4140d50c857d2ec98ed3",
+	"30 validators, 0 cheaters": "0xc6d2cec71e1e93b54942e1392ca7ee7ef4bd31406ddad7410ec6f7d577b726b8",
+	"30 validators, 9 cheaters": "0x9658b2ceb7f08b645c8e5979c4e60e9dff5667e3497bbebdbb71dbe36d03ee29",
+}
+
+// TestIndexResultsDigest checks that ForklessCause, MedianTime and the vectors are the same
+// as calculated by the reference implementation, on the random DAGs with forks.
+func TestIndexResultsDigest(t *testing.T) {
+	logger.SetTestMode(t)
+	assertar := assert.New(t)
+
+	for _, test := range []struct {
+		validators int
+		cheaters   int
+	}{
+		{5, 0},
+		{10, 3},
+		{30, 0},
+		{30, 9},
+	} {
+		name := fmt.Sprintf("%d validators, %d cheaters", test.validators, test.cheaters)
+		got := indexResultsDigest(test.validators, test.cheaters)
+		assertar.Equal(indexResultsDigests[name], got, name)
+	}
+}
+
+func indexResultsDigest(validatorsNum, cheatersNum int) string {
+	r := rand.New(rand.NewSource(int64(validatorsNum + cheatersNum)))
+
+	nodes := make([]idx.StakerID, validatorsNum)
+	builder := pos.NewBuilder()
+	for i := range nodes {
+		nodes[i] = idx.StakerID(i + 1)
+		builder.Set(nodes[i], pos.Stake(1+i%3))
+	}
+	validators := builder.Build()
+
+	ordered := make([]*inter.EventHeaderData, 0, validatorsNum*10)
+	events := make(map[hash.Event]*inter.EventHeaderData)
+	getEvent := func(id hash.Event) *inter.EventHeaderData {
+		return events[id]
+	}
+	inter.ForEachRandFork(nodes, nodes[:cheatersNum], 10, 4, 3, r, inter.ForEachEvent{
+		Process: func(e *inter.Event, name string) {
+			if events[e.Hash()] != nil {
+				return // identical fork
+			}
+			events[e.Hash()] = &e.EventHeaderData
+			ordered = append(ordered, &e.EventHeaderData)
+		},
+		Build: func(e *inter.Event, name string) *inter.Event {
+			e.ClaimedTime = inter.Timestamp(e.Lamport*100 + idx.Lamport(r.Intn(100)))
+			return e
+		},
+	})
+
+	vi := NewIndex(DefaultIndexConfig(), validators, memorydb.New(), getEvent)
+	for _, e := range ordered {
+		vi.Add(e)
+		vi.Flush()
+	}
+
+	buf := bytes.Buffer{}
+	branches := idx.Validator(len(vi.bi.BranchIDCreatorIdxs))
+	for _, a := range ordered {
+		buf.Write(vi.GetHighestBeforeSeq(a.Hash()))
+		buf.Write(vi.GetHighestBeforeTime(a.Hash()))
+		buf.Write(vi.GetHighestBeforeAllBranches(a.Hash()))
+		after := vi.GetLowestAfterSeq(a.Hash())
+		for branchID := idx.Validator(0); branchID < branches; branchID++ {
+			buf.Write(after.Get(branchID).Bytes())
+		}
+		buf.Write(bigendian.Int64ToBytes(uint64(vi.MedianTime(a.Hash(), 50))))
+		for _, b := range ordered {
+			if vi.ForklessCause(a.Hash(), b.Hash()) {
+				buf.WriteByte(1)
+			} else {
+				buf.WriteByte(0)
+			}
+		}
+	}
+	return hash.Of(buf.Bytes()).Hex()
+}